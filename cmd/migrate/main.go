@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"diary-backend/internal/config"
+	"diary-backend/internal/database"
+	"diary-backend/internal/migrate"
+	"diary-backend/migrations"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const usage = `Usage: migrate [-dir migrations] <command>
+
+Commands:
+  up [n]         apply all pending migrations, or the next n
+  down [n]       roll back the last migration, or the last n
+  status         list migrations and when they were applied
+  baseline <v>   mark migrations up to version v as applied without running
+                 them, for databases set up by hand before the runner
+  create <name>  write empty up and down files for a new migration to -dir
+`
+
+func main() {
+	dir := flag.String("dir", "migrations", "directory new migrations are created in")
+	flag.Usage = func() { fmt.Fprint(flag.CommandLine.Output(), usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Creating files needs no database
+	if args[0] == "create" {
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		up, down, err := migrate.Create(*dir, args[1])
+		if err != nil {
+			log.Fatal("Failed to create migration:", err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", up, down)
+		return
+	}
+
+	var version int64
+	if args[0] == "baseline" {
+		if len(args) != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		v, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || v < 1 {
+			log.Fatalf("invalid version %q: must be a positive number", args[1])
+		}
+		version = v
+		args = args[:1]
+	}
+
+	steps, err := stepsArg(args)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cfg, err := config.LoadDatabase()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+	if err := database.Connect(cfg); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	migrator, err := migrate.New(database.GetDB(), migrations.FS)
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx, steps)
+		for _, m := range applied {
+			fmt.Printf("Applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("Failed to migrate database:", err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("No pending migrations")
+		}
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		for _, m := range rolledBack {
+			fmt.Printf("Rolled back %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal("Failed to roll back database:", err)
+		}
+		if len(rolledBack) == 0 {
+			fmt.Println("No applied migrations")
+		}
+	case "baseline":
+		recorded, err := migrator.Baseline(ctx, version)
+		if err != nil {
+			log.Fatal("Failed to baseline database:", err)
+		}
+		for _, m := range recorded {
+			fmt.Printf("Marked %03d_%s as applied\n", m.Version, m.Name)
+		}
+		if len(recorded) == 0 {
+			fmt.Println("No migrations to mark")
+		}
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal("Failed to read migration status:", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range statuses {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format(time.RFC3339)
+			}
+			if s.Missing {
+				applied += " (files missing)"
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		w.Flush()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// stepsArg reads the optional step count after up or down; 0 means none was given
+func stepsArg(args []string) (int, error) {
+	if len(args) < 2 {
+		return 0, nil
+	}
+	if len(args) > 2 || args[0] == "status" {
+		return 0, fmt.Errorf("too many arguments for %s", args[0])
+	}
+	steps, err := strconv.Atoi(args[1])
+	if err != nil || steps < 1 {
+		return 0, fmt.Errorf("invalid step count %q: must be a positive number", args[1])
+	}
+	return steps, nil
+}
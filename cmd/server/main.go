@@ -1,46 +1,159 @@
 package main
 
 import (
+	"context"
+	"diary-backend/internal/auth"
 	"diary-backend/internal/config"
 	"diary-backend/internal/database"
+	"diary-backend/internal/handlers"
+	"diary-backend/internal/logging"
+	"diary-backend/internal/middleware"
+	"diary-backend/internal/migrate"
+	"diary-backend/internal/repository"
 	"diary-backend/internal/routes"
-	"log"
+	"diary-backend/migrations"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 func main() {
+	// SIGINT and SIGTERM start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Log JSON from the start; the configured level and format apply once loaded
+	slog.SetDefault(logging.New(os.Stdout, "json", slog.LevelInfo))
+
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load configuration:", err)
+		fatal("Failed to load configuration", err)
 	}
+	level, _ := logging.ParseLevel(cfg.Log.Level)
+	logger := logging.New(os.Stdout, cfg.Log.Format, level)
+	slog.SetDefault(logger)
 
 	// Connect to database
 	if err := database.Connect(&cfg.Database); err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		fatal("Failed to connect to database", err)
+	}
+
+	migrator, err := migrate.New(database.GetDB(), migrations.FS)
+	if err != nil {
+		fatal("Failed to load migrations", err)
+	}
+
+	// Bring the schema up to date before serving when asked to
+	if cfg.Database.AutoMigrate {
+		applied, err := migrator.Up(ctx, 0)
+		for _, m := range applied {
+			logger.Info("Applied migration", slog.Int64("version", m.Version), slog.String("name", m.Name))
+		}
+		if err != nil {
+			fatal("Failed to migrate database", err)
+		}
+	}
+
+	// Let the bootstrap user log in once credentials are configured
+	if cfg.Auth.BootstrapEmail != "" {
+		hash, err := auth.HashPassword(cfg.Auth.BootstrapPassword)
+		if err != nil {
+			fatal("Failed to hash bootstrap password", err)
+		}
+		claimed, err := database.ClaimBootstrapUser(ctx, cfg.Auth.BootstrapEmail, hash)
+		if err != nil {
+			fatal("Failed to claim bootstrap user", err)
+		}
+		if claimed {
+			logger.Info("Bootstrap user claimed", slog.String("email", cfg.Auth.BootstrapEmail))
+		}
+	}
+
+	// Purge expired trash in the background
+	if cfg.Trash.RetentionDays > 0 {
+		retention := time.Duration(cfg.Trash.RetentionDays) * 24 * time.Hour
+		go database.StartTrashPurger(ctx, retention, time.Hour)
 	}
 
 	// Set Gin mode
 	gin.SetMode(gin.ReleaseMode)
 
-	// Create Gin router
-	router := gin.Default()
+	// Create Gin router; every request gets an ID and one log line
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Logger(logger), middleware.Recovery(logger))
 
 	// Setup routes
-	routes.SetupRoutes(router, cfg.CORS.AllowedOrigins)
+	issuer := auth.NewTokenIssuer(cfg.Auth.JWTSecret, cfg.Auth.AccessTokenTTL, cfg.Auth.RefreshTokenTTL)
+	health := handlers.NewHealthHandler(map[string]handlers.ReadinessCheck{
+		"database":   database.Ping,
+		"migrations": migrationsCurrent(migrator),
+	})
+	routes.SetupRoutes(router, cfg.CORS.AllowedOrigins, issuer, repository.NewGorm(database.GetDB()), health)
 
 	// Start server
 	serverAddr := cfg.Server.Host + ":" + cfg.Server.Port
-	log.Printf("Starting server on %s", serverAddr)
+	logger.Info("Starting server", slog.String("addr", serverAddr))
 
 	server := &http.Server{
-		Addr:    serverAddr,
-		Handler: router,
+		Addr:              serverAddr,
+		Handler:           router,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			fatal("Failed to start server", err)
+		}
+	case <-ctx.Done():
+		stop()
+		logger.Info("Shutting down, draining in-flight requests", slog.String("timeout", cfg.Server.ShutdownTimeout.String()))
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Failed to drain requests", slog.String("error", err.Error()))
+		}
 	}
 
-	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatal("Failed to start server:", err)
+	if err := database.Close(); err != nil {
+		logger.Error("Failed to close database", slog.String("error", err.Error()))
+	}
+	logger.Info("Server stopped")
+}
+
+// fatal logs an error that keeps the server from running and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.String("error", err.Error()))
+	os.Exit(1)
+}
+
+// migrationsCurrent is a readiness check that fails while migrations are pending
+func migrationsCurrent(migrator *migrate.Migrator) handlers.ReadinessCheck {
+	return func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("%d pending migrations, next is %03d_%s", len(pending), pending[0].Version, pending[0].Name)
+		}
+		return nil
 	}
 }
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// PersonalAccessTokenPrefix marks personal access tokens so they can be told
+// apart from JWTs and spotted by secret scanners
+const PersonalAccessTokenPrefix = "dpat_"
+
+// Scopes a personal access token can be granted. A write scope includes
+// the matching read scope.
+const (
+	ScopeTasksRead         = "tasks:read"
+	ScopeTasksWrite        = "tasks:write"
+	ScopeProjectsRead      = "projects:read"
+	ScopeProjectsWrite     = "projects:write"
+	ScopeResourcesRead     = "resources:read"
+	ScopeResourcesWrite    = "resources:write"
+	ScopeGoalsRead         = "goals:read"
+	ScopeGoalsWrite        = "goals:write"
+	ScopeTechnologiesRead  = "technologies:read"
+	ScopeTechnologiesWrite = "technologies:write"
+	ScopeActivityRead      = "activity:read"
+	ScopeViewsRead         = "views:read"
+	ScopeViewsWrite        = "views:write"
+)
+
+// Scopes lists every scope that can be granted
+var Scopes = []string{
+	ScopeTasksRead, ScopeTasksWrite,
+	ScopeProjectsRead, ScopeProjectsWrite,
+	ScopeResourcesRead, ScopeResourcesWrite,
+	ScopeGoalsRead, ScopeGoalsWrite,
+	ScopeTechnologiesRead, ScopeTechnologiesWrite,
+	ScopeActivityRead,
+	ScopeViewsRead, ScopeViewsWrite,
+}
+
+// ValidScope reports whether scope can be granted
+func ValidScope(scope string) bool {
+	for _, known := range Scopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether the granted scopes allow required
+func HasScope(granted []string, required string) bool {
+	resource, access, _ := strings.Cut(required, ":")
+	for _, scope := range granted {
+		if scope == required || (access == "read" && scope == resource+":write") {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPersonalAccessToken reports whether a bearer token is a personal access token
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
+
+// NewPersonalAccessToken generates a token, the hash it is stored under and a
+// short prefix that identifies it in listings
+func NewPersonalAccessToken() (token, hash, prefix string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	token = PersonalAccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashPersonalAccessToken(token), token[:len(PersonalAccessTokenPrefix)+6], nil
+}
+
+// HashPersonalAccessToken returns the SHA-256 hash a token is stored and looked up by.
+// The tokens are random, so an unsalted fast hash is enough.
+func HashPersonalAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	// AccessToken authenticates API requests
+	AccessToken = "access"
+	// RefreshToken can only be exchanged for a new token pair
+	RefreshToken = "refresh"
+
+	issuer = "diary-backend"
+)
+
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the JWT claims of both token kinds. The subject is the user ID
+// and the JWT ID identifies refresh tokens so they can be revoked.
+type Claims struct {
+	Kind string `json:"kind"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the user the token was issued to
+func (c *Claims) UserID() (uuid.UUID, error) {
+	return uuid.Parse(c.Subject)
+}
+
+// TokenPair is what login and refresh hand out
+type TokenPair struct {
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresIn        int64     `json:"expires_in"` // seconds until the access token expires
+	RefreshID        uuid.UUID `json:"-"`
+	RefreshExpiresAt time.Time `json:"-"`
+}
+
+// TokenIssuer signs and verifies HS256 tokens
+type TokenIssuer struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+}
+
+// NewTokenIssuer creates an issuer for the given signing secret and token lifetimes
+func NewTokenIssuer(secret string, accessTTL, refreshTTL time.Duration) *TokenIssuer {
+	return &TokenIssuer{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+	}
+}
+
+// Issue creates a new access and refresh token for a user
+func (i *TokenIssuer) Issue(userID uuid.UUID) (*TokenPair, error) {
+	now := time.Now()
+
+	access, err := i.sign(Claims{
+		Kind: AccessToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(i.accessTTL)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	refreshID := uuid.New()
+	refreshExpiresAt := now.Add(i.refreshTTL)
+	refresh, err := i.sign(Claims{
+		Kind: RefreshToken,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			ID:        refreshID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:      access,
+		RefreshToken:     refresh,
+		TokenType:        "Bearer",
+		ExpiresIn:        int64(i.accessTTL.Seconds()),
+		RefreshID:        refreshID,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func (i *TokenIssuer) sign(claims Claims) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(i.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
+
+// Parse verifies a token and makes sure it is of the expected kind
+func (i *TokenIssuer) Parse(token, kind string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return i.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Kind != kind {
+		return nil, ErrInvalidToken
+	}
+	if _, err := claims.UserID(); err != nil {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
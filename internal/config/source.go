@@ -0,0 +1,148 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// source looks settings up in the environment first and then in the optional
+// config file, collecting every invalid value instead of stopping at the first
+type source struct {
+	file map[string]string // config file settings by dotted key, e.g. database.host
+	read map[string]bool   // keys looked up so far
+	errs problems
+}
+
+// newSource reads the config file named by CONFIG_FILE, if any. YAML and TOML
+// are told apart by the file extension.
+func newSource() (*source, error) {
+	s := &source{file: map[string]string{}, read: map[string]bool{}}
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var tree map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		err = toml.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s: extension must be .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	flatten("", tree, s.file)
+	return s, nil
+}
+
+// flatten stores the scalar values of a nested config file by dotted key.
+// Lists become comma-separated values, like the environment variables.
+func flatten(prefix string, tree map[string]interface{}, into map[string]string) {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch value := value.(type) {
+		case map[string]interface{}:
+			flatten(key, value, into)
+		case []interface{}:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			into[key] = strings.Join(items, ",")
+		case nil:
+		default:
+			into[key] = fmt.Sprint(value)
+		}
+	}
+}
+
+// get returns the value of a setting: the environment variable when set,
+// otherwise the config file entry, otherwise the default
+func (s *source) get(key, env, defaultValue string) string {
+	s.read[key] = true
+	if value := os.Getenv(env); value != "" {
+		return value
+	}
+	if value, ok := s.file[key]; ok {
+		return value
+	}
+	return defaultValue
+}
+
+// invalid records a value that could not be parsed
+func (s *source) invalid(key, env, format string, args ...interface{}) {
+	s.errs.add(env, key, format, args...)
+}
+
+func (s *source) string(key, env, defaultValue string) string {
+	return s.get(key, env, defaultValue)
+}
+
+func (s *source) int(key, env, defaultValue string) int {
+	value := s.get(key, env, defaultValue)
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		s.invalid(key, env, "%q is not a whole number", value)
+	}
+	return n
+}
+
+func (s *source) bool(key, env, defaultValue string) bool {
+	value := s.get(key, env, defaultValue)
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		s.invalid(key, env, "%q is not true or false", value)
+	}
+	return b
+}
+
+func (s *source) duration(key, env, defaultValue string) time.Duration {
+	value := s.get(key, env, defaultValue)
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		s.invalid(key, env, "%q is not a duration such as 15s or 30m", value)
+	}
+	return d
+}
+
+// checkUnknown reports config file entries that no setting read, which are
+// usually typos. With sections given only entries under them are checked.
+func (s *source) checkUnknown(sections ...string) {
+	var unknown []string
+	for key := range s.file {
+		if s.read[key] {
+			continue
+		}
+		if len(sections) == 0 {
+			unknown = append(unknown, key)
+		}
+		for _, section := range sections {
+			if strings.HasPrefix(key, section+".") {
+				unknown = append(unknown, key)
+				break
+			}
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		s.errs = append(s.errs, fmt.Errorf("config file: unknown setting %s", key))
+	}
+}
@@ -0,0 +1,28 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"diary-backend/internal/models"
+)
+
+// AuthenticatePersonalAccessToken looks up an unexpired token by its hash and
+// records that it was used. last_used_at is written at most once a minute.
+func AuthenticatePersonalAccessToken(ctx context.Context, hash string) (*models.PersonalAccessToken, error) {
+	db := GetDB().WithContext(ctx)
+
+	var token models.PersonalAccessToken
+	err := db.Where("token_hash = ? AND (expires_at IS NULL OR expires_at > ?)", hash, time.Now()).
+		First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > time.Minute {
+		db.Model(&token).UpdateColumn("last_used_at", now)
+		token.LastUsedAt = &now
+	}
+	return &token, nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"diary-backend/internal/models"
+)
+
+// PurgeTrash permanently deletes tasks and resources that were moved to the
+// trash before cutoff
+func PurgeTrash(ctx context.Context, cutoff time.Time) (tasks int64, resources int64, err error) {
+	db := GetDB().WithContext(ctx).Unscoped()
+
+	result := db.Where("deleted_at < ?", cutoff).Delete(&models.Task{})
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+	tasks = result.RowsAffected
+
+	result = db.Where("deleted_at < ?", cutoff).Delete(&models.Resource{})
+	if result.Error != nil {
+		return tasks, 0, result.Error
+	}
+	return tasks, result.RowsAffected, nil
+}
+
+// StartTrashPurger purges trash older than retention right away and then on
+// every interval, until ctx is cancelled
+func StartTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		tasks, resources, err := PurgeTrash(ctx, time.Now().Add(-retention))
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to purge trash", slog.String("error", err.Error()))
+		} else if tasks > 0 || resources > 0 {
+			slog.InfoContext(ctx, "Purged trash", slog.Int64("tasks", tasks), slog.Int64("resources", resources))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
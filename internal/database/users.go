@@ -0,0 +1,21 @@
+package database
+
+import (
+	"context"
+	"strings"
+
+	"diary-backend/internal/models"
+)
+
+// ClaimBootstrapUser gives the bootstrap user an email and password so the
+// data created before accounts existed can be logged into. It only applies
+// while the bootstrap user has no password yet, and reports whether it did.
+func ClaimBootstrapUser(ctx context.Context, email, passwordHash string) (bool, error) {
+	result := GetDB().WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND password_hash = ''", models.BootstrapUserID).
+		Updates(map[string]interface{}{
+			"email":         strings.ToLower(strings.TrimSpace(email)),
+			"password_hash": passwordHash,
+		})
+	return result.RowsAffected > 0, result.Error
+}
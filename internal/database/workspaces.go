@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreatePersonalWorkspace creates a user's personal workspace with the user as its owner
+func CreatePersonalWorkspace(tx *gorm.DB, userID uuid.UUID) (*models.Workspace, error) {
+	workspace := models.Workspace{
+		ID:        uuid.New(),
+		Name:      "Personal",
+		Personal:  true,
+		CreatedBy: userID,
+	}
+	if err := tx.Create(&workspace).Error; err != nil {
+		return nil, err
+	}
+	member := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: userID, Role: models.RoleOwner}
+	if err := tx.Create(&member).Error; err != nil {
+		return nil, err
+	}
+	return &workspace, nil
+}
+
+// PersonalWorkspaceID returns the ID of a user's personal workspace
+func PersonalWorkspaceID(ctx context.Context, userID uuid.UUID) (uuid.UUID, error) {
+	var workspace models.Workspace
+	err := GetDB().WithContext(ctx).Select("id").
+		Where("personal AND created_by = ?", userID).
+		First(&workspace).Error
+	return workspace.ID, err
+}
+
+// WorkspaceRole returns the role a user has in a workspace, or
+// gorm.ErrRecordNotFound when the user is not a member
+func WorkspaceRole(ctx context.Context, workspaceID, userID uuid.UUID) (string, error) {
+	var member models.WorkspaceMember
+	err := GetDB().WithContext(ctx).
+		Where("workspace_id = ? AND user_id = ?", workspaceID, userID).
+		First(&member).Error
+	return member.Role, err
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"diary-backend/internal/database"
+	"diary-backend/internal/history"
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// dbFor returns the database bound to the request's context, so that queries
+// stop when the client goes away and their log lines carry the request ID
+func dbFor(c *gin.Context) *gorm.DB {
+	return database.GetDB().WithContext(c.Request.Context())
+}
+
+// recordActivity appends an event for a change to an entity of the current
+// workspace. Pass the entity as it was before and after the change; updates
+// that changed nothing are not recorded.
+func recordActivity(tx *gorm.DB, c *gin.Context, action, entityType string, entityID uuid.UUID, before, after interface{}) error {
+	return recordWorkspaceActivity(tx, c, currentWorkspaceID(c), action, entityType, entityID, before, after)
+}
+
+// recordWorkspaceActivity is recordActivity for routes outside the workspace
+// selected by the request, such as workspace management
+func recordWorkspaceActivity(tx *gorm.DB, c *gin.Context, workspaceID uuid.UUID, action, entityType string, entityID uuid.UUID, before, after interface{}) error {
+	return history.Record(tx, workspaceID, currentUserID(c), action, entityType, entityID, before, after)
+}
+
+// listActivity writes one page of events matching query, newest first
+func listActivity(c *gin.Context, query *gorm.DB) {
+	page, limit, ok := listPagination(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+
+	var events []models.ActivityEvent
+	err := query.Order("activity_events.created_at DESC, activity_events.id DESC").
+		Offset((page - 1) * limit).Limit(limit).
+		Scan(&events).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+
+	writeActivity(c, events, page, limit, total)
+}
+
+// writeActivity writes a page of events with its position in the list
+func writeActivity(c *gin.Context, events []models.ActivityEvent, page, limit int, total int64) {
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// repositoryHistory lists the events of the entity in the path from a
+// repository's History. Deleted entities keep their history.
+func repositoryHistory(c *gin.Context, list func(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error)) {
+	entityUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	page, limit, ok := listPagination(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+
+	events, total, err := list(c.Request.Context(), currentWorkspaceID(c), entityUUID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		return
+	}
+
+	writeActivity(c, events, page, limit, total)
+}
+
+// GetActivity lists the workspace's activity, newest first. It can be filtered
+// by entity_type, entity_id, action, actor_id and a since/until time range (RFC 3339).
+func GetActivity(c *gin.Context) {
+	db := dbFor(c)
+	query := repository.ActivityEvents(db, currentWorkspaceID(c))
+
+	if entityType := c.Query("entity_type"); entityType != "" {
+		query = query.Where("activity_events.entity_type = ?", entityType)
+	}
+	if entityID := c.Query("entity_id"); entityID != "" {
+		id, err := uuid.Parse(entityID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity_id"})
+			return
+		}
+		query = query.Where("activity_events.entity_id = ?", id)
+	}
+	if action := c.Query("action"); action != "" {
+		query = query.Where("activity_events.action = ?", action)
+	}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		id, err := uuid.Parse(actorID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid actor_id"})
+			return
+		}
+		query = query.Where("activity_events.actor_id = ?", id)
+	}
+	for param, op := range map[string]string{"since": ">=", "until": "<"} {
+		if value := c.Query(param); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + ", expected RFC 3339"})
+				return
+			}
+			query = query.Where("activity_events.created_at "+op+" ?", t)
+		}
+	}
+
+	listActivity(c, query)
+}
+
+// entityHistory lists the events of one entity. Deleted entities keep their history.
+func entityHistory(c *gin.Context, entityType string) {
+	entityUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	db := dbFor(c)
+	listActivity(c, repository.ActivityEvents(db, currentWorkspaceID(c)).
+		Where("activity_events.entity_type = ? AND activity_events.entity_id = ?", entityType, entityUUID))
+}
+
+// GetTaskHistory lists the changes made to a task
+func (h *TaskHandler) GetTaskHistory(c *gin.Context) {
+	repositoryHistory(c, h.tasks.History)
+}
+
+// GetProjectHistory lists the changes made to a project
+func GetProjectHistory(c *gin.Context) {
+	entityHistory(c, models.EntityProject)
+}
+
+// GetResourceHistory lists the changes made to a resource
+func (h *ResourceHandler) GetResourceHistory(c *gin.Context) {
+	repositoryHistory(c, h.resources.History)
+}
+
+// GetGoalHistory lists the changes made to a goal
+func GetGoalHistory(c *gin.Context) {
+	entityHistory(c, models.EntityGoal)
+}
+
+// GetTechnologyHistory lists the changes made to a technology
+func GetTechnologyHistory(c *gin.Context) {
+	entityHistory(c, models.EntityTechnology)
+}
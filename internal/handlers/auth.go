@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"diary-backend/internal/auth"
+	"diary-backend/internal/database"
+	"diary-backend/internal/middleware"
+	"diary-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var errRefreshTokenReused = errors.New("refresh token has already been used")
+
+// tokenIssuer signs the tokens handed out by Register, Login and RefreshToken
+var tokenIssuer *auth.TokenIssuer
+
+// SetTokenIssuer sets the issuer used by the auth handlers
+func SetTokenIssuer(issuer *auth.TokenIssuer) {
+	tokenIssuer = issuer
+}
+
+// currentUserID returns the authenticated user set by middleware.RequireAuth
+func currentUserID(c *gin.Context) uuid.UUID {
+	return c.MustGet(middleware.UserIDKey).(uuid.UUID)
+}
+
+// issueTokens creates a token pair for a user and records its refresh token
+func issueTokens(tx *gorm.DB, userID uuid.UUID) (*auth.TokenPair, error) {
+	pair, err := tokenIssuer.Issue(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.RefreshTokenRecord{
+		ID:        pair.RefreshID,
+		UserID:    userID,
+		ExpiresAt: pair.RefreshExpiresAt,
+	}
+	if err := tx.Create(&record).Error; err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// Register creates a user account with its personal workspace and logs it in
+func Register(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create account"})
+		return
+	}
+
+	user := models.User{
+		ID:           uuid.New(),
+		Email:        strings.ToLower(strings.TrimSpace(req.Email)),
+		PasswordHash: hash,
+		Name:         strings.TrimSpace(req.Name),
+	}
+
+	db := dbFor(c)
+	var tokens *auth.TokenPair
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var existing int64
+		if err := tx.Model(&models.User{}).Where("LOWER(email) = ?", user.Email).Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			return gorm.ErrDuplicatedKey
+		}
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if _, err := database.CreatePersonalWorkspace(tx, user.ID); err != nil {
+			return err
+		}
+		tokens, err = issueTokens(tx, user.ID)
+		return err
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		c.JSON(http.StatusConflict, gin.H{"error": "An account with this email already exists"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create account"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"user":   user,
+		"tokens": tokens,
+	})
+}
+
+// Login exchanges an email and password for a token pair
+func Login(c *gin.Context) {
+	var req models.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := dbFor(c)
+	var user models.User
+	err := db.Where("LOWER(email) = ?", strings.ToLower(strings.TrimSpace(req.Email))).First(&user).Error
+	if err != nil || !auth.CheckPassword(user.PasswordHash, req.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid email or password"})
+		return
+	}
+
+	tokens, err := issueTokens(db, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log in"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":   user,
+		"tokens": tokens,
+	})
+}
+
+// RefreshToken exchanges a refresh token for a new token pair. Each refresh
+// token works once; presenting a used one again revokes all of the user's
+// sessions, since it means the token was copied.
+func RefreshToken(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := tokenIssuer.Parse(req.RefreshToken, auth.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+	userID, _ := claims.UserID()
+	tokenID, err := uuid.Parse(claims.ID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	db := dbFor(c)
+	var tokens *auth.TokenPair
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Revoking only an unrevoked token lets one of two concurrent
+		// refreshes with the same token through; the other sees it reused
+		result := tx.Model(&models.RefreshTokenRecord{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", tokenID, userID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var record models.RefreshTokenRecord
+			if err := tx.First(&record, "id = ? AND user_id = ?", tokenID, userID).Error; err != nil {
+				return err
+			}
+			return errRefreshTokenReused
+		}
+		var err error
+		tokens, err = issueTokens(tx, userID)
+		return err
+	})
+	if errors.Is(err, errRefreshTokenReused) {
+		db.Model(&models.RefreshTokenRecord{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Update("revoked_at", time.Now())
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || errors.Is(err, errRefreshTokenReused) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// Logout revokes a refresh token. Access tokens stay valid until they expire.
+func Logout(c *gin.Context) {
+	var req models.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := tokenIssuer.Parse(req.RefreshToken, auth.RefreshToken)
+	if err == nil {
+		userID, _ := claims.UserID()
+		db := dbFor(c)
+		db.Model(&models.RefreshTokenRecord{}).
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", claims.ID, userID).
+			Update("revoked_at", time.Now())
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
+}
+
+// GetCurrentUser returns the authenticated user
+func GetCurrentUser(c *gin.Context) {
+	db := dbFor(c)
+	var user models.User
+	if err := db.First(&user, "id = ?", currentUserID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"user": user})
+}
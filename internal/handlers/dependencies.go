@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetTaskDependencies lists the tasks blocking a task and the tasks it blocks
+func (h *TaskHandler) GetTaskDependencies(c *gin.Context) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	blockedBy, blocking, err := h.tasks.Dependencies(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch dependencies"})
+		return
+	}
+
+	blocked := false
+	for _, blocker := range blockedBy {
+		if !blocker.Completed && blocker.Status != "cancelled" {
+			blocked = true
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"blockedBy": blockedBy,
+		"blocking":  blocking,
+		"blocked":   blocked,
+	})
+}
+
+// CreateTaskDependency marks the task in the path as blocked by another task,
+// rejecting links that would create a cycle
+func (h *TaskHandler) CreateTaskDependency(c *gin.Context) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTaskDependencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	dependency := models.TaskDependency{TaskID: task.ID, DependsOnID: req.DependsOnID}
+	err := h.tasks.CreateDependency(c.Request.Context(), currentActor(c), &dependency)
+	switch {
+	case errors.Is(err, repository.ErrSelfDependency), errors.Is(err, repository.ErrDependencyCycle):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dependsOnId: task does not exist"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create dependency"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"dependency": dependency})
+}
+
+// DeleteTaskDependency removes a blocker from a task
+func (h *TaskHandler) DeleteTaskDependency(c *gin.Context) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	dependsOnUUID, err := uuid.Parse(c.Param("dependsOnId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dependency ID format"})
+		return
+	}
+
+	dependency := models.TaskDependency{TaskID: task.ID, DependsOnID: dependsOnUUID}
+	err = h.tasks.DeleteDependency(c.Request.Context(), currentActor(c), dependency)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dependency not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete dependency"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Dependency deleted successfully"})
+}
+
+// GetProjectGraph returns the dependency graph of a project's tasks with the
+// tasks in topological order: every task comes after the tasks blocking it
+func (h *TaskHandler) GetProjectGraph(c *gin.Context) {
+	projectUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	project, err := h.tasks.Project(ctx, currentWorkspaceID(c), projectUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	tasks, edges, err := h.tasks.ProjectGraph(ctx, project.WorkspaceID, project.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project graph"})
+		return
+	}
+
+	order, ok := topologicalOrder(tasks, edges)
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "Dependency graph contains a cycle"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project": project,
+		"tasks":   order,
+		"edges":   edges,
+	})
+}
+
+// topologicalOrder sorts tasks with Kahn's algorithm, keeping the incoming
+// (due date) order among tasks that are ready at the same time. It reports
+// false if the edges contain a cycle.
+func topologicalOrder(tasks []models.Task, edges []models.TaskDependency) ([]models.Task, bool) {
+	position := make(map[uuid.UUID]int, len(tasks))
+	for i, task := range tasks {
+		position[task.ID] = i
+	}
+
+	inDegree := make(map[uuid.UUID]int, len(tasks))
+	dependents := make(map[uuid.UUID][]uuid.UUID)
+	for _, edge := range edges {
+		inDegree[edge.TaskID]++
+		dependents[edge.DependsOnID] = append(dependents[edge.DependsOnID], edge.TaskID)
+	}
+
+	var ready []int
+	for i, task := range tasks {
+		if inDegree[task.ID] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	order := make([]models.Task, 0, len(tasks))
+	for len(ready) > 0 {
+		sort.Ints(ready)
+		current := tasks[ready[0]]
+		ready = ready[1:]
+		order = append(order, current)
+
+		for _, dependent := range dependents[current.ID] {
+			inDegree[dependent]--
+			if inDegree[dependent] == 0 {
+				ready = append(ready, position[dependent])
+			}
+		}
+	}
+
+	return order, len(order) == len(tasks)
+}
@@ -0,0 +1,443 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errUnknownResource   = errors.New("one or more resources do not exist")
+	errResourceNotLinked = errors.New("resource is not linked to this goal")
+)
+
+// linkGoalResources links the given resources of the goal's user to a goal,
+// ignoring links that already exist
+func linkGoalResources(tx *gorm.DB, goal models.Goal, resourceIDs []uuid.UUID) error {
+	if len(resourceIDs) == 0 {
+		return nil
+	}
+
+	var found int64
+	err := tx.Model(&models.Resource{}).Where("id IN ? AND workspace_id = ?", resourceIDs, goal.WorkspaceID).Count(&found).Error
+	if err != nil {
+		return err
+	}
+	if found != int64(len(uniqueUUIDs(resourceIDs))) {
+		return errUnknownResource
+	}
+
+	links := make([]models.GoalResource, 0, len(resourceIDs))
+	for _, resourceID := range uniqueUUIDs(resourceIDs) {
+		links = append(links, models.GoalResource{GoalID: goal.ID, ResourceID: resourceID})
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error
+}
+
+func uniqueUUIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// goalSnapshot is a goal as the activity log records it, with its linked resources
+type goalSnapshot struct {
+	models.Goal
+	ResourceIDs []uuid.UUID `json:"resource_ids"`
+}
+
+// snapshotGoal captures a goal and the resources currently linked to it
+func snapshotGoal(tx *gorm.DB, goal models.Goal) (*goalSnapshot, error) {
+	snapshot := goalSnapshot{Goal: goal, ResourceIDs: []uuid.UUID{}}
+	err := tx.Model(&models.GoalResource{}).
+		Where("goal_id = ?", goal.ID).
+		Order("resource_id ASC").
+		Pluck("resource_id", &snapshot.ResourceIDs).Error
+	return &snapshot, err
+}
+
+// goalResources returns the resources linked to a goal
+func goalResources(db *gorm.DB, goalID uuid.UUID) ([]models.Resource, error) {
+	var resources []models.Resource
+	err := db.Model(&models.Resource{}).
+		Joins("JOIN learning_goal_resources gr ON gr.resource_id = learning_resources.id").
+		Where("gr.goal_id = ?", goalID).
+		Order("gr.created_at ASC").
+		Find(&resources).Error
+	return resources, err
+}
+
+// GetGoals lists learning goals with optional status and technology filters
+func GetGoals(c *gin.Context) {
+	status := c.Query("status")
+	technology := c.Query("technology")
+
+	db := dbFor(c)
+	query := db.Model(&models.Goal{}).Where("workspace_id = ?", currentWorkspaceID(c))
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if technology != "" {
+		query = query.Where("technology = ?", technology)
+	}
+
+	var goals []models.Goal
+	err := query.Order("target_date ASC NULLS LAST, created_at DESC").Find(&goals).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goals": goals,
+		"filters": gin.H{
+			"status":     status,
+			"technology": technology,
+		},
+	})
+}
+
+// GetGoalByID retrieves a goal together with its linked resources
+func GetGoalByID(c *gin.Context) {
+	goalUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	db := dbFor(c)
+	var goal models.Goal
+	if err := db.First(&goal, "id = ? AND workspace_id = ?", goalUUID, currentWorkspaceID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	resources, err := goalResources(db, goalUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal resources"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"goal":      goal,
+		"resources": resources,
+	})
+}
+
+// CreateGoal creates a goal and links the given resources to it
+func CreateGoal(c *gin.Context) {
+	var req models.GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := dbFor(c)
+	workspaceID := currentWorkspaceID(c)
+	technology, err := repository.NormalizeTechnology(db, workspaceID, req.Technology)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+		return
+	}
+
+	goal := models.Goal{
+		ID:          uuid.New(),
+		UserID:      currentUserID(c),
+		WorkspaceID: workspaceID,
+		Title:       req.Title,
+		Description: req.Description,
+		Technology:  technology,
+		TargetDate:  req.TargetDate,
+		Status:      req.Status,
+	}
+	if goal.Status == "" {
+		goal.Status = "active"
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&goal).Error; err != nil {
+			return err
+		}
+		if err := linkGoalResources(tx, goal, req.ResourceIDs); err != nil {
+			return err
+		}
+		if err := repository.RecalculateGoal(tx, goal.ID, req.Status == ""); err != nil {
+			return err
+		}
+		if err := tx.First(&goal, "id = ?", goal.ID).Error; err != nil {
+			return err
+		}
+		after, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionCreate, models.EntityGoal, goal.ID, nil, after)
+	})
+	if errors.Is(err, errUnknownResource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create goal"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Goal created successfully",
+		"goal":    goal,
+	})
+}
+
+// UpdateGoal updates a goal. When resource_ids is present it replaces the linked resources.
+func UpdateGoal(c *gin.Context) {
+	goalUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	var req models.GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := dbFor(c)
+	var goal models.Goal
+	if err := db.First(&goal, "id = ? AND workspace_id = ?", goalUUID, currentWorkspaceID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+
+	original := goal
+	goal.Title = req.Title
+	goal.Description = req.Description
+	technology, err := repository.NormalizeTechnology(db, goal.WorkspaceID, req.Technology)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal"})
+		return
+	}
+
+	goal.Technology = technology
+	goal.TargetDate = req.TargetDate
+	if req.Status != "" {
+		goal.Status = req.Status
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		before, err := snapshotGoal(tx, original)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(&goal).Error; err != nil {
+			return err
+		}
+		if req.ResourceIDs != nil {
+			if err := tx.Where("goal_id = ?", goal.ID).Delete(&models.GoalResource{}).Error; err != nil {
+				return err
+			}
+			if err := linkGoalResources(tx, goal, req.ResourceIDs); err != nil {
+				return err
+			}
+		}
+		if err := repository.RecalculateGoal(tx, goal.ID, req.Status == ""); err != nil {
+			return err
+		}
+		if err := tx.First(&goal, "id = ?", goal.ID).Error; err != nil {
+			return err
+		}
+		after, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionUpdate, models.EntityGoal, goal.ID, before, after)
+	})
+	if errors.Is(err, errUnknownResource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Goal updated successfully",
+		"goal":    goal,
+	})
+}
+
+// DeleteGoal deletes a goal. Linked resources are left untouched.
+func DeleteGoal(c *gin.Context) {
+	goalUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	db := dbFor(c)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var goal models.Goal
+		if err := tx.First(&goal, "id = ? AND workspace_id = ?", goalUUID, currentWorkspaceID(c)).Error; err != nil {
+			return err
+		}
+		before, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&goal).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionDelete, models.EntityGoal, goal.ID, before, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Goal deleted successfully"})
+}
+
+// LinkGoalResources links additional resources to a goal
+func LinkGoalResources(c *gin.Context) {
+	goalUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	var req models.GoalResourcesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := dbFor(c)
+	var goal models.Goal
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&goal, "id = ? AND workspace_id = ?", goalUUID, currentWorkspaceID(c)).Error; err != nil {
+			return err
+		}
+		before, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		if err := linkGoalResources(tx, goal, req.ResourceIDs); err != nil {
+			return err
+		}
+		if err := repository.RecalculateGoal(tx, goalUUID, true); err != nil {
+			return err
+		}
+		if err := tx.First(&goal, "id = ?", goalUUID).Error; err != nil {
+			return err
+		}
+		after, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionUpdate, models.EntityGoal, goal.ID, before, after)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+	if errors.Is(err, errUnknownResource) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link resources"})
+		return
+	}
+
+	resources, err := goalResources(db, goalUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch goal resources"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Resources linked successfully",
+		"goal":      goal,
+		"resources": resources,
+	})
+}
+
+// UnlinkGoalResource removes a resource from a goal
+func UnlinkGoalResource(c *gin.Context) {
+	goalUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid goal ID"})
+		return
+	}
+
+	resourceUUID, err := uuid.Parse(c.Param("resourceId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	db := dbFor(c)
+	var goal models.Goal
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&goal, "id = ? AND workspace_id = ?", goalUUID, currentWorkspaceID(c)).Error; err != nil {
+			return err
+		}
+		before, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		result := tx.Where("goal_id = ? AND resource_id = ?", goalUUID, resourceUUID).Delete(&models.GoalResource{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errResourceNotLinked
+		}
+		if err := repository.RecalculateGoal(tx, goalUUID, true); err != nil {
+			return err
+		}
+		if err := tx.First(&goal, "id = ?", goalUUID).Error; err != nil {
+			return err
+		}
+		after, err := snapshotGoal(tx, goal)
+		if err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionUpdate, models.EntityGoal, goal.ID, before, after)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Goal not found"})
+		return
+	}
+	if errors.Is(err, errResourceNotLinked) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource is not linked to this goal"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unlink resource"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Resource unlinked successfully",
+		"goal":    goal,
+	})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"diary-backend/internal/buildinfo"
+
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout bounds how long a readiness probe waits on its checks
+const readinessTimeout = 2 * time.Second
+
+// ReadinessCheck reports why the server cannot take traffic, or nil when it can
+type ReadinessCheck func(ctx context.Context) error
+
+// HealthHandler serves the liveness and readiness probes
+type HealthHandler struct {
+	checks map[string]ReadinessCheck
+}
+
+// NewHealthHandler returns a HealthHandler that is ready when every check passes
+func NewHealthHandler(checks map[string]ReadinessCheck) *HealthHandler {
+	return &HealthHandler{checks: checks}
+}
+
+// Live reports that the process is up and serving requests
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "alive",
+		"service": "diary-backend",
+	})
+}
+
+// Ready runs every readiness check and answers 503 when any of them fails,
+// so that traffic is routed away from instances that lost the database
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	status, ready := http.StatusOK, "ready"
+	results := make(map[string]string, len(h.checks))
+	for name, check := range h.checks {
+		if err := check(ctx); err != nil {
+			status, ready = http.StatusServiceUnavailable, "unavailable"
+			results[name] = err.Error()
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	c.JSON(status, gin.H{
+		"status":  ready,
+		"service": "diary-backend",
+		"checks":  results,
+	})
+}
+
+// GetVersion returns the build of the running server
+func GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, buildinfo.Get())
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxOffset caps offset paging, which slows down the deeper it goes. Lists
+// are paged further with cursors.
+const maxOffset = 10000
+
+// maxLimit caps the page size of every list
+const maxLimit = 100
+
+// Errors of the paging parameters shared by the list endpoints
+const (
+	errCursorConflict = "Use either after or before, not both"
+	errCursorOffset   = "Cursors cannot be combined with offset or page"
+	errInvalidCursor  = "Invalid cursor; it may belong to a list sorted differently"
+)
+
+// errOffsetTooDeep is returned for offsets beyond maxOffset
+var errOffsetTooDeep = fmt.Sprintf("Offset is limited to %d; page further with the next_cursor of a page", maxOffset)
+
+// errInvalidLimit is returned for page sizes outside 1 to maxLimit
+var errInvalidLimit = fmt.Sprintf("Invalid limit, expected 1 to %d", maxLimit)
+
+// queryLimit reads the limit parameter of a list, returning fallback when it
+// is not given. It reports false for a limit outside 1 to maxLimit.
+func queryLimit(c *gin.Context, fallback int) (int, bool) {
+	value := c.Query("limit")
+	if value == "" {
+		return fallback, true
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > maxLimit {
+		return 0, false
+	}
+	return limit, true
+}
+
+// listPagination reads the page and limit parameters of a list paged by
+// page number. It reports false for a limit outside 1 to maxLimit.
+func listPagination(c *gin.Context) (int, int, bool) {
+	limit, ok := queryLimit(c, 20)
+	if !ok {
+		return 0, 0, false
+	}
+	page := 1
+	fmt.Sscanf(c.DefaultQuery("page", "1"), "%d", &page)
+	if page < 1 {
+		page = 1
+	}
+	return page, limit, true
+}
+
+// setLinkHeader links the next and previous pages in an RFC 8288 Link header.
+// Their URLs are the request's with its cursor or offset replaced.
+func setLinkHeader(c *gin.Context, page repository.Page) {
+	var links []string
+	for _, link := range []struct{ rel, param, cursor string }{
+		{"next", "after", page.NextCursor},
+		{"prev", "before", page.PrevCursor},
+	} {
+		if link.cursor == "" {
+			continue
+		}
+		query := c.Request.URL.Query()
+		for _, param := range []string{"after", "before", "offset", "page"} {
+			query.Del(param)
+		}
+		query.Set(link.param, link.cursor)
+		target := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, target.String(), link.rel))
+	}
+	if len(links) > 0 {
+		c.Header("Link", strings.Join(links, ", "))
+	}
+}
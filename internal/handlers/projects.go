@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"diary-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// projectSummaries builds the listing query for a workspace's projects with per-project task counts
+func projectSummaries(db *gorm.DB, workspaceID uuid.UUID) *gorm.DB {
+	return db.Model(&models.Project{}).
+		Select(`projects.*,
+			COUNT(tasks.id) AS task_count,
+			COUNT(tasks.id) FILTER (WHERE tasks.completed) AS completed_count`).
+		Joins("LEFT JOIN tasks ON tasks.project_id = projects.id AND tasks.deleted_at IS NULL").
+		Where("projects.workspace_id = ?", workspaceID).
+		Group("projects.id")
+}
+
+func setCompletionPercentage(summary *models.ProjectSummary) {
+	if summary.TaskCount > 0 {
+		percentage := float64(summary.CompletedCount) / float64(summary.TaskCount) * 100
+		summary.CompletionPercentage = float64(int(percentage*10+0.5)) / 10
+	}
+}
+
+// taskProjectError says why a task cannot be assigned to a project, with nil
+// standing for a project that does not exist
+func taskProjectError(project *models.Project) string {
+	if project == nil {
+		return "Invalid projectId: project does not exist"
+	}
+	if project.ArchivedAt != nil {
+		return "Invalid projectId: project is archived"
+	}
+	return ""
+}
+
+// GetProjects lists projects with their task counts
+func GetProjects(c *gin.Context) {
+	archived := c.DefaultQuery("archived", "false") // true, false, all
+
+	db := dbFor(c)
+	query := projectSummaries(db, currentWorkspaceID(c))
+	switch archived {
+	case "true":
+		query = query.Where("projects.archived_at IS NOT NULL")
+	case "false":
+		query = query.Where("projects.archived_at IS NULL")
+	case "all":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid archived filter, expected true, false or all"})
+		return
+	}
+
+	var projects []models.ProjectSummary
+	if err := query.Order("projects.name ASC").Scan(&projects).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch projects"})
+		return
+	}
+
+	for i := range projects {
+		setCompletionPercentage(&projects[i])
+	}
+
+	c.JSON(http.StatusOK, gin.H{"projects": projects})
+}
+
+// GetProjectByID retrieves a single project with its task counts
+func GetProjectByID(c *gin.Context) {
+	projectUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+		return
+	}
+
+	db := dbFor(c)
+	var projects []models.ProjectSummary
+	err = projectSummaries(db, currentWorkspaceID(c)).Where("projects.id = ?", projectUUID).Scan(&projects).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+	if len(projects) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	project := projects[0]
+	setCompletionPercentage(&project)
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+// CreateProject creates a new project
+func CreateProject(c *gin.Context) {
+	var req models.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project := models.Project{
+		UserID:      currentUserID(c),
+		WorkspaceID: currentWorkspaceID(c),
+		Name:        req.Name,
+		Description: req.Description,
+		Color:       req.Color,
+	}
+
+	db := dbFor(c)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionCreate, models.EntityProject, project.ID, nil, project)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"project": project})
+}
+
+// UpdateProject updates an existing project
+func UpdateProject(c *gin.Context) {
+	projectUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+		return
+	}
+
+	var req models.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := dbFor(c)
+	var project models.Project
+	if err := db.First(&project, "id = ? AND workspace_id = ?", projectUUID, currentWorkspaceID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	before := project
+	project.Name = req.Name
+	project.Description = req.Description
+	project.Color = req.Color
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&project).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionUpdate, models.EntityProject, project.ID, before, project)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+// DeleteProject deletes a project. Its tasks are kept and detached from it.
+func DeleteProject(c *gin.Context) {
+	projectUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+		return
+	}
+
+	db := dbFor(c)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var project models.Project
+		if err := tx.First(&project, "id = ? AND workspace_id = ?", projectUUID, currentWorkspaceID(c)).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&project).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionDelete, models.EntityProject, project.ID, project, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Project deleted successfully"})
+}
+
+// setProjectArchived archives or unarchives a project
+func setProjectArchived(c *gin.Context, archived bool) {
+	projectUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+		return
+	}
+
+	db := dbFor(c)
+	var project models.Project
+	if err := db.First(&project, "id = ? AND workspace_id = ?", projectUUID, currentWorkspaceID(c)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch project"})
+		return
+	}
+
+	var archivedAt *time.Time
+	if archived {
+		if project.ArchivedAt != nil {
+			c.JSON(http.StatusOK, gin.H{"project": project})
+			return
+		}
+		now := time.Now()
+		archivedAt = &now
+	}
+
+	before := project
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&project).Update("archived_at", archivedAt).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&project, "id = ?", projectUUID).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionUpdate, models.EntityProject, project.ID, before, project)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update project"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"project": project})
+}
+
+// ArchiveProject archives a project
+func ArchiveProject(c *gin.Context) {
+	setProjectArchived(c, true)
+}
+
+// UnarchiveProject restores an archived project
+func UnarchiveProject(c *gin.Context) {
+	setProjectArchived(c, false)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"diary-backend/internal/querylang"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeQueryError answers 400 with the position of the problem when err is
+// an invalid q parameter, and reports whether it was
+func writeQueryError(c *gin.Context, err error) bool {
+	var queryErr *querylang.Error
+	if !errors.As(err, &queryErr) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error":    "Invalid q: " + queryErr.Error(),
+		"position": queryErr.Pos,
+	})
+	return true
+}
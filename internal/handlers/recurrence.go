@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"time"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/recurrence"
+)
+
+// maxOccurrencesPerTask caps how many upcoming occurrences are expanded for a single recurring task
+const maxOccurrencesPerTask = 100
+
+// normalizeRecurrence validates an RRULE and returns it in canonical form
+func normalizeRecurrence(value string) (string, error) {
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
+
+// seriesStart returns the date a task's recurrence rule is evaluated from
+func seriesStart(task models.Task) time.Time {
+	if task.SeriesStart != nil {
+		return *task.SeriesStart
+	}
+	return task.DueDate
+}
+
+// nextOccurrence builds the task following a completed recurring task. It
+// returns nil when the task does not recur or its series has ended.
+func nextOccurrence(task models.Task) (*models.Task, error) {
+	if task.Recurrence == nil {
+		return nil, nil
+	}
+
+	rule, err := recurrence.Parse(*task.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+
+	start := seriesStart(task)
+	dueDate, ok := rule.Next(start, task.DueDate)
+	if !ok {
+		return nil, nil
+	}
+
+	seriesID := task.ID
+	if task.SeriesID != nil {
+		seriesID = *task.SeriesID
+	}
+
+	next := models.Task{
+		UserID:      task.UserID,
+		WorkspaceID: task.WorkspaceID,
+		Title:       task.Title,
+		Description: task.Description,
+		DueDate:     dueDate,
+		Priority:    task.Priority,
+		Category:    task.Category,
+		Status:      "pending",
+		ProjectID:   task.ProjectID,
+		Tags:        task.Tags,
+		Recurrence:  task.Recurrence,
+		SeriesID:    &seriesID,
+		SeriesStart: &start,
+		Completed:   false,
+	}
+
+	return &next, nil
+}
+
+// expandOccurrences lists the upcoming occurrences of the given recurring
+// tasks that fall within [from, to] and have not been generated yet
+func expandOccurrences(tasks []models.Task, from, to time.Time) []models.TaskOccurrence {
+	occurrences := []models.TaskOccurrence{}
+
+	for _, task := range tasks {
+		if task.Recurrence == nil || task.Completed {
+			continue
+		}
+
+		rule, err := recurrence.Parse(*task.Recurrence)
+		if err != nil {
+			continue
+		}
+
+		// Only dates after the task's own due date are still virtual
+		windowStart := from
+		if !task.DueDate.Before(windowStart) {
+			windowStart = task.DueDate.AddDate(0, 0, 1)
+		}
+
+		for _, dueDate := range rule.Between(seriesStart(task), windowStart, to, maxOccurrencesPerTask) {
+			occurrences = append(occurrences, models.TaskOccurrence{
+				TaskID:   task.ID,
+				SeriesID: task.SeriesID,
+				Title:    task.Title,
+				DueDate:  dueDate,
+				Priority: task.Priority,
+				Category: task.Category,
+			})
+		}
+	}
+
+	return occurrences
+}
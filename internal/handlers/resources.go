@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"strings"
+	"time"
 
-	"diary-backend/internal/database"
+	"diary-backend/internal/metadata"
 	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -17,7 +22,7 @@ type ResourceRequest struct {
 	Description   string   `json:"description,omitempty"`
 	Technology    string   `json:"technology" binding:"required"`
 	Type          string   `json:"type" binding:"required"`
-	Status        string   `json:"status,omitempty"`
+	Status        string   `json:"status,omitempty" binding:"omitempty,oneof=to-read reading completed bookmarked"`
 	Priority      string   `json:"priority,omitempty"`
 	Rating        *int     `json:"rating,omitempty"`
 	EstimatedTime *int     `json:"estimated_time,omitempty"`
@@ -26,82 +31,281 @@ type ResourceRequest struct {
 	Tags          []string `json:"tags,omitempty"`
 }
 
-func GetResources(c *gin.Context) {
-	// Query parameters for filtering
-	technology := c.Query("technology")
-	resourceType := c.Query("type")
-	status := c.Query("status")
-	priority := c.Query("priority")
-	search := c.Query("search")
+// ResourceHandler serves the learning resource endpoints from a resource repository
+type ResourceHandler struct {
+	resources repository.ResourceRepository
+}
+
+// NewResourceHandler returns a ResourceHandler storing resources in resources
+func NewResourceHandler(resources repository.ResourceRepository) *ResourceHandler {
+	return &ResourceHandler{resources: resources}
+}
+
+func (h *ResourceHandler) GetResources(c *gin.Context) {
+	var filters models.ResourceFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+	filter, err := resourceListFilter(filters)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	after := c.Query("after")
+	before := c.Query("before")
 	page := c.DefaultQuery("page", "1")
-	limit := c.DefaultQuery("limit", "20")
+	limitInt, ok := queryLimit(c, 20)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
 
-	// Convert page and limit to integers
+	// Convert page to an integer
 	pageInt := 1
-	limitInt := 20
 	fmt.Sscanf(page, "%d", &pageInt)
-	fmt.Sscanf(limit, "%d", &limitInt)
 	if pageInt < 1 {
 		pageInt = 1
 	}
-	if limitInt < 1 {
-		limitInt = 20
+	offset := (pageInt - 1) * limitInt
+	switch {
+	case after != "" && before != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCursorConflict})
+		return
+	case (after != "" || before != "") && c.Query("page") != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCursorOffset})
+		return
+	case offset > maxOffset:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errOffsetTooDeep})
+		return
 	}
 
-	// Build query
-	db := database.GetDB()
-	query := db.Model(&models.Resource{})
-	if technology != "" {
-		query = query.Where("technology = ?", technology)
-	}
-	if resourceType != "" {
-		query = query.Where("type = ?", resourceType)
-	}
-	if status != "" {
-		query = query.Where("status = ?", status)
-	}
-	if priority != "" {
-		query = query.Where("priority = ?", priority)
+	filter.Limit, filter.Offset, filter.After, filter.Before = limitInt, offset, after, before
+	resources, pageInfo, err := h.resources.List(c.Request.Context(), currentWorkspaceID(c), filter)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidCursor})
+		return
 	}
-	if search != "" {
-		like := "%" + search + "%"
-		query = query.Where("title ILIKE ? OR description ILIKE ?", like, like)
+	if writeQueryError(c, err) {
+		return
 	}
-
-	// Count total
-	var total int64
-	query.Count(&total)
-
-	// Pagination
-	offset := (pageInt - 1) * limitInt
-	var resources []models.Resource
-	err := query.Offset(offset).Limit(limitInt).Find(&resources).Error
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch resources"})
 		return
 	}
 
+	total := pageInfo.Total
 	totalPages := (total + int64(limitInt) - 1) / int64(limitInt)
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"resources": resources,
 		"pagination": gin.H{
 			"page":        pageInt,
 			"limit":       limitInt,
 			"total":       total,
 			"total_pages": totalPages,
+			"next_cursor": pageInfo.NextCursor,
+			"prev_cursor": pageInfo.PrevCursor,
 		},
 		"filters": gin.H{
-			"technology": technology,
-			"type":       resourceType,
-			"status":     status,
-			"priority":   priority,
-			"search":     search,
+			"technology":     filters.Technology,
+			"type":           filters.Type,
+			"status":         filters.Status,
+			"priority":       filters.Priority,
+			"search":         filters.Search,
+			"q":              filters.Q,
+			"tags":           nonNil(filter.Tags),
+			"tag_mode":       tagMode(filter.AnyTag),
+			"rating_min":     filter.RatingMin,
+			"rating_max":     filter.RatingMax,
+			"progress_min":   filter.ProgressMin,
+			"progress_max":   filter.ProgressMax,
+			"estimated_time": nonNil(filter.EstimatedTime),
+			"created_from":   filters.CreatedFrom,
+			"created_to":     filters.CreatedTo,
+			"completed_from": filters.CompletedFrom,
+			"completed_to":   filters.CompletedTo,
+			"sort":           appliedResourceSort(filter),
 		},
-	})
+	}
+	if filters.Search != "" {
+		ids := make([]uuid.UUID, len(resources))
+		for i, resource := range resources {
+			ids[i] = resource.ID
+		}
+		highlights, err := h.resources.Highlights(c.Request.Context(), currentWorkspaceID(c), filters.Search, ids)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to highlight search matches"})
+			return
+		}
+		response["highlights"] = highlights
+	}
+
+	setLinkHeader(c, pageInfo)
+	c.JSON(http.StatusOK, response)
+}
+
+// maxSortFields caps the number of fields a list can be sorted by
+const maxSortFields = 4
+
+// invalidFilter is a filter parameter of a list that cannot be applied. Its
+// text is the error returned to the client.
+type invalidFilter string
+
+func (e invalidFilter) Error() string {
+	return string(e)
+}
+
+// resourceListFilter turns the query parameters of a resource list into the
+// filter the repository lists resources by, without paging
+func resourceListFilter(filters models.ResourceFilters) (repository.ResourceFilter, error) {
+	filter := repository.ResourceFilter{
+		ListOptions:   repository.ListOptions{Search: filters.Search, Query: filters.Q, Tags: splitTags(filters.Tags)},
+		Technology:    filters.Technology,
+		Type:          filters.Type,
+		Status:        filters.Status,
+		Priority:      filters.Priority,
+		RatingMin:     filters.RatingMin,
+		RatingMax:     filters.RatingMax,
+		ProgressMin:   filters.ProgressMin,
+		ProgressMax:   filters.ProgressMax,
+		EstimatedTime: splitTags(filters.EstimatedTime),
+	}
+
+	if err := checkSearch(filters.Search); err != nil {
+		return filter, err
+	}
+	switch filters.TagMode {
+	case "", "all":
+	case "any":
+		filter.AnyTag = true
+	default:
+		return filter, invalidFilter("Invalid tag_mode, expected all or any")
+	}
+	for _, bucket := range filter.EstimatedTime {
+		if !repository.ValidEstimateBucket(bucket) {
+			return filter, invalidFilter("Invalid estimated_time: " + bucket + ", expected short, medium, long or none")
+		}
+	}
+	if err := checkRange("rating", filters.RatingMin, filters.RatingMax, 1, 5); err != nil {
+		return filter, err
+	}
+	if err := checkRange("progress", filters.ProgressMin, filters.ProgressMax, 0, 100); err != nil {
+		return filter, err
+	}
+
+	var err error
+	if filter.CreatedFrom, filter.CreatedTo, err = parseDateRange("created", filters.CreatedFrom, filters.CreatedTo); err != nil {
+		return filter, err
+	}
+	if filter.CompletedFrom, filter.CompletedTo, err = parseDateRange("completed", filters.CompletedFrom, filters.CompletedTo); err != nil {
+		return filter, err
+	}
+	if filter.Sort, err = parseResourceSort(filters.Sort); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// checkRange validates the name_min and name_max parameters of a range
+// between lowest and highest
+func checkRange(name string, min, max *int, lowest, highest int) error {
+	for _, bound := range []struct {
+		param string
+		value *int
+	}{{name + "_min", min}, {name + "_max", max}} {
+		if bound.value != nil && (*bound.value < lowest || *bound.value > highest) {
+			return invalidFilter(fmt.Sprintf("Invalid %s, expected %d to %d", bound.param, lowest, highest))
+		}
+	}
+	if min != nil && max != nil && *max < *min {
+		return invalidFilter(fmt.Sprintf("Invalid %s range: %s_max must not be below %s_min", name, name, name))
+	}
+	return nil
 }
 
-func GetResourceByID(c *gin.Context) {
+// parseDateRange reads the name_from and name_to dates of a range, either of
+// which may be empty
+func parseDateRange(name, fromParam, toParam string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromParam != "" {
+		parsed, err := parseDate(fromParam)
+		if err != nil {
+			return nil, nil, invalidFilter("Invalid " + name + "_from date, expected YYYY-MM-DD")
+		}
+		from = &parsed
+	}
+	if toParam != "" {
+		parsed, err := parseDate(toParam)
+		if err != nil {
+			return nil, nil, invalidFilter("Invalid " + name + "_to date, expected YYYY-MM-DD")
+		}
+		to = &parsed
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		return nil, nil, invalidFilter("Invalid " + name + " range: " + name + "_to must not be before " + name + "_from")
+	}
+	return from, to, nil
+}
+
+// parseResourceSort reads a sort parameter such as -priority,created_at:
+// fields in order of precedence, each descending when prefixed with -
+func parseResourceSort(value string) ([]repository.SortField, error) {
+	var fields []repository.SortField
+	for _, name := range splitTags(value) {
+		field := repository.SortField{Field: strings.TrimPrefix(name, "-"), Desc: strings.HasPrefix(name, "-")}
+		if !repository.ValidResourceSort(field.Field) {
+			return nil, invalidFilter("Invalid sort field: " + field.Field)
+		}
+		for _, earlier := range fields {
+			if earlier.Field == field.Field {
+				return nil, invalidFilter("Invalid sort: " + field.Field + " is given twice")
+			}
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) > maxSortFields {
+		return nil, invalidFilter(fmt.Sprintf("Invalid sort: at most %d fields", maxSortFields))
+	}
+	return fields, nil
+}
+
+// appliedResourceSort describes the order of a resource list in the syntax
+// of the sort parameter, naming the default order when none is given
+func appliedResourceSort(filter repository.ResourceFilter) string {
+	if len(filter.Sort) == 0 {
+		if filter.Search != "" {
+			return "relevance"
+		}
+		return "created_at"
+	}
+	names := make([]string, len(filter.Sort))
+	for i, field := range filter.Sort {
+		names[i] = field.Field
+		if field.Desc {
+			names[i] = "-" + field.Field
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// tagMode names how a list matches its tags filter
+func tagMode(anyTag bool) string {
+	if anyTag {
+		return "any"
+	}
+	return "all"
+}
+
+// nonNil returns values, or an empty list for nil so that it encodes as []
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+func (h *ResourceHandler) GetResourceByID(c *gin.Context) {
 	id := c.Param("id")
 
 	// Validate UUID
@@ -111,10 +315,7 @@ func GetResourceByID(c *gin.Context) {
 		return
 	}
 
-	// Query the database for the resource
-	db := database.GetDB()
-	var resource models.Resource
-	err = db.Where("id = ?", uid).First(&resource).Error
+	resource, err := h.resources.Get(c.Request.Context(), currentWorkspaceID(c), uid)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
 		return
@@ -123,23 +324,32 @@ func GetResourceByID(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"resource": resource})
 }
 
-func CreateResource(c *gin.Context) {
+func (h *ResourceHandler) CreateResource(c *gin.Context) {
 	var req ResourceRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	workspaceID := currentWorkspaceID(c)
+	technology, err := h.resources.NormalizeTechnology(c.Request.Context(), workspaceID, req.Technology)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create resource"})
+		return
+	}
+
 	// Generate a new UUID for the resource
 	resourceID := uuid.New()
 
 	// Build the resource model
 	resource := models.Resource{
 		ID:            resourceID,
+		UserID:        currentUserID(c),
+		WorkspaceID:   workspaceID,
 		Title:         req.Title,
 		URL:           req.URL,
 		Description:   req.Description,
-		Technology:    req.Technology,
+		Technology:    technology,
 		Type:          req.Type,
 		Status:        req.Status,
 		Priority:      req.Priority,
@@ -151,7 +361,7 @@ func CreateResource(c *gin.Context) {
 	}
 
 	// Insert into database
-	err := database.CreateResource(c.Request.Context(), &resource)
+	err = h.resources.Create(c.Request.Context(), currentActor(c), &resource)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create resource"})
 		return
@@ -163,7 +373,7 @@ func CreateResource(c *gin.Context) {
 	})
 }
 
-func UpdateResource(c *gin.Context) {
+func (h *ResourceHandler) UpdateResource(c *gin.Context) {
 	id := c.Param("id")
 
 	uid, err := uuid.Parse(id)
@@ -178,20 +388,25 @@ func UpdateResource(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-	var resource models.Resource
+	ctx := c.Request.Context()
 	// Find the resource first
-	err = db.Where("id = ?", uid).First(&resource).Error
+	resource, err := h.resources.Get(ctx, currentWorkspaceID(c), uid)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
 		return
 	}
 
+	technology, err := h.resources.NormalizeTechnology(ctx, resource.WorkspaceID, req.Technology)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update resource"})
+		return
+	}
+
 	// Update fields
 	resource.Title = req.Title
 	resource.URL = req.URL
 	resource.Description = req.Description
-	resource.Technology = req.Technology
+	resource.Technology = technology
 	resource.Type = req.Type
 	resource.Status = req.Status
 	resource.Priority = req.Priority
@@ -201,9 +416,8 @@ func UpdateResource(c *gin.Context) {
 	resource.Notes = req.Notes
 	resource.Tags = req.Tags
 
-	// Save the updated resource
-	err = db.Save(&resource).Error
-	if err != nil {
+	// Save the updated resource and refresh the goals it belongs to
+	if err := h.resources.Update(ctx, currentActor(c), resource); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update resource"})
 		return
 	}
@@ -214,21 +428,32 @@ func UpdateResource(c *gin.Context) {
 	})
 }
 
-func DeleteResource(c *gin.Context) {
+func (h *ResourceHandler) DeleteResource(c *gin.Context) {
 	id := c.Param("id")
 
-	if _, err := uuid.Parse(id); err != nil {
+	uid, err := uuid.Parse(id)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
 		return
 	}
 
-	// TODO: Implement database deletion
+	// Move the resource to the trash; goals no longer count it
+	err = h.resources.Delete(c.Request.Context(), currentActor(c), uid)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete resource"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Resource deleted successfully",
+		"message": "Resource moved to trash",
 	})
 }
 
-func UpdateResourceStatus(c *gin.Context) {
+func (h *ResourceHandler) UpdateResourceStatus(c *gin.Context) {
 	id := c.Param("id")
 
 	uid, err := uuid.Parse(id)
@@ -238,7 +463,7 @@ func UpdateResourceStatus(c *gin.Context) {
 	}
 
 	var req struct {
-		Status string `json:"status" binding:"required"`
+		Status string `json:"status" binding:"required,oneof=to-read reading completed bookmarked"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -246,31 +471,37 @@ func UpdateResourceStatus(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-	var resource models.Resource
-	err = db.Where("id = ?", uid).First(&resource).Error
+	ctx := c.Request.Context()
+	resource, err := h.resources.Get(ctx, currentWorkspaceID(c), uid)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
 		return
 	}
 
 	resource.Status = req.Status
-	err = db.Save(&resource).Error
-	if err != nil {
+	if err := h.resources.Update(ctx, currentActor(c), resource); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update resource status"})
 		return
 	}
 
+	// The database sets completed_at and progress when the status changes
+	resource, err = h.resources.Get(ctx, currentWorkspaceID(c), uid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch updated resource"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "Resource status updated successfully",
 		"resource": resource,
 	})
 }
 
-func UpdateResourceRating(c *gin.Context) {
+func (h *ResourceHandler) UpdateResourceRating(c *gin.Context) {
 	id := c.Param("id")
 
-	if _, err := uuid.Parse(id); err != nil {
+	uid, err := uuid.Parse(id)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
 		return
 	}
@@ -284,37 +515,141 @@ func UpdateResourceRating(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement rating update
+	ctx := c.Request.Context()
+	resource, err := h.resources.Get(ctx, currentWorkspaceID(c), uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+
+	resource.Rating = &req.Rating
+	if err := h.resources.Update(ctx, currentActor(c), resource); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update resource rating"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Resource rating updated successfully",
+		"message":  "Resource rating updated successfully",
+		"resource": resource,
 	})
 }
 
-func GetResourceStats(c *gin.Context) {
-	// TODO: Implement stats calculation
-	c.JSON(http.StatusOK, gin.H{
-		"total_resources":      0,
-		"completed_count":      0,
-		"in_progress_count":    0,
-		"to_read_count":        0,
-		"bookmarked_count":     0,
-		"weekly_hours":         0,
-		"avg_rating":           0,
-		"technology_breakdown": map[string]int{},
-		"type_breakdown":       map[string]int{},
+// GetResourceStats aggregates resource and study-time statistics. Resources
+// are filtered by created_at and sessions by session_date when from/to are
+// given; without a window weekly_hours covers the last 7 days.
+func (h *ResourceHandler) GetResourceStats(c *gin.Context) {
+	technology := c.Query("technology")
+	fromParam := c.Query("from")
+	toParam := c.Query("to")
+
+	var from, to *time.Time
+	if fromParam != "" {
+		parsed, err := parseDate(fromParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return
+		}
+		from = &parsed
+	}
+	if toParam != "" {
+		parsed, err := parseDate(toParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return
+		}
+		to = &parsed
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window: to must not be before from"})
+		return
+	}
+
+	// Study time comes from the sessions logged within the window
+	sessionFrom, sessionTo := time.Now().AddDate(0, 0, -6), time.Now()
+	if from != nil || to != nil {
+		if from != nil {
+			sessionFrom = *from
+		}
+		if to != nil {
+			sessionTo = *to
+		}
+		if from == nil {
+			sessionFrom = sessionTo.AddDate(0, 0, -6)
+		}
+	}
+
+	stats, err := h.resources.Stats(c.Request.Context(), currentWorkspaceID(c), repository.ResourceStatsFilter{
+		Technology:  technology,
+		From:        from,
+		To:          to,
+		SessionFrom: sessionFrom,
+		SessionTo:   sessionTo,
 	})
-}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate resource stats"})
+		return
+	}
+
+	days := int(sessionTo.Sub(sessionFrom).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+	totalHours := float64(stats.SessionMinutes) / 60
+	weeklyHours := totalHours
+	if days > 7 {
+		weeklyHours = totalHours / (float64(days) / 7)
+	}
+
+	avgRating := 0.0
+	if stats.AvgRating != nil {
+		avgRating = roundTo(*stats.AvgRating, 2)
+	}
 
-func GetTechnologies(c *gin.Context) {
-	// TODO: Get unique technologies from database
 	c.JSON(http.StatusOK, gin.H{
-		"technologies": []string{},
+		"total_resources":      stats.Total,
+		"completed_count":      stats.Completed,
+		"in_progress_count":    stats.Reading,
+		"to_read_count":        stats.ToRead,
+		"bookmarked_count":     stats.Bookmarked,
+		"weekly_hours":         roundTo(weeklyHours, 2),
+		"total_hours":          roundTo(totalHours, 2),
+		"session_count":        stats.SessionCount,
+		"avg_rating":           avgRating,
+		"technology_breakdown": stats.Technologies,
+		"type_breakdown":       stats.Types,
+		"filters": gin.H{
+			"technology": technology,
+			"from":       fromParam,
+			"to":         toParam,
+		},
 	})
 }
 
-func ImportFromURL(c *gin.Context) {
+// roundTo rounds a value to the given number of decimal places
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
+
+// urlExtractor loads pages for ImportFromURL. Replace it with SetURLFetcher
+// to import from somewhere other than the live web.
+var urlExtractor = metadata.NewExtractor(metadata.NewHTTPFetcher(metadata.DefaultTimeout, metadata.DefaultMaxBytes))
+
+// SetURLFetcher changes the fetcher used by ImportFromURL
+func SetURLFetcher(fetcher metadata.Fetcher) {
+	urlExtractor = metadata.NewExtractor(fetcher)
+}
+
+// ImportFromURL extracts resource details from a page. With save=true (in
+// the body or the query string) the resource is also stored, which requires
+// a technology.
+func (h *ResourceHandler) ImportFromURL(c *gin.Context) {
 	var req struct {
-		URL string `json:"url" binding:"required,url"`
+		URL        string   `json:"url" binding:"required,url"`
+		Save       bool     `json:"save"`
+		Technology string   `json:"technology"`
+		Priority   string   `json:"priority" binding:"omitempty,oneof=low medium high"`
+		Tags       []string `json:"tags"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -322,13 +657,63 @@ func ImportFromURL(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement URL metadata extraction
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Resource imported successfully",
-		"resource": gin.H{
-			"title":       "Extracted Title",
-			"description": "Extracted Description",
-			"type":        "article",
-		},
+	save := req.Save || c.Query("save") == "true"
+	if save && req.Technology == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "technology is required when save=true"})
+		return
+	}
+
+	workspaceID := currentWorkspaceID(c)
+	technology, err := h.resources.NormalizeTechnology(c.Request.Context(), workspaceID, req.Technology)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import resource"})
+		return
+	}
+
+	meta, err := urlExtractor.Extract(c.Request.Context(), req.URL)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch URL: " + err.Error()})
+		return
+	}
+
+	resource := models.Resource{
+		UserID:        currentUserID(c),
+		WorkspaceID:   workspaceID,
+		Title:         meta.Title,
+		URL:           req.URL,
+		Description:   meta.Description,
+		Technology:    technology,
+		Type:          meta.Type,
+		Status:        "to-read",
+		Priority:      req.Priority,
+		EstimatedTime: meta.EstimatedMinutes,
+		Tags:          req.Tags,
+	}
+	if resource.Title == "" {
+		resource.Title = metadata.TruncateTitle(req.URL)
+	}
+	if resource.Priority == "" {
+		resource.Priority = "medium"
+	}
+
+	if !save {
+		c.JSON(http.StatusOK, gin.H{
+			"message":  "Resource metadata extracted successfully",
+			"resource": resource,
+			"metadata": meta,
+		})
+		return
+	}
+
+	resource.ID = uuid.New()
+	if err := h.resources.Create(c.Request.Context(), currentActor(c), &resource); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create resource"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Resource imported successfully",
+		"resource": resource,
+		"metadata": meta,
 	})
 }
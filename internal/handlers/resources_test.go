@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"diary-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// createResource creates a resource through the API and returns it
+func (s *testServer) createResource(t *testing.T, body gin.H) models.Resource {
+	t.Helper()
+	var out struct {
+		Resource models.Resource `json:"resource"`
+	}
+	expect(t, s.do(t, http.MethodPost, "/resources", body, &out), http.StatusCreated)
+	return out.Resource
+}
+
+// resourceTitles lists the titles of a resource list response
+func resourceTitles(resources []models.Resource) []string {
+	titles := make([]string, len(resources))
+	for i, resource := range resources {
+		titles[i] = resource.Title
+	}
+	return titles
+}
+
+func TestCreateResource(t *testing.T) {
+	s := newTestServer(t)
+	resource := s.createResource(t, gin.H{"title": "Go blog", "technology": "Go", "type": "article", "tags": []string{"go"}})
+	if resource.ID == uuid.Nil || resource.Title != "Go blog" {
+		t.Errorf("resource = %+v, want it stored with an ID", resource)
+	}
+
+	var out struct {
+		Resource models.Resource `json:"resource"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/resources/"+resource.ID.String(), nil, &out), http.StatusOK)
+	if out.Resource.ID != resource.ID || out.Resource.Technology != resource.Technology {
+		t.Errorf("GET = %+v, want the created resource", out.Resource)
+	}
+
+	expect(t, s.do(t, http.MethodPost, "/resources", gin.H{"title": "No type", "technology": "Go"}, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodGet, "/resources/not-a-uuid", nil, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodGet, "/resources/"+uuid.NewString(), nil, nil), http.StatusNotFound)
+}
+
+func TestGetResourcesFilters(t *testing.T) {
+	s := newTestServer(t)
+	s.createResource(t, gin.H{"title": "Tour of Go", "technology": "Go", "type": "course", "priority": "high", "rating": 5, "progress": 100, "tags": []string{"go", "basics"}})
+	s.createResource(t, gin.H{"title": "Effective Go", "technology": "Go", "type": "article", "priority": "medium", "rating": 4, "progress": 40, "tags": []string{"go"}})
+	s.createResource(t, gin.H{"title": "Rust book", "technology": "Rust", "type": "book", "priority": "low", "rating": 3, "tags": []string{"rust", "basics"}})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"?sort=title", []string{"Effective Go", "Rust book", "Tour of Go"}},
+		{"?sort=-rating", []string{"Tour of Go", "Effective Go", "Rust book"}},
+		{"?sort=-priority,title", []string{"Tour of Go", "Effective Go", "Rust book"}},
+		{"?type=book&sort=title", []string{"Rust book"}},
+		{"?tags=go,basics&sort=title", []string{"Tour of Go"}},
+		{"?tags=rust,basics&tag_mode=any&sort=title", []string{"Rust book", "Tour of Go"}},
+		{"?rating_min=4&sort=title", []string{"Effective Go", "Tour of Go"}},
+		{"?progress_min=10&progress_max=50&sort=title", []string{"Effective Go"}},
+		{"?q=rating>=4+-type:course&sort=title", []string{"Effective Go"}},
+		{"?limit=2&sort=title", []string{"Effective Go", "Rust book"}},
+		{"?limit=2&page=2&sort=title", []string{"Tour of Go"}},
+	}
+	for _, tt := range tests {
+		var out struct {
+			Resources []models.Resource `json:"resources"`
+		}
+		expect(t, s.do(t, http.MethodGet, "/resources"+tt.query, nil, &out), http.StatusOK)
+		if got := resourceTitles(out.Resources); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("GET /resources%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGetResourcesRejectsInvalidParameters(t *testing.T) {
+	s := newTestServer(t)
+	for _, query := range []string{
+		"?tag_mode=some",
+		"?estimated_time=forever",
+		"?rating_min=0",
+		"?rating_min=4&rating_max=2",
+		"?progress_max=101",
+		"?created_from=yesterday",
+		"?completed_from=2026-03-02&completed_to=2026-03-01",
+		"?sort=colour",
+		"?sort=title,-title",
+		"?sort=title,rating,priority,progress,created_at",
+		"?after=a&before=b",
+		"?after=a&page=2",
+		"?after=not-a-cursor",
+		"?q=rating>>4",
+		"?search=!!!",
+	} {
+		if w := s.do(t, http.MethodGet, "/resources"+query, nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /resources%s = %d, want 400: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestUpdateResource(t *testing.T) {
+	s := newTestServer(t)
+	resource := s.createResource(t, gin.H{"title": "Draft notes", "technology": "Go", "type": "article"})
+	path := "/resources/" + resource.ID.String()
+
+	var out struct {
+		Resource models.Resource `json:"resource"`
+	}
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"title": "Notes", "technology": "Go", "type": "article", "status": "reading"}, &out), http.StatusOK)
+	if out.Resource.Title != "Notes" || out.Resource.Status != "reading" {
+		t.Errorf("resource = %+v, want it renamed and being read", out.Resource)
+	}
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"title": "Notes", "technology": "Go", "type": "article", "status": "in-progress"}, nil), http.StatusBadRequest)
+
+	expect(t, s.do(t, http.MethodPatch, path+"/status", gin.H{"status": "completed"}, &out), http.StatusOK)
+	if out.Resource.Status != "completed" {
+		t.Errorf("status = %q, want completed", out.Resource.Status)
+	}
+
+	expect(t, s.do(t, http.MethodPatch, path+"/status", gin.H{}, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodPatch, path+"/status", gin.H{"status": "in-progress"}, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodPut, "/resources/"+uuid.NewString(), gin.H{"title": "x", "technology": "Go", "type": "article"}, nil), http.StatusNotFound)
+}
+
+func TestDeleteResource(t *testing.T) {
+	s := newTestServer(t)
+	resource := s.createResource(t, gin.H{"title": "Old link", "technology": "Go", "type": "article"})
+	path := "/resources/" + resource.ID.String()
+
+	expect(t, s.do(t, http.MethodDelete, path, nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodGet, path, nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodDelete, path, nil, nil), http.StatusNotFound)
+}
+
+func TestRestoreResource(t *testing.T) {
+	s := newTestServer(t)
+	resource := s.createResource(t, gin.H{"title": "Old link", "technology": "Go", "type": "article"})
+	path := "/resources/" + resource.ID.String()
+
+	expect(t, s.do(t, http.MethodPost, path+"/restore", nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodDelete, path, nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodPost, path+"/restore", nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodGet, path, nil, nil), http.StatusOK)
+}
+
+func TestCreateSession(t *testing.T) {
+	s := newTestServer(t)
+	resource := s.createResource(t, gin.H{"title": "Go spec", "technology": "Go", "type": "documentation", "status": "to-read"})
+	path := "/resources/" + resource.ID.String() + "/sessions"
+
+	var out struct {
+		Resource models.Resource `json:"resource"`
+	}
+	expect(t, s.do(t, http.MethodPost, path, gin.H{"duration_minutes": 30}, &out), http.StatusCreated)
+	if out.Resource.Status != "reading" {
+		t.Errorf("status = %q, want the resource moved to reading", out.Resource.Status)
+	}
+	expect(t, s.do(t, http.MethodPost, path, gin.H{"duration_minutes": 15}, nil), http.StatusCreated)
+
+	var sessions struct {
+		Sessions     []models.Session `json:"sessions"`
+		TotalMinutes int64            `json:"total_minutes"`
+	}
+	expect(t, s.do(t, http.MethodGet, path, nil, &sessions), http.StatusOK)
+	if len(sessions.Sessions) != 2 || sessions.TotalMinutes != 45 {
+		t.Errorf("sessions = %d with %d minutes, want 2 with 45", len(sessions.Sessions), sessions.TotalMinutes)
+	}
+	expect(t, s.do(t, http.MethodPost, "/resources/"+uuid.NewString()+"/sessions", gin.H{"duration_minutes": 30}, nil), http.StatusNotFound)
+}
+
+func TestImportFromURLValidation(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		name   string
+		body   gin.H
+		status int
+	}{
+		{"missing url", gin.H{}, http.StatusBadRequest},
+		{"invalid priority", gin.H{"url": "https://example.com", "priority": "urgent"}, http.StatusBadRequest},
+		{"save without technology", gin.H{"url": "https://example.com", "save": true}, http.StatusBadRequest},
+		{"internal address", gin.H{"url": "http://127.0.0.1/"}, http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(t, http.MethodPost, "/resources/import-url", tt.body, nil), tt.status)
+		})
+	}
+}
+
+func TestUpdateResourceRating(t *testing.T) {
+	s := newTestServer(t)
+	resource := s.createResource(t, gin.H{"title": "Go spec", "technology": "Go", "type": "documentation"})
+	path := "/resources/" + resource.ID.String() + "/rating"
+
+	var out struct {
+		Resource models.Resource `json:"resource"`
+	}
+	expect(t, s.do(t, http.MethodPatch, path, gin.H{"rating": 4}, &out), http.StatusOK)
+	if out.Resource.Rating == nil || *out.Resource.Rating != 4 {
+		t.Errorf("rating = %v, want 4", out.Resource.Rating)
+	}
+	stored, err := s.repos.Resources.Get(t.Context(), s.workspaceID, resource.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Rating == nil || *stored.Rating != 4 {
+		t.Errorf("stored rating = %v, want 4", stored.Rating)
+	}
+
+	for _, body := range []gin.H{{}, {"rating": 0}, {"rating": 6}} {
+		expect(t, s.do(t, http.MethodPatch, path, body, nil), http.StatusBadRequest)
+	}
+	expect(t, s.do(t, http.MethodPatch, "/resources/not-a-uuid/rating", gin.H{"rating": 4}, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodPatch, "/resources/"+uuid.NewString()+"/rating", gin.H{"rating": 4}, nil), http.StatusNotFound)
+}
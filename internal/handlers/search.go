@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+
+	"diary-backend/internal/auth"
+	"diary-backend/internal/middleware"
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Types of records returned by the unified search
+const (
+	searchTypeTask     = "task"
+	searchTypeResource = "resource"
+)
+
+// errNoSearchWords is the error for searches without any word to match
+const errNoSearchWords = "Search has no words"
+
+// checkSearch rejects a search that is given but has no words to match
+func checkSearch(search string) error {
+	if search != "" && !repository.HasSearchWords(search) {
+		return invalidFilter(errNoSearchWords)
+	}
+	return nil
+}
+
+// searchScopes are the scopes a token needs to search each type of record
+var searchScopes = map[string]string{
+	searchTypeTask:     auth.ScopeTasksRead,
+	searchTypeResource: auth.ScopeResourcesRead,
+}
+
+// searchResult is one match of the unified search: the highlighted match and
+// the task or resource it was found in
+type searchResult struct {
+	Type string    `json:"type"`
+	ID   uuid.UUID `json:"id"`
+	repository.Highlight
+	Task     *models.Task     `json:"task,omitempty"`
+	Resource *models.Resource `json:"resource,omitempty"`
+}
+
+// SearchHandler serves the search across tasks and learning resources
+type SearchHandler struct {
+	tasks     repository.TaskRepository
+	resources repository.ResourceRepository
+}
+
+// NewSearchHandler returns a SearchHandler searching tasks and resources
+func NewSearchHandler(tasks repository.TaskRepository, resources repository.ResourceRepository) *SearchHandler {
+	return &SearchHandler{tasks: tasks, resources: resources}
+}
+
+// tokenAllows reports whether the request may use scope. Login sessions may
+// use every scope.
+func tokenAllows(c *gin.Context, scope string) bool {
+	granted, ok := c.Get(middleware.TokenScopesKey)
+	return !ok || auth.HasScope(granted.([]string), scope)
+}
+
+// Search ranks the tasks and resources of the workspace matching q and
+// returns the best matches of both types in one list. types limits the search
+// to task or resource; by default every type the token can read is searched.
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query parameter q is required"})
+		return
+	}
+	if !repository.HasSearchWords(query) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errNoSearchWords})
+		return
+	}
+
+	limit, ok := queryLimit(c, 20)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+
+	types := map[string]bool{}
+	if value := c.Query("types"); value != "" {
+		for _, searchType := range splitTags(value) {
+			scope, ok := searchScopes[searchType]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type: " + searchType + ", expected task or resource"})
+				return
+			}
+			if !tokenAllows(c, scope) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "Token is missing the " + scope + " scope"})
+				return
+			}
+			types[searchType] = true
+		}
+	} else {
+		for searchType, scope := range searchScopes {
+			types[searchType] = tokenAllows(c, scope)
+		}
+		if !types[searchTypeTask] && !types[searchTypeResource] {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Token is missing the " + auth.ScopeTasksRead + " or " + auth.ScopeResourcesRead + " scope"})
+			return
+		}
+	}
+
+	ctx := c.Request.Context()
+	workspaceID := currentWorkspaceID(c)
+	options := repository.ListOptions{Search: query, Limit: limit}
+	results := []searchResult{}
+	totals := gin.H{}
+
+	if types[searchTypeTask] {
+		tasks, page, err := h.tasks.List(ctx, workspaceID, repository.TaskFilter{ListOptions: options})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+			return
+		}
+		ids := make([]uuid.UUID, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		highlights, err := h.tasks.Highlights(ctx, workspaceID, query, ids)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search tasks"})
+			return
+		}
+		for i := range tasks {
+			results = append(results, searchResult{Type: searchTypeTask, ID: tasks[i].ID, Highlight: highlights[tasks[i].ID], Task: &tasks[i]})
+		}
+		totals[searchTypeTask] = page.Total
+	}
+
+	if types[searchTypeResource] {
+		resources, page, err := h.resources.List(ctx, workspaceID, repository.ResourceFilter{ListOptions: options})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search resources"})
+			return
+		}
+		ids := make([]uuid.UUID, len(resources))
+		for i, resource := range resources {
+			ids[i] = resource.ID
+		}
+		highlights, err := h.resources.Highlights(ctx, workspaceID, query, ids)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search resources"})
+			return
+		}
+		for i := range resources {
+			results = append(results, searchResult{Type: searchTypeResource, ID: resources[i].ID, Highlight: highlights[resources[i].ID], Resource: &resources[i]})
+		}
+		totals[searchTypeResource] = page.Total
+	}
+
+	// Each type is ranked already; interleave them by rank and keep the best
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Rank > results[j].Rank
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"query":   query,
+		"results": results,
+		"totals":  totals,
+	})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSearch(t *testing.T) {
+	s := newTestServer(t)
+	s.createTask(t, gin.H{"title": "Learn goroutines", "dueDate": "2026-03-02T00:00:00Z"})
+	s.createTask(t, gin.H{"title": "Buy groceries", "dueDate": "2026-03-02T00:00:00Z"})
+	s.createResource(t, gin.H{"title": "Go by Example", "technology": "Go", "type": "article"})
+
+	var out struct {
+		Results []searchResult `json:"results"`
+		Totals  map[string]int `json:"totals"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/search?q=go", nil, &out), http.StatusOK)
+	if len(out.Results) != 2 || out.Totals[searchTypeTask] != 1 || out.Totals[searchTypeResource] != 1 {
+		t.Errorf("results = %+v, totals = %v; want the goroutines task and the Go resource", out.Results, out.Totals)
+	}
+
+	expect(t, s.do(t, http.MethodGet, "/search?q=go&types=task", nil, &out), http.StatusOK)
+	if len(out.Results) != 1 || out.Results[0].Type != searchTypeTask {
+		t.Errorf("results = %+v, want only the task", out.Results)
+	}
+}
+
+func TestSearchRejectsInvalidParameters(t *testing.T) {
+	s := newTestServer(t)
+	for _, query := range []string{
+		"",
+		"?q=",
+		"?q=!!!",
+		"?q=%22%22",
+		"?q=go&limit=0",
+		"?q=go&limit=101",
+		"?q=go&types=goal",
+	} {
+		if w := s.do(t, http.MethodGet, "/search"+query, nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /search%s = %d, want 400: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// parseDate parses a YYYY-MM-DD query value
+func parseDate(value string) (time.Time, error) {
+	return time.Parse("2006-01-02", value)
+}
+
+// GetSessions lists learning sessions across all resources
+func (h *ResourceHandler) GetSessions(c *gin.Context) {
+	var filters models.SessionFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
+		return
+	}
+
+	if filters.Page < 1 {
+		filters.Page = 1
+	}
+	limit, ok := queryLimit(c, 50)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+
+	filter := repository.SessionFilter{
+		Technology: filters.Technology,
+		Limit:      limit,
+		Offset:     (filters.Page - 1) * limit,
+	}
+
+	if filters.ResourceID != "" {
+		resourceUUID, err := uuid.Parse(filters.ResourceID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+			return
+		}
+		filter.ResourceID = &resourceUUID
+	}
+
+	if filters.From != "" {
+		from, err := parseDate(filters.From)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return
+		}
+		filter.From = &from
+	}
+
+	if filters.To != "" {
+		to, err := parseDate(filters.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return
+		}
+		filter.To = &to
+	}
+
+	sessions, totals, err := h.resources.Sessions(c.Request.Context(), currentWorkspaceID(c), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	totalPages := (totals.Count + int64(limit) - 1) / int64(limit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions":      sessions,
+		"total_minutes": totals.Minutes,
+		"pagination": gin.H{
+			"page":        filters.Page,
+			"limit":       limit,
+			"total":       totals.Count,
+			"total_pages": totalPages,
+		},
+		"filters": gin.H{
+			"resource_id": filters.ResourceID,
+			"technology":  filters.Technology,
+			"from":        filters.From,
+			"to":          filters.To,
+		},
+	})
+}
+
+// GetResourceSessions lists the sessions logged against a single resource
+func (h *ResourceHandler) GetResourceSessions(c *gin.Context) {
+	resourceUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	workspaceID := currentWorkspaceID(c)
+	if _, err := h.resources.Get(ctx, workspaceID, resourceUUID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+
+	sessions, totals, err := h.resources.Sessions(ctx, workspaceID, repository.SessionFilter{ResourceID: &resourceUUID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions":      sessions,
+		"total_minutes": totals.Minutes,
+	})
+}
+
+// CreateSession logs a study session against a resource. A resource that
+// has not been started yet is moved to "reading".
+func (h *ResourceHandler) CreateSession(c *gin.Context) {
+	resourceUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	var req models.SessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session := models.Session{
+		ResourceID:      resourceUUID,
+		DurationMinutes: req.DurationMinutes,
+		Notes:           req.Notes,
+		SessionDate:     time.Now(),
+	}
+	if req.SessionDate != nil {
+		session.SessionDate = *req.SessionDate
+	}
+
+	resource, err := h.resources.CreateSession(c.Request.Context(), currentActor(c), &session)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to log session"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Session logged successfully",
+		"session":  session,
+		"resource": resource,
+	})
+}
+
+// findResourceSession loads a session by ID, making sure it belongs to the resource in the path
+func (h *ResourceHandler) findResourceSession(c *gin.Context) (*models.Session, bool) {
+	resourceUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return nil, false
+	}
+
+	sessionUUID, err := uuid.Parse(c.Param("sessionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid session ID"})
+		return nil, false
+	}
+
+	session, err := h.resources.Session(c.Request.Context(), currentWorkspaceID(c), resourceUUID, sessionUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return nil, false
+	}
+
+	return session, true
+}
+
+// GetSessionByID retrieves a single session of a resource
+func (h *ResourceHandler) GetSessionByID(c *gin.Context) {
+	session, ok := h.findResourceSession(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"session": session})
+}
+
+// UpdateSession updates a logged session
+func (h *ResourceHandler) UpdateSession(c *gin.Context) {
+	session, ok := h.findResourceSession(c)
+	if !ok {
+		return
+	}
+
+	var req models.SessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session.DurationMinutes = req.DurationMinutes
+	session.Notes = req.Notes
+	if req.SessionDate != nil {
+		session.SessionDate = *req.SessionDate
+	}
+
+	err := h.resources.UpdateSession(c.Request.Context(), currentActor(c), session)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Session updated successfully",
+		"session": session,
+	})
+}
+
+// DeleteSession removes a logged session
+func (h *ResourceHandler) DeleteSession(c *gin.Context) {
+	session, ok := h.findResourceSession(c)
+	if !ok {
+		return
+	}
+
+	err := h.resources.DeleteSession(c.Request.Context(), currentActor(c), session.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Session deleted successfully"})
+}
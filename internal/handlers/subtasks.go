@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"net/http"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// taskProgress computes a task's progress from its direct subtasks and checklist items
+func taskProgress(task models.Task, subtasks []models.Task, checklist []models.ChecklistItem) models.TaskProgress {
+	progress := models.TaskProgress{
+		SubtasksTotal:  int64(len(subtasks)),
+		ChecklistTotal: int64(len(checklist)),
+	}
+	for _, subtask := range subtasks {
+		if subtask.Completed {
+			progress.SubtasksCompleted++
+		}
+	}
+	for _, item := range checklist {
+		if item.Done {
+			progress.ChecklistDone++
+		}
+	}
+
+	total := progress.SubtasksTotal + progress.ChecklistTotal
+	switch {
+	case total > 0:
+		progress.Percentage = int((progress.SubtasksCompleted + progress.ChecklistDone) * 100 / total)
+	case task.Completed:
+		progress.Percentage = 100
+	}
+	return progress
+}
+
+// findParentTask loads the task named by the :id path parameter
+func (h *TaskHandler) findParentTask(c *gin.Context) (*models.Task, bool) {
+	taskUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		return nil, false
+	}
+
+	task, err := h.tasks.Get(c.Request.Context(), currentWorkspaceID(c), taskUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return nil, false
+	}
+
+	return task, true
+}
+
+// GetSubtasks lists the direct subtasks of a task
+func (h *TaskHandler) GetSubtasks(c *gin.Context) {
+	parent, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	subtasks, err := h.tasks.Subtasks(ctx, parent.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subtasks"})
+		return
+	}
+
+	checklist, err := h.tasks.Checklist(ctx, parent.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"subtasks": subtasks,
+		"progress": taskProgress(*parent, subtasks, checklist),
+	})
+}
+
+// CreateSubtask creates a task below the task in the path. The subtask
+// inherits the parent's project unless one is given.
+func (h *TaskHandler) CreateSubtask(c *gin.Context) {
+	parent, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ProjectID == nil {
+		req.ProjectID = parent.ProjectID
+	}
+
+	task, ok := newTaskFromRequest(c, req)
+	if !ok {
+		return
+	}
+	task.ParentID = &parent.ID
+
+	// Subtasks can only be assigned to existing projects
+	if task.ProjectID != nil && !h.validateProject(c, *task.ProjectID) {
+		return
+	}
+
+	if err := h.tasks.Create(c.Request.Context(), currentActor(c), task); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create subtask"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"task": task})
+}
+
+// GetChecklist lists the checklist items of a task
+func (h *TaskHandler) GetChecklist(c *gin.Context) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	checklist, err := h.tasks.Checklist(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklist"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"checklist": checklist})
+}
+
+// CreateChecklistItem adds an item to a task's checklist, appending it unless a position is given
+func (h *TaskHandler) CreateChecklistItem(c *gin.Context) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	var req models.CreateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	item := models.ChecklistItem{
+		TaskID: task.ID,
+		Title:  req.Title,
+		Done:   req.Done,
+	}
+	if req.Position != nil {
+		item.Position = *req.Position
+	}
+
+	if err := h.tasks.CreateChecklistItem(c.Request.Context(), currentActor(c), &item, req.Position == nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create checklist item"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"item": item})
+}
+
+// findChecklistItem loads a checklist item, making sure it belongs to the task in the path
+func (h *TaskHandler) findChecklistItem(c *gin.Context) (*models.ChecklistItem, bool) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return nil, false
+	}
+
+	itemUUID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid checklist item ID format"})
+		return nil, false
+	}
+
+	item, err := h.tasks.ChecklistItem(c.Request.Context(), task.ID, itemUUID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checklist item not found"})
+		return nil, false
+	}
+
+	return item, true
+}
+
+// UpdateChecklistItem updates a checklist item
+func (h *TaskHandler) UpdateChecklistItem(c *gin.Context) {
+	item, ok := h.findChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	update := repository.ChecklistItemUpdate{Title: req.Title, Done: req.Done, Position: req.Position}
+	if update != (repository.ChecklistItemUpdate{}) {
+		var err error
+		item, err = h.tasks.UpdateChecklistItem(c.Request.Context(), currentActor(c), item.ID, update)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update checklist item"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"item": item})
+}
+
+// DeleteChecklistItem removes an item from a task's checklist
+func (h *TaskHandler) DeleteChecklistItem(c *gin.Context) {
+	item, ok := h.findChecklistItem(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tasks.DeleteChecklistItem(c.Request.Context(), currentActor(c), item.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete checklist item"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Checklist item deleted successfully"})
+}
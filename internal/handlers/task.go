@@ -1,156 +1,215 @@
 package handlers
 
 import (
-	"diary-backend/internal/database"
-	"diary-backend/internal/models"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
-// Add this helper function at the top of your handlers file
-func getColumnName(sortBy string) string {
-	columnMap := map[string]string{
-		"dueDate":   "due_date",
-		"createdAt": "created_at",
-		"updatedAt": "updated_at",
-		"projectId": "project_id",
-		"title":     "title",
-		"priority":  "priority",
-		"category":  "category",
-		"status":    "status",
-		"completed": "completed",
-	}
+// TaskHandler serves the task endpoints from a task repository
+type TaskHandler struct {
+	tasks repository.TaskRepository
+}
 
-	if column, exists := columnMap[sortBy]; exists {
-		return column
+// NewTaskHandler returns a TaskHandler storing tasks in tasks
+func NewTaskHandler(tasks repository.TaskRepository) *TaskHandler {
+	return &TaskHandler{tasks: tasks}
+}
+
+// currentActor returns the user and workspace changes are made by
+func currentActor(c *gin.Context) repository.Actor {
+	return repository.Actor{WorkspaceID: currentWorkspaceID(c), UserID: currentUserID(c)}
+}
+
+// splitTags turns a comma-separated tags parameter into its non-empty tags
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.Split(value, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
 	}
-	return sortBy // fallback to original if not found
+	return tags
 }
 
 // GetTasks retrieves tasks with optional filtering
-func GetTasks(c *gin.Context) {
+func (h *TaskHandler) GetTasks(c *gin.Context) {
 	var filters models.TaskFilters
 	if err := c.ShouldBindQuery(&filters); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
 		return
 	}
 
-	// Set default values
-	if filters.Limit == 0 {
-		filters.Limit = 50
+	var ok bool
+	if filters.Limit, ok = queryLimit(c, 0); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
 	}
-	if filters.SortBy == "" {
-		filters.SortBy = "dueDate"
+	setTaskFilterDefaults(&filters)
+	if filters.SortOrder != "asc" && filters.SortOrder != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sortOrder, expected asc or desc"})
+		return
 	}
-	if filters.SortOrder == "" {
-		filters.SortOrder = "asc"
+	switch {
+	case filters.After != "" && filters.Before != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCursorConflict})
+		return
+	case (filters.After != "" || filters.Before != "") && filters.Offset != 0:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCursorOffset})
+		return
+	case filters.Offset < 0 || filters.Offset > maxOffset:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errOffsetTooDeep})
+		return
 	}
-
-	db := database.GetDB()
-	query := db.Model(&models.Task{})
-
-	// Apply filters
-	if filters.Category != "" {
-		query = query.Where("category = ?", filters.Category)
+	if err := checkSearch(filters.Search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
-	if filters.Priority != "" {
-		query = query.Where("priority = ?", filters.Priority)
+	// Window for expanding upcoming occurrences of recurring tasks
+	var expandFrom, expandTo time.Time
+	if filters.Expand {
+		var err error
+		expandFrom, expandTo, err = occurrenceWindow(filters.From, filters.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
-	if filters.Status != "" {
-		query = query.Where("status = ?", filters.Status)
+	tasks, page, err := h.tasks.List(c.Request.Context(), currentWorkspaceID(c), taskListFilter(filters))
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sortBy: " + filters.SortBy})
+		return
 	}
-
-	if filters.Completed != nil {
-		query = query.Where("completed = ?", *filters.Completed)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidCursor})
+		return
 	}
-
-	if filters.ProjectID != "" {
-		if projectUUID, err := uuid.Parse(filters.ProjectID); err == nil {
-			query = query.Where("project_id = ?", projectUUID)
-		}
+	if writeQueryError(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
+		return
 	}
 
-	// Search in title and description
+	response := gin.H{
+		"tasks": tasks,
+		"pagination": gin.H{
+			"total":       page.Total,
+			"limit":       filters.Limit,
+			"offset":      filters.Offset,
+			"next_cursor": page.NextCursor,
+			"prev_cursor": page.PrevCursor,
+		},
+	}
 	if filters.Search != "" {
-		searchTerm := "%" + strings.ToLower(filters.Search) + "%"
-		query = query.Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchTerm, searchTerm)
-	}
-
-	// Date filtering
-	now := time.Now()
-	switch filters.DateFilter {
-	case "today":
-		today := now.Format("2006-01-02")
-		query = query.Where("due_date = ?", today)
-	case "tomorrow":
-		tomorrow := now.AddDate(0, 0, 1).Format("2006-01-02")
-		query = query.Where("due_date = ?", tomorrow)
-	case "this-week":
-		weekEnd := now.AddDate(0, 0, 7-int(now.Weekday()))
-		query = query.Where("due_date BETWEEN ? AND ?", now.Format("2006-01-02"), weekEnd.Format("2006-01-02"))
-	case "overdue":
-		yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
-		query = query.Where("due_date <= ? AND completed = false", yesterday)
-	}
-
-	// Tag filtering
-	if filters.Tags != "" {
-		tags := strings.Split(filters.Tags, ",")
-		for _, tag := range tags {
-			tag = strings.TrimSpace(tag)
-			if tag != "" {
-				query = query.Where("? = ANY(tags)", tag)
-			}
+		ids := make([]uuid.UUID, len(tasks))
+		for i, task := range tasks {
+			ids[i] = task.ID
+		}
+		highlights, err := h.tasks.Highlights(c.Request.Context(), currentWorkspaceID(c), filters.Search, ids)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to highlight search matches"})
+			return
 		}
+		response["highlights"] = highlights
+	}
+	if filters.Expand {
+		response["occurrences"] = expandOccurrences(tasks, expandFrom, expandTo)
 	}
 
-	sortColumn := getColumnName(filters.SortBy) // Convert camelCase to snake_case
-	orderClause := fmt.Sprintf("%s %s", sortColumn, filters.SortOrder)
+	setLinkHeader(c, page)
 
-	if filters.SortBy == "priority" {
-		// Custom priority ordering
-		orderClause = "CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 END"
-		if filters.SortOrder == "asc" {
-			orderClause += " ASC"
-		} else {
-			orderClause += " DESC"
-		}
+	c.JSON(http.StatusOK, response)
+}
+
+// setTaskFilterDefaults fills in the page size and sort of a task list
+func setTaskFilterDefaults(filters *models.TaskFilters) {
+	if filters.Limit == 0 {
+		filters.Limit = 50
 	}
+	// Searches are ranked by relevance unless a sort field is given
+	if filters.SortBy == "" && filters.Search == "" {
+		filters.SortBy = "dueDate"
+	}
+	if filters.SortOrder == "" {
+		filters.SortOrder = "asc"
+	}
+}
 
-	var tasks []models.Task
-	var total int64
+// taskListFilter turns the query parameters of a task list into the filter
+// the repository lists tasks by
+func taskListFilter(filters models.TaskFilters) repository.TaskFilter {
+	filter := repository.TaskFilter{
+		ListOptions: repository.ListOptions{
+			Search: filters.Search,
+			Query:  filters.Q,
+			Tags:   splitTags(filters.Tags),
+			Limit:  filters.Limit,
+			Offset: filters.Offset,
+			After:  filters.After,
+			Before: filters.Before,
+		},
+		Category:   filters.Category,
+		Priority:   filters.Priority,
+		Status:     filters.Status,
+		Completed:  filters.Completed,
+		Blocked:    filters.Blocked,
+		DateFilter: filters.DateFilter,
+	}
+	if filters.SortBy != "" {
+		filter.Sort = []repository.SortField{{Field: filters.SortBy, Desc: filters.SortOrder == "desc"}}
+	}
+	if projectUUID, err := uuid.Parse(filters.ProjectID); err == nil {
+		filter.ProjectID = &projectUUID
+	}
+	return filter
+}
 
-	// Get total count
-	query.Count(&total)
+// occurrenceWindow parses the from/to window used to expand recurring tasks,
+// defaulting to the next 30 days and allowing at most a year
+func occurrenceWindow(fromParam, toParam string) (time.Time, time.Time, error) {
+	from := time.Now()
+	if fromParam != "" {
+		parsed, err := time.Parse("2006-01-02", fromParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
 
-	// Apply pagination and sorting
-	result := query.Order(orderClause).Limit(filters.Limit).Offset(filters.Offset).Find(&tasks)
+	to := from.AddDate(0, 0, 30)
+	if toParam != "" {
+		parsed, err := time.Parse("2006-01-02", toParam)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("Invalid to date, expected YYYY-MM-DD")
+		}
+		to = parsed
+	}
 
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tasks"})
-		return
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Invalid window: to must not be before from")
+	}
+	if to.After(from.AddDate(1, 0, 0)) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Occurrence window cannot exceed one year")
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"tasks": tasks,
-		"pagination": gin.H{
-			"total":  total,
-			"limit":  filters.Limit,
-			"offset": filters.Offset,
-		},
-	})
+	return from, to, nil
 }
 
 // GetTaskByID retrieves a single task by ID
-func GetTaskByID(c *gin.Context) {
+func (h *TaskHandler) GetTaskByID(c *gin.Context) {
 	taskID := c.Param("id")
 
 	taskUUID, err := uuid.Parse(taskID)
@@ -159,29 +218,41 @@ func GetTaskByID(c *gin.Context) {
 		return
 	}
 
-	var task models.Task
-	db := database.GetDB()
-
-	result := db.First(&task, "id = ?", taskUUID)
-	if result.Error != nil {
+	ctx := c.Request.Context()
+	task, err := h.tasks.Get(ctx, currentWorkspaceID(c), taskUUID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"task": task})
-}
-
-// CreateTask creates a new task
-func CreateTask(c *gin.Context) {
-	var req models.CreateTaskRequest
+	subtasks, err := h.tasks.Subtasks(ctx, taskUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch subtasks"})
+		return
+	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	checklist, err := h.tasks.Checklist(ctx, taskUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch checklist"})
 		return
 	}
 
+	c.JSON(http.StatusOK, gin.H{
+		"task":      task,
+		"subtasks":  subtasks,
+		"checklist": checklist,
+		"progress":  taskProgress(*task, subtasks, checklist),
+	})
+}
+
+// newTaskFromRequest builds a task from a create request, applying defaults
+// and validation. On failure it writes the error response and returns false.
+// The project is left to the caller to validate.
+func newTaskFromRequest(c *gin.Context, req models.CreateTaskRequest) (*models.Task, bool) {
 	// Create task model
 	task := models.Task{
+		UserID:      currentUserID(c),
+		WorkspaceID: currentWorkspaceID(c),
 		Title:       req.Title,
 		Description: req.Description,
 		DueDate:     req.DueDate,
@@ -193,6 +264,19 @@ func CreateTask(c *gin.Context) {
 		Completed:   false,
 	}
 
+	// Recurring tasks start a new series at their first due date
+	if req.Recurrence != nil && *req.Recurrence != "" {
+		rule, err := normalizeRecurrence(*req.Recurrence)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+			return nil, false
+		}
+		task.ID = uuid.New()
+		task.Recurrence = &rule
+		task.SeriesID = &task.ID
+		task.SeriesStart = &task.DueDate
+	}
+
 	// Set defaults if empty
 	if task.Priority == "" {
 		task.Priority = "medium"
@@ -204,10 +288,47 @@ func CreateTask(c *gin.Context) {
 		task.Status = "pending"
 	}
 
-	db := database.GetDB()
-	result := db.Create(&task)
+	return &task, true
+}
+
+// validateProject makes sure a task is being assigned to an existing, active
+// project of its workspace. On failure it writes the error response and
+// returns false.
+func (h *TaskHandler) validateProject(c *gin.Context, projectID uuid.UUID) bool {
+	project, err := h.tasks.Project(c.Request.Context(), currentWorkspaceID(c), projectID)
+	if errors.Is(err, repository.ErrNotFound) {
+		project = nil
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check project"})
+		return false
+	}
+	if msg := taskProjectError(project); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return false
+	}
+	return true
+}
+
+// CreateTask creates a new task
+func (h *TaskHandler) CreateTask(c *gin.Context) {
+	var req models.CreateTaskRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	task, ok := newTaskFromRequest(c, req)
+	if !ok {
+		return
+	}
 
-	if result.Error != nil {
+	// Tasks can only be assigned to existing projects
+	if task.ProjectID != nil && !h.validateProject(c, *task.ProjectID) {
+		return
+	}
+
+	if err := h.tasks.Create(c.Request.Context(), currentActor(c), task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
 		return
 	}
@@ -216,7 +337,7 @@ func CreateTask(c *gin.Context) {
 }
 
 // UpdateTask updates an existing task
-func UpdateTask(c *gin.Context) {
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
 	taskID := c.Param("id")
 
 	taskUUID, err := uuid.Parse(taskID)
@@ -231,66 +352,121 @@ func UpdateTask(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-	var task models.Task
+	ctx := c.Request.Context()
 
 	// Check if task exists
-	if result := db.First(&task, "id = ?", taskUUID); result.Error != nil {
+	task, err := h.tasks.Get(ctx, currentWorkspaceID(c), taskUUID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
 
 	// Update fields only if provided
-	updates := make(map[string]interface{})
-
-	if req.Title != nil {
-		updates["title"] = *req.Title
+	update := repository.TaskUpdate{
+		Title:       req.Title,
+		Description: req.Description,
+		Completed:   req.Completed,
+		DueDate:     req.DueDate,
+		Priority:    req.Priority,
+		Category:    req.Category,
+		Status:      req.Status,
+		ProjectID:   req.ProjectID,
+		Tags:        req.Tags,
 	}
-	if req.Description != nil {
-		updates["description"] = *req.Description
+	// Auto-update status when marked as completed
+	if req.Completed != nil && *req.Completed && task.Status != "completed" && req.Status == nil {
+		completed := "completed"
+		update.Status = &completed
 	}
-	if req.Completed != nil {
-		updates["completed"] = *req.Completed
-		// Auto-update status when marked as completed
-		if *req.Completed && task.Status != "completed" {
-			updates["status"] = "completed"
+	if req.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *req.ProjectID) {
+		if !h.validateProject(c, *req.ProjectID) {
+			return
 		}
 	}
-	if req.DueDate != nil {
-		updates["due_date"] = *req.DueDate
-	}
-	if req.Priority != nil {
-		updates["priority"] = *req.Priority
-	}
-	if req.Category != nil {
-		updates["category"] = *req.Category
-	}
-	if req.Status != nil {
-		updates["status"] = *req.Status
+	if req.Recurrence != nil {
+		if *req.Recurrence == "" {
+			update.ClearRecurrence = true
+		} else {
+			rule, err := normalizeRecurrence(*req.Recurrence)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recurrence: " + err.Error()})
+				return
+			}
+			// A changed rule starts a new series from this task
+			if task.Recurrence == nil || *task.Recurrence != rule {
+				start := task.DueDate
+				if req.DueDate != nil {
+					start = *req.DueDate
+				}
+				update.Recurrence = &rule
+				update.SeriesID = &task.ID
+				update.SeriesStart = &start
+			}
+		}
 	}
-	if req.ProjectID != nil {
-		updates["project_id"] = *req.ProjectID
+
+	wasCompleted := task.Completed || task.Status == "completed"
+	completing := !wasCompleted && ((update.Completed != nil && *update.Completed) || (update.Status != nil && *update.Status == "completed"))
+
+	// A blocked task can only be started or completed when force=true is given
+	if update.Status != nil && (*update.Status == "in-progress" || *update.Status == "completed") && *update.Status != task.Status {
+		if c.Query("force") != "true" {
+			blockers, err := h.tasks.OpenBlockers(ctx, task.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check dependencies"})
+				return
+			}
+			if len(blockers) > 0 {
+				c.JSON(http.StatusConflict, gin.H{
+					"error":     "Task is blocked by unfinished tasks; finish them first or retry with force=true",
+					"blockedBy": blockers,
+				})
+				return
+			}
+		}
 	}
-	if req.Tags != nil {
-		updates["tags"] = pq.StringArray(*req.Tags)
+
+	// A parent with open subtasks at any depth is only completed when
+	// cascade=true is given
+	cascade := c.Query("cascade") == "true"
+	if completing && !cascade {
+		openSubtasks, err := h.tasks.CountOpenDescendants(ctx, task.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check subtasks"})
+			return
+		}
+		if openSubtasks > 0 {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":        "Task has open subtasks; complete them first or retry with cascade=true",
+				"openSubtasks": openSubtasks,
+			})
+			return
+		}
 	}
+	update.CompleteDescendants = completing && cascade
 
-	// Update timestamp
-	updates["updated_at"] = time.Now()
+	// Completing a recurring task schedules its next occurrence along with
+	// the update
+	if completing {
+		update.NextOccurrence = nextOccurrence
+	}
 
-	result := db.Model(&task).Updates(updates)
-	if result.Error != nil {
+	task, next, err := h.tasks.Update(ctx, currentActor(c), taskUUID, update)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
 		return
 	}
 
-	// Fetch updated task
-	db.First(&task, "id = ?", taskUUID)
-	c.JSON(http.StatusOK, gin.H{"task": task})
+	response := gin.H{"task": task}
+	if next != nil {
+		response["nextOccurrence"] = next
+	}
+
+	c.JSON(http.StatusOK, response)
 }
 
-// DeleteTask deletes a task by ID
-func DeleteTask(c *gin.Context) {
+// DeleteTask moves a task and its subtasks to the trash
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
 	taskID := c.Param("id")
 
 	taskUUID, err := uuid.Parse(taskID)
@@ -299,54 +475,32 @@ func DeleteTask(c *gin.Context) {
 		return
 	}
 
-	db := database.GetDB()
-	result := db.Delete(&models.Task{}, "id = ?", taskUUID)
-
-	if result.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
+	deleted, err := h.tasks.Delete(c.Request.Context(), currentActor(c), taskUUID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
 		return
 	}
-
-	if result.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Task moved to trash",
+		"deleted": deleted,
+	})
 }
 
 // GetTaskStats returns task statistics
-func GetTaskStats(c *gin.Context) {
-	db := database.GetDB()
+func (h *TaskHandler) GetTaskStats(c *gin.Context) {
+	// topLevel=true leaves subtasks out of every count
+	topLevel := c.Query("topLevel") == "true"
 
-	var stats struct {
-		Total          int64 `json:"total"`
-		Today          int64 `json:"today"`
-		TodayCompleted int64 `json:"todayCompleted"`
-		InProgress     int64 `json:"inProgress"`
-		Completed      int64 `json:"completed"`
-		Overdue        int64 `json:"overdue"`
+	stats, err := h.tasks.Stats(c.Request.Context(), currentWorkspaceID(c), topLevel)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate task stats"})
+		return
 	}
 
-	// Total tasks
-	db.Model(&models.Task{}).Count(&stats.Total)
-
-	// Today's tasks
-	today := time.Now().Format("2006-01-02")
-	db.Model(&models.Task{}).Where("due_date = ?", today).Count(&stats.Today)
-
-	// Today's completed tasks
-	db.Model(&models.Task{}).Where("due_date = ? AND completed = true", today).Count(&stats.TodayCompleted)
-
-	// In progress tasks
-	db.Model(&models.Task{}).Where("completed = false AND status != 'cancelled'").Count(&stats.InProgress)
-
-	// Completed tasks
-	db.Model(&models.Task{}).Where("completed = true").Count(&stats.Completed)
-
-	// Overdue tasks
-	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
-	db.Model(&models.Task{}).Where("due_date <= ? AND completed = false", yesterday).Count(&stats.Overdue)
-
 	c.JSON(http.StatusOK, gin.H{"stats": stats})
 }
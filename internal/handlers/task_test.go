@@ -0,0 +1,580 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"diary-backend/internal/middleware"
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// testServer serves the task, resource, search and view endpoints from
+// in-memory repositories for a user of one workspace. Requests are made as
+// userID with role, which tests may change between requests.
+type testServer struct {
+	router      *gin.Engine
+	repos       repository.Repositories
+	userID      uuid.UUID
+	workspaceID uuid.UUID
+	role        string
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	s := &testServer{
+		router:      gin.New(),
+		repos:       repository.NewMemory(),
+		userID:      uuid.New(),
+		workspaceID: uuid.New(),
+		role:        models.RoleEditor,
+	}
+	s.router.Use(func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, s.userID)
+		c.Set(middleware.WorkspaceIDKey, s.workspaceID)
+		c.Set(middleware.WorkspaceRoleKey, s.role)
+	})
+
+	taskHandler := NewTaskHandler(s.repos.Tasks)
+	tasks := s.router.Group("/tasks")
+	tasks.GET("", taskHandler.GetTasks)
+	tasks.GET("/stats", taskHandler.GetTaskStats)
+	tasks.GET("/:id", taskHandler.GetTaskByID)
+	tasks.POST("", taskHandler.CreateTask)
+	tasks.PUT("/:id", taskHandler.UpdateTask)
+	tasks.DELETE("/:id", taskHandler.DeleteTask)
+	tasks.GET("/trash", taskHandler.GetTaskTrash)
+	tasks.DELETE("/trash/:id", taskHandler.PurgeTask)
+	tasks.POST("/:id/restore", taskHandler.RestoreTask)
+	tasks.GET("/:id/versions", taskHandler.GetTaskVersions)
+	tasks.POST("/:id/versions/:n/revert", taskHandler.RevertTaskVersion)
+	tasks.POST("/:id/subtasks", taskHandler.CreateSubtask)
+	tasks.GET("/:id/checklist", taskHandler.GetChecklist)
+	tasks.POST("/:id/checklist", taskHandler.CreateChecklistItem)
+	tasks.POST("/:id/dependencies", taskHandler.CreateTaskDependency)
+	tasks.GET("/:id/dependencies", taskHandler.GetTaskDependencies)
+
+	resourceHandler := NewResourceHandler(s.repos.Resources)
+	resources := s.router.Group("/resources")
+	resources.GET("", resourceHandler.GetResources)
+	resources.GET("/:id", resourceHandler.GetResourceByID)
+	resources.POST("", resourceHandler.CreateResource)
+	resources.PUT("/:id", resourceHandler.UpdateResource)
+	resources.DELETE("/:id", resourceHandler.DeleteResource)
+	resources.PATCH("/:id/status", resourceHandler.UpdateResourceStatus)
+	resources.PATCH("/:id/rating", resourceHandler.UpdateResourceRating)
+	resources.POST("/import-url", resourceHandler.ImportFromURL)
+	resources.POST("/:id/restore", resourceHandler.RestoreResource)
+	resources.POST("/:id/sessions", resourceHandler.CreateSession)
+	resources.GET("/:id/sessions", resourceHandler.GetResourceSessions)
+
+	s.router.GET("/search", NewSearchHandler(s.repos.Tasks, s.repos.Resources).Search)
+
+	viewHandler := NewViewHandler(s.repos.Views, s.repos.Tasks, s.repos.Resources)
+	views := s.router.Group("/views")
+	views.GET("", viewHandler.GetViews)
+	views.GET("/:id", viewHandler.GetViewByID)
+	views.POST("", viewHandler.CreateView)
+	views.PUT("/:id", viewHandler.UpdateView)
+	views.DELETE("/:id", viewHandler.DeleteView)
+	views.GET("/:id/items", viewHandler.GetViewItems)
+	return s
+}
+
+// do sends a request with an optional JSON body and decodes the JSON response
+// into out when it is not nil
+func (s *testServer) do(t *testing.T, method, path string, body any, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	if out != nil && w.Code < 300 {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w
+}
+
+// expect fails the test when a response does not have the wanted status
+func expect(t *testing.T, w *httptest.ResponseRecorder, status int) {
+	t.Helper()
+	if w.Code != status {
+		t.Fatalf("status = %d, want %d: %s", w.Code, status, w.Body.String())
+	}
+}
+
+// createTask creates a task through the API and returns it
+func (s *testServer) createTask(t *testing.T, body gin.H) models.Task {
+	t.Helper()
+	var out struct {
+		Task models.Task `json:"task"`
+	}
+	expect(t, s.do(t, http.MethodPost, "/tasks", body, &out), http.StatusCreated)
+	return out.Task
+}
+
+// taskTitles lists the titles of a task list response
+func taskTitles(tasks []models.Task) []string {
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return titles
+}
+
+func TestCreateTaskDefaults(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Write report", "dueDate": "2026-03-02T00:00:00Z"})
+	if task.ID == uuid.Nil || task.Priority != "medium" || task.Category != "personal" || task.Status != "pending" {
+		t.Errorf("task = %+v, want an ID and the default priority, category and status", task)
+	}
+
+	stored, err := s.repos.Tasks.Get(t.Context(), s.workspaceID, task.ID)
+	if err != nil {
+		t.Fatalf("created task not stored: %v", err)
+	}
+	if stored.UserID != s.userID {
+		t.Errorf("stored task belongs to %s, want %s", stored.UserID, s.userID)
+	}
+}
+
+func TestCreateTaskValidation(t *testing.T) {
+	s := newTestServer(t)
+	tests := []struct {
+		name string
+		body any
+	}{
+		{"malformed body", "not an object"},
+		{"invalid recurrence", gin.H{"title": "Standup", "dueDate": "2026-03-02T00:00:00Z", "recurrence": "FREQ=HOURLY"}},
+		{"unknown project", gin.H{"title": "Plan", "dueDate": "2026-03-02T00:00:00Z", "projectId": uuid.New()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expect(t, s.do(t, http.MethodPost, "/tasks", tt.body, nil), http.StatusBadRequest)
+		})
+	}
+}
+
+func TestGetTaskByID(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Read paper", "dueDate": "2026-03-02T00:00:00Z"})
+
+	var out struct {
+		Task     models.Task   `json:"task"`
+		Subtasks []models.Task `json:"subtasks"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks/"+task.ID.String(), nil, &out), http.StatusOK)
+	if out.Task.ID != task.ID || out.Task.Title != "Read paper" || len(out.Subtasks) != 0 {
+		t.Errorf("response = %+v, want the task without subtasks", out)
+	}
+
+	expect(t, s.do(t, http.MethodGet, "/tasks/not-a-uuid", nil, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodGet, "/tasks/"+uuid.NewString(), nil, nil), http.StatusNotFound)
+}
+
+func TestTasksAreScopedToTheWorkspace(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Private", "dueDate": "2026-03-02T00:00:00Z"})
+
+	s.workspaceID = uuid.New()
+	expect(t, s.do(t, http.MethodGet, "/tasks/"+task.ID.String(), nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+task.ID.String(), gin.H{"title": "Taken"}, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodDelete, "/tasks/"+task.ID.String(), nil, nil), http.StatusNotFound)
+
+	var out struct {
+		Tasks []models.Task `json:"tasks"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks", nil, &out), http.StatusOK)
+	if len(out.Tasks) != 0 {
+		t.Errorf("other workspace lists %v", taskTitles(out.Tasks))
+	}
+}
+
+func TestGetTasksFilters(t *testing.T) {
+	s := newTestServer(t)
+	s.createTask(t, gin.H{"title": "Fix login", "dueDate": "2026-03-03T00:00:00Z", "priority": "high", "category": "office", "tags": []string{"auth", "bug"}})
+	s.createTask(t, gin.H{"title": "Write tests", "dueDate": "2026-03-01T00:00:00Z", "priority": "low", "category": "office", "tags": []string{"bug"}})
+	s.createTask(t, gin.H{"title": "Buy groceries", "dueDate": "2026-03-02T00:00:00Z", "priority": "high"})
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Write tests", "Buy groceries", "Fix login"}},
+		{"?sortOrder=desc", []string{"Fix login", "Buy groceries", "Write tests"}},
+		{"?category=office", []string{"Write tests", "Fix login"}},
+		{"?priority=high", []string{"Buy groceries", "Fix login"}},
+		{"?tags=bug", []string{"Write tests", "Fix login"}},
+		{"?tags=bug,auth", []string{"Fix login"}},
+		{"?sortBy=title", []string{"Buy groceries", "Fix login", "Write tests"}},
+		{"?q=priority:high+category:office", []string{"Fix login"}},
+		{"?limit=2", []string{"Write tests", "Buy groceries"}},
+		{"?limit=2&offset=2", []string{"Fix login"}},
+	}
+	for _, tt := range tests {
+		var out struct {
+			Tasks []models.Task `json:"tasks"`
+		}
+		expect(t, s.do(t, http.MethodGet, "/tasks"+tt.query, nil, &out), http.StatusOK)
+		if got := taskTitles(out.Tasks); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("GET /tasks%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestGetTasksRejectsInvalidParameters(t *testing.T) {
+	s := newTestServer(t)
+	for _, query := range []string{
+		"?sortOrder=sideways",
+		"?sortBy=colour",
+		"?limit=abc",
+		"?after=a&before=b",
+		"?after=a&offset=10",
+		"?offset=-1",
+		"?offset=10001",
+		"?after=not-a-cursor",
+		"?q=priority:",
+		"?search=!!!",
+		"?search=-+*",
+		"?expand=true&from=yesterday",
+	} {
+		if w := s.do(t, http.MethodGet, "/tasks"+query, nil, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /tasks%s = %d, want 400: %s", query, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestUpdateTask(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Draft", "dueDate": "2026-03-02T00:00:00Z"})
+
+	var out struct {
+		Task models.Task `json:"task"`
+	}
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+task.ID.String(), gin.H{"title": "Final", "completed": true}, &out), http.StatusOK)
+	if out.Task.Title != "Final" || !out.Task.Completed || out.Task.Status != "completed" {
+		t.Errorf("task = %+v, want it renamed and completed", out.Task)
+	}
+
+	expect(t, s.do(t, http.MethodPut, "/tasks/not-a-uuid", gin.H{"title": "x"}, nil), http.StatusBadRequest)
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+uuid.NewString(), gin.H{"title": "x"}, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+task.ID.String(), gin.H{"recurrence": "FREQ=SOMETIMES"}, nil), http.StatusBadRequest)
+}
+
+func TestCompletingRecurringTaskCreatesNextOccurrence(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Water plants", "dueDate": "2026-03-02T00:00:00Z", "recurrence": "FREQ=WEEKLY"})
+
+	var out struct {
+		NextOccurrence *models.Task `json:"nextOccurrence"`
+	}
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+task.ID.String(), gin.H{"completed": true}, &out), http.StatusOK)
+	if out.NextOccurrence == nil {
+		t.Fatal("no next occurrence")
+	}
+	want := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+	if !out.NextOccurrence.DueDate.Equal(want) || out.NextOccurrence.Completed {
+		t.Errorf("next occurrence = %+v, want an open task due %s", out.NextOccurrence, want)
+	}
+	if _, err := s.repos.Tasks.Get(t.Context(), s.workspaceID, out.NextOccurrence.ID); err != nil {
+		t.Errorf("next occurrence not stored: %v", err)
+	}
+}
+
+func TestCompletingParentWithOpenSubtasks(t *testing.T) {
+	s := newTestServer(t)
+	parent := s.createTask(t, gin.H{"title": "Release", "dueDate": "2026-03-02T00:00:00Z"})
+	actor := repository.Actor{WorkspaceID: s.workspaceID, UserID: s.userID}
+	subtask := models.Task{WorkspaceID: s.workspaceID, UserID: s.userID, Title: "Changelog", ParentID: &parent.ID, Status: "completed", Completed: true}
+	if err := s.repos.Tasks.Create(t.Context(), actor, &subtask); err != nil {
+		t.Fatal(err)
+	}
+	// Only the grandchild is open
+	nested := models.Task{WorkspaceID: s.workspaceID, UserID: s.userID, Title: "Credits", ParentID: &subtask.ID, Status: "pending"}
+	if err := s.repos.Tasks.Create(t.Context(), actor, &nested); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/tasks/" + parent.ID.String()
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"completed": true}, nil), http.StatusConflict)
+	expect(t, s.do(t, http.MethodPut, path+"?cascade=true", gin.H{"completed": true}, nil), http.StatusOK)
+
+	stored, err := s.repos.Tasks.Get(t.Context(), s.workspaceID, nested.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored.Completed {
+		t.Error("cascade did not complete the nested subtask")
+	}
+}
+
+func TestDeleteTask(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Old", "dueDate": "2026-03-02T00:00:00Z"})
+
+	var out struct {
+		Deleted int64 `json:"deleted"`
+	}
+	expect(t, s.do(t, http.MethodDelete, "/tasks/"+task.ID.String(), nil, &out), http.StatusOK)
+	if out.Deleted != 1 {
+		t.Errorf("deleted = %d, want 1", out.Deleted)
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks/"+task.ID.String(), nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodDelete, "/tasks/"+task.ID.String(), nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodDelete, "/tasks/not-a-uuid", nil, nil), http.StatusBadRequest)
+}
+
+func TestGetTaskStats(t *testing.T) {
+	s := newTestServer(t)
+	s.createTask(t, gin.H{"title": "Open", "dueDate": "2026-03-02T00:00:00Z"})
+	done := s.createTask(t, gin.H{"title": "Done", "dueDate": "2026-03-02T00:00:00Z"})
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+done.ID.String(), gin.H{"completed": true}, nil), http.StatusOK)
+
+	var out struct {
+		Stats repository.TaskStats `json:"stats"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks/stats", nil, &out), http.StatusOK)
+	if out.Stats.Total != 2 || out.Stats.Completed != 1 {
+		t.Errorf("stats = %+v, want 2 tasks with 1 completed", out.Stats)
+	}
+}
+
+func TestCompletingTaskWithBrokenRuleChangesNothing(t *testing.T) {
+	s := newTestServer(t)
+	broken := "FREQ=HOURLY"
+	task := models.Task{WorkspaceID: s.workspaceID, UserID: s.userID, Title: "Imported", DueDate: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Status: "pending", Recurrence: &broken}
+	if err := s.repos.Tasks.Create(t.Context(), repository.Actor{WorkspaceID: s.workspaceID, UserID: s.userID}, &task); err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, s.do(t, http.MethodPut, "/tasks/"+task.ID.String(), gin.H{"completed": true}, nil), http.StatusInternalServerError)
+	stored, err := s.repos.Tasks.Get(t.Context(), s.workspaceID, task.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Completed || stored.Status != "pending" {
+		t.Errorf("task = %+v, want the failed update not to be saved", stored)
+	}
+}
+
+func TestCompletingRecurringTaskTwiceCreatesOneOccurrence(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Backup", "dueDate": "2026-03-02T00:00:00Z", "recurrence": "FREQ=DAILY"})
+	path := "/tasks/" + task.ID.String()
+
+	var out struct {
+		NextOccurrence *models.Task `json:"nextOccurrence"`
+	}
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"completed": true}, &out), http.StatusOK)
+	if out.NextOccurrence == nil {
+		t.Fatal("no next occurrence")
+	}
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"completed": false, "status": "pending"}, nil), http.StatusOK)
+	out.NextOccurrence = nil
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"completed": true}, &out), http.StatusOK)
+	if out.NextOccurrence != nil {
+		t.Errorf("second completion created %+v, want the existing occurrence kept", out.NextOccurrence)
+	}
+
+	var list struct {
+		Tasks []models.Task `json:"tasks"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks", nil, &list), http.StatusOK)
+	if len(list.Tasks) != 2 {
+		t.Errorf("tasks = %v, want the task and one occurrence", taskTitles(list.Tasks))
+	}
+}
+
+func TestGetTasksLinkHeader(t *testing.T) {
+	s := newTestServer(t)
+	for _, day := range []string{"01", "02", "02", "03", "04"} {
+		s.createTask(t, gin.H{"title": "Task due " + day, "dueDate": "2026-03-" + day + "T00:00:00Z"})
+	}
+
+	var pages [][]string
+	path := "/tasks?limit=2&sortOrder=desc"
+	for path != "" {
+		var out struct {
+			Tasks []models.Task `json:"tasks"`
+		}
+		w := s.do(t, http.MethodGet, path, nil, &out)
+		expect(t, w, http.StatusOK)
+		pages = append(pages, taskTitles(out.Tasks))
+
+		link := w.Header().Get("Link")
+		if len(pages) > 1 && !strings.Contains(link, `rel="prev"`) {
+			t.Errorf("page %d has no prev link: %q", len(pages), link)
+		}
+		path = ""
+		if match := regexp.MustCompile(`<([^>]*)>; rel="next"`).FindStringSubmatch(link); match != nil {
+			path = match[1]
+			if !strings.Contains(path, "limit=2") || !strings.Contains(path, "sortOrder=desc") || !strings.Contains(path, "after=") {
+				t.Errorf("next link %q does not keep the query", path)
+			}
+		}
+		if len(pages) > 5 {
+			t.Fatal("next links do not end")
+		}
+	}
+
+	want := "[[Task due 04 Task due 03] [Task due 02 Task due 02] [Task due 01]]"
+	if fmt.Sprint(pages) != want {
+		t.Errorf("pages = %v, want %s", pages, want)
+	}
+}
+
+func TestListLimits(t *testing.T) {
+	s := newTestServer(t)
+	for _, path := range []string{"/tasks", "/resources", "/search?q=go"} {
+		separator := "?"
+		if strings.Contains(path, "?") {
+			separator = "&"
+		}
+		for _, limit := range []string{"-1", "0", "101", "1000000", "ten"} {
+			if w := s.do(t, http.MethodGet, path+separator+"limit="+limit, nil, nil); w.Code != http.StatusBadRequest {
+				t.Errorf("GET %s with limit=%s = %d, want 400", path, limit, w.Code)
+			}
+		}
+		for _, limit := range []string{"1", "100"} {
+			expect(t, s.do(t, http.MethodGet, path+separator+"limit="+limit, nil, nil), http.StatusOK)
+		}
+	}
+}
+
+func TestTaskTrash(t *testing.T) {
+	s := newTestServer(t)
+	parent := s.createTask(t, gin.H{"title": "Release", "dueDate": "2026-03-02T00:00:00Z"})
+	var created struct {
+		Task models.Task `json:"task"`
+	}
+	expect(t, s.do(t, http.MethodPost, "/tasks/"+parent.ID.String()+"/subtasks", gin.H{"title": "Changelog", "dueDate": "2026-03-02T00:00:00Z"}, &created), http.StatusCreated)
+	subtask := created.Task
+	expect(t, s.do(t, http.MethodDelete, "/tasks/"+parent.ID.String(), nil, nil), http.StatusOK)
+
+	var trash struct {
+		Tasks []models.Task `json:"tasks"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks/trash", nil, &trash), http.StatusOK)
+	if len(trash.Tasks) != 2 {
+		t.Errorf("trash = %v, want the task and its subtask", taskTitles(trash.Tasks))
+	}
+
+	expect(t, s.do(t, http.MethodPost, "/tasks/"+subtask.ID.String()+"/restore", nil, nil), http.StatusConflict)
+	var restored struct {
+		Restored int64 `json:"restored"`
+	}
+	expect(t, s.do(t, http.MethodPost, "/tasks/"+parent.ID.String()+"/restore", nil, &restored), http.StatusOK)
+	if restored.Restored != 2 {
+		t.Errorf("restored = %d, want 2", restored.Restored)
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks/"+subtask.ID.String(), nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodPost, "/tasks/"+parent.ID.String()+"/restore", nil, nil), http.StatusNotFound)
+
+	// Only tasks in the trash can be purged
+	expect(t, s.do(t, http.MethodDelete, "/tasks/trash/"+parent.ID.String(), nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodDelete, "/tasks/"+parent.ID.String(), nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodDelete, "/tasks/trash/"+parent.ID.String(), nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodGet, "/tasks/trash", nil, &trash), http.StatusOK)
+	if len(trash.Tasks) != 0 {
+		t.Errorf("trash = %v, want it empty after purging the parent", taskTitles(trash.Tasks))
+	}
+}
+
+func TestChecklistItemsAreAppended(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Release", "dueDate": "2026-03-02T00:00:00Z"})
+	path := "/tasks/" + task.ID.String() + "/checklist"
+
+	expect(t, s.do(t, http.MethodPost, path, gin.H{"title": "Tag", "position": 5}, nil), http.StatusCreated)
+	var out struct {
+		Item models.ChecklistItem `json:"item"`
+	}
+	expect(t, s.do(t, http.MethodPost, path, gin.H{"title": "Announce"}, &out), http.StatusCreated)
+	if out.Item.Position != 6 {
+		t.Errorf("position = %d, want 6, after the last item", out.Item.Position)
+	}
+
+	var list struct {
+		Checklist []models.ChecklistItem `json:"checklist"`
+	}
+	expect(t, s.do(t, http.MethodGet, path, nil, &list), http.StatusOK)
+	if len(list.Checklist) != 2 || list.Checklist[1].Title != "Announce" {
+		t.Errorf("checklist = %+v, want Tag then Announce", list.Checklist)
+	}
+}
+
+func TestTaskDependencies(t *testing.T) {
+	s := newTestServer(t)
+	design := s.createTask(t, gin.H{"title": "Design", "dueDate": "2026-03-01T00:00:00Z"})
+	build := s.createTask(t, gin.H{"title": "Build", "dueDate": "2026-03-02T00:00:00Z"})
+	ship := s.createTask(t, gin.H{"title": "Ship", "dueDate": "2026-03-03T00:00:00Z"})
+	link := func(task, dependsOn models.Task) *httptest.ResponseRecorder {
+		return s.do(t, http.MethodPost, "/tasks/"+task.ID.String()+"/dependencies", gin.H{"dependsOnId": dependsOn.ID}, nil)
+	}
+
+	expect(t, link(build, design), http.StatusCreated)
+	expect(t, link(ship, build), http.StatusCreated)
+	expect(t, link(ship, build), http.StatusCreated)
+	expect(t, link(design, ship), http.StatusBadRequest)
+	expect(t, link(design, design), http.StatusBadRequest)
+	expect(t, link(design, models.Task{ID: uuid.New()}), http.StatusBadRequest)
+
+	var out struct {
+		BlockedBy []models.Task `json:"blockedBy"`
+		Blocking  []models.Task `json:"blocking"`
+		Blocked   bool          `json:"blocked"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/tasks/"+build.ID.String()+"/dependencies", nil, &out), http.StatusOK)
+	if fmt.Sprint(taskTitles(out.BlockedBy), taskTitles(out.Blocking)) != "[Design] [Ship]" || !out.Blocked {
+		t.Errorf("dependencies = %v blocking %v (blocked %v), want Design blocking Build blocking Ship", taskTitles(out.BlockedBy), taskTitles(out.Blocking), out.Blocked)
+	}
+}
+
+func TestRevertTaskVersion(t *testing.T) {
+	s := newTestServer(t)
+	task := s.createTask(t, gin.H{"title": "Draft", "dueDate": "2026-03-02T00:00:00Z", "priority": "low"})
+	path := "/tasks/" + task.ID.String()
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"title": "Final", "priority": "high"}, nil), http.StatusOK)
+
+	var versions struct {
+		Versions []models.TaskVersionSummary `json:"versions"`
+	}
+	expect(t, s.do(t, http.MethodGet, path+"/versions", nil, &versions), http.StatusOK)
+	if len(versions.Versions) != 1 || versions.Versions[0].Changes["title"].To != "Final" {
+		t.Fatalf("versions = %+v, want the rename", versions.Versions)
+	}
+
+	var out struct {
+		Task models.Task `json:"task"`
+	}
+	expect(t, s.do(t, http.MethodPost, path+"/versions/1/revert", nil, &out), http.StatusOK)
+	if out.Task.Title != "Draft" || out.Task.Priority != "low" {
+		t.Errorf("task = %+v, want it back to Draft with low priority", out.Task)
+	}
+	expect(t, s.do(t, http.MethodGet, path+"/versions", nil, &versions), http.StatusOK)
+	if len(versions.Versions) != 2 {
+		t.Errorf("versions = %d, want the replaced state kept as version 2", len(versions.Versions))
+	}
+	expect(t, s.do(t, http.MethodPost, path+"/versions/3/revert", nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodPost, path+"/versions/0/revert", nil, nil), http.StatusBadRequest)
+}
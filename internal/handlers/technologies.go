@@ -0,0 +1,578 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errTechnologyExists = errors.New("a technology with this name or alias already exists")
+	errTechnologyParent = errors.New("parent_id must refer to another technology that is not below this one")
+)
+
+// slugify turns a technology name into a URL-friendly slug, e.g. "Node.js" -> "node-js"
+func slugify(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+			dash = false
+		} else if r == '+' {
+			sb.WriteString("plus")
+			dash = false
+		} else if r == '#' {
+			sb.WriteString("sharp")
+			dash = false
+		} else if !dash && sb.Len() > 0 {
+			sb.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "-")
+}
+
+// normalizeAliases lower-cases and de-duplicates aliases, dropping the name itself
+func normalizeAliases(name string, aliases []string) pq.StringArray {
+	nameKey := repository.TechnologyKey(name)
+	seen := make(map[string]bool)
+	normalized := pq.StringArray{}
+	for _, alias := range aliases {
+		key := repository.TechnologyKey(alias)
+		if key == "" || key == nameKey || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, key)
+	}
+	return normalized
+}
+
+// checkTechnologyConflicts makes sure none of the names collides with another technology
+func checkTechnologyConflicts(db *gorm.DB, technology *models.Technology) error {
+	for _, value := range append([]string{technology.Name, technology.Slug}, technology.Aliases...) {
+		existing, err := repository.FindTechnology(db, technology.WorkspaceID, value)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.ID != technology.ID {
+			return errTechnologyExists
+		}
+	}
+	return nil
+}
+
+// checkTechnologyParent rejects parents of another workspace and parents that
+// would make the hierarchy circular
+func checkTechnologyParent(db *gorm.DB, technology *models.Technology) error {
+	for current := technology.ParentID; current != nil; {
+		if *current == technology.ID {
+			return errTechnologyParent
+		}
+		var parent models.Technology
+		if err := db.First(&parent, "id = ? AND workspace_id = ?", *current, technology.WorkspaceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errTechnologyParent
+			}
+			return err
+		}
+		current = parent.ParentID
+	}
+	return nil
+}
+
+// GetTechnologies lists every technology in use or registered, with resource
+// counts, completion ratio and total study minutes
+func GetTechnologies(c *gin.Context) {
+	db := dbFor(c)
+	workspaceID := currentWorkspaceID(c)
+
+	var rows []struct {
+		Name           string
+		ResourceCount  int64
+		CompletedCount int64
+		TotalMinutes   int64
+	}
+	err := db.Table("learning_resources AS r").
+		Select(`r.technology AS name,
+			COUNT(*) AS resource_count,
+			COUNT(*) FILTER (WHERE r.status = 'completed') AS completed_count,
+			COALESCE(SUM(s.minutes), 0) AS total_minutes`).
+		Joins(`LEFT JOIN (
+			SELECT resource_id, SUM(duration_minutes) AS minutes
+			FROM learning_sessions GROUP BY resource_id
+		) s ON s.resource_id = r.id`).
+		Where("r.workspace_id = ? AND r.deleted_at IS NULL", workspaceID).
+		Group("r.technology").
+		Scan(&rows).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch technologies"})
+		return
+	}
+
+	var registry []models.Technology
+	if err := db.Where("workspace_id = ?", workspaceID).Find(&registry).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch technologies"})
+		return
+	}
+
+	names := make(map[uuid.UUID]string, len(registry))
+	for _, technology := range registry {
+		names[technology.ID] = technology.Name
+	}
+
+	usage := make(map[string]*models.TechnologyUsage)
+	for _, technology := range registry {
+		id := technology.ID
+		entry := &models.TechnologyUsage{ID: &id, Name: technology.Name, Registered: true}
+		if technology.ParentID != nil {
+			entry.Parent = names[*technology.ParentID]
+		}
+		usage[technology.Name] = entry
+	}
+	for _, row := range rows {
+		entry, ok := usage[row.Name]
+		if !ok {
+			entry = &models.TechnologyUsage{Name: row.Name}
+			usage[row.Name] = entry
+		}
+		entry.ResourceCount = row.ResourceCount
+		entry.CompletedCount = row.CompletedCount
+		entry.TotalMinutes = row.TotalMinutes
+		if row.ResourceCount > 0 {
+			entry.CompletionRatio = roundTo(float64(row.CompletedCount)/float64(row.ResourceCount), 2)
+		}
+	}
+
+	technologies := make([]models.TechnologyUsage, 0, len(usage))
+	for _, entry := range usage {
+		technologies = append(technologies, *entry)
+	}
+	sort.Slice(technologies, func(i, j int) bool {
+		if technologies[i].ResourceCount != technologies[j].ResourceCount {
+			return technologies[i].ResourceCount > technologies[j].ResourceCount
+		}
+		return technologies[i].Name < technologies[j].Name
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"technologies": technologies,
+	})
+}
+
+// GetTechnologyRegistry lists the registered technologies
+func GetTechnologyRegistry(c *gin.Context) {
+	db := dbFor(c)
+	var technologies []models.Technology
+	if err := db.Where("workspace_id = ?", currentWorkspaceID(c)).Order("name ASC").Find(&technologies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch technologies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"technologies": technologies})
+}
+
+// CreateTechnology registers a technology with its aliases
+func CreateTechnology(c *gin.Context) {
+	var req models.TechnologyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := strings.Join(strings.Fields(req.Name), " ")
+	technology := models.Technology{
+		ID:          uuid.New(),
+		WorkspaceID: currentWorkspaceID(c),
+		Name:        name,
+		Slug:        slugify(name),
+		Aliases:     normalizeAliases(name, req.Aliases),
+		ParentID:    req.ParentID,
+	}
+
+	db := dbFor(c)
+	err := checkTechnologyConflicts(db, &technology)
+	if err == nil {
+		err = checkTechnologyParent(db, &technology)
+	}
+	if err == nil {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&technology).Error; err != nil {
+				return err
+			}
+			return recordActivity(tx, c, models.ActionCreate, models.EntityTechnology, technology.ID, nil, technology)
+		})
+	}
+	switch {
+	case errors.Is(err, errTechnologyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errTechnologyParent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create technology"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Technology created successfully",
+		"technology": technology,
+	})
+}
+
+// UpdateTechnology renames a technology or changes its aliases or parent.
+// Resources and goals using the old name are renamed with it.
+func UpdateTechnology(c *gin.Context) {
+	technologyUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid technology ID"})
+		return
+	}
+
+	var req models.TechnologyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	db := dbFor(c)
+	var technology models.Technology
+	if err := db.First(&technology, "id = ? AND workspace_id = ?", technologyUUID, currentWorkspaceID(c)).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Technology not found"})
+		return
+	}
+
+	before := technology
+	oldName := technology.Name
+	technology.Name = strings.Join(strings.Fields(req.Name), " ")
+	technology.Slug = slugify(technology.Name)
+	technology.Aliases = normalizeAliases(technology.Name, req.Aliases)
+	technology.ParentID = req.ParentID
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := checkTechnologyConflicts(tx, &technology); err != nil {
+			return err
+		}
+		if err := checkTechnologyParent(tx, &technology); err != nil {
+			return err
+		}
+		if err := tx.Save(&technology).Error; err != nil {
+			return err
+		}
+		if err := recordActivity(tx, c, models.ActionUpdate, models.EntityTechnology, technology.ID, before, technology); err != nil {
+			return err
+		}
+		if oldName == technology.Name {
+			return nil
+		}
+		_, err := renameTechnologyValue(tx, c, technology.WorkspaceID, oldName, technology.Name)
+		return err
+	})
+	switch {
+	case errors.Is(err, errTechnologyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, errTechnologyParent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update technology"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    "Technology updated successfully",
+		"technology": technology,
+	})
+}
+
+// DeleteTechnology removes a technology from the registry. Resources keep their technology value.
+func DeleteTechnology(c *gin.Context) {
+	technologyUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid technology ID"})
+		return
+	}
+
+	db := dbFor(c)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var technology models.Technology
+		if err := tx.First(&technology, "id = ? AND workspace_id = ?", technologyUUID, currentWorkspaceID(c)).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&technology).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, c, models.ActionDelete, models.EntityTechnology, technology.ID, technology, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Technology not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete technology"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Technology deleted successfully"})
+}
+
+// technologyRename records one value rewritten by a merge
+type technologyRename struct {
+	From             string `json:"from"`
+	To               string `json:"to"`
+	ResourcesUpdated int64  `json:"resources_updated"`
+	GoalsUpdated     int64  `json:"goals_updated"`
+}
+
+// renameTechnologyValue rewrites every resource and goal of a workspace using exactly value.
+// Trashed resources are included so they still match once restored.
+func renameTechnologyValue(tx *gorm.DB, c *gin.Context, workspaceID uuid.UUID, value, canonical string) (technologyRename, error) {
+	rename := technologyRename{From: value, To: canonical}
+	before := map[string]string{"technology": value}
+	after := map[string]string{"technology": canonical}
+	returningID := clause.Returning{Columns: []clause.Column{{Name: "id"}}}
+
+	var resources []models.Resource
+	result := tx.Unscoped().Model(&resources).Clauses(returningID).
+		Where("workspace_id = ? AND technology = ?", workspaceID, value).
+		Update("technology", canonical)
+	if result.Error != nil {
+		return rename, result.Error
+	}
+	rename.ResourcesUpdated = result.RowsAffected
+	for _, resource := range resources {
+		if err := recordActivity(tx, c, models.ActionUpdate, models.EntityResource, resource.ID, before, after); err != nil {
+			return rename, err
+		}
+	}
+
+	var goals []models.Goal
+	result = tx.Model(&goals).Clauses(returningID).
+		Where("workspace_id = ? AND technology = ?", workspaceID, value).
+		Update("technology", canonical)
+	if result.Error != nil {
+		return rename, result.Error
+	}
+	rename.GoalsUpdated = result.RowsAffected
+	for _, goal := range goals {
+		if err := recordActivity(tx, c, models.ActionUpdate, models.EntityGoal, goal.ID, before, after); err != nil {
+			return rename, err
+		}
+	}
+
+	return rename, nil
+}
+
+// distinctTechnologyValues returns every technology value used by a workspace's
+// resources and goals, with how many resources use it
+func distinctTechnologyValues(tx *gorm.DB, workspaceID uuid.UUID) (map[string]int64, error) {
+	var rows []struct {
+		Technology string
+		Uses       int64
+	}
+	err := tx.Raw(`
+		SELECT technology, SUM(uses) AS uses FROM (
+			SELECT technology, COUNT(*) FILTER (WHERE deleted_at IS NULL) AS uses
+			FROM learning_resources WHERE workspace_id = @workspace GROUP BY technology
+			UNION ALL
+			SELECT technology, 0 AS uses FROM learning_goals WHERE workspace_id = @workspace GROUP BY technology
+		) v GROUP BY technology`, sql.Named("workspace", workspaceID)).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		values[row.Technology] = row.Uses
+	}
+	return values, nil
+}
+
+// MergeTechnologies merges duplicate technology values. With sources and a
+// target, every source spelling (and registered source technology) is folded
+// into the target. Without sources, every existing value is normalized: to
+// its registered name, or else to the most used spelling among values that
+// differ only in case or spacing.
+func MergeTechnologies(c *gin.Context) {
+	var req models.MergeTechnologiesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Sources) > 0 && strings.TrimSpace(req.Target) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "target is required when sources are given"})
+		return
+	}
+
+	db := dbFor(c)
+	workspaceID := currentWorkspaceID(c)
+	renames := []technologyRename{}
+	var target *models.Technology
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		values, err := distinctTechnologyValues(tx, workspaceID)
+		if err != nil {
+			return err
+		}
+
+		if len(req.Sources) == 0 {
+			return mergeAllTechnologies(tx, c, workspaceID, values, &renames)
+		}
+
+		target, err = mergeIntoTarget(tx, c, workspaceID, req.Sources, req.Target, values, &renames)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to merge technologies"})
+		return
+	}
+
+	response := gin.H{
+		"message": "Technologies merged successfully",
+		"merged":  renames,
+	}
+	if target != nil {
+		response["technology"] = target
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// mergeAllTechnologies normalizes every existing value
+func mergeAllTechnologies(tx *gorm.DB, c *gin.Context, workspaceID uuid.UUID, values map[string]int64, renames *[]technologyRename) error {
+	// Most used spelling per key, for values that are not registered
+	preferred := make(map[string]string)
+	for value, uses := range values {
+		key := repository.TechnologyKey(value)
+		current, ok := preferred[key]
+		if !ok || uses > values[current] || (uses == values[current] && value < current) {
+			preferred[key] = value
+		}
+	}
+
+	for value := range values {
+		var canonical string
+		registered, err := repository.FindTechnology(tx, workspaceID, value)
+		switch {
+		case err == nil:
+			canonical = registered.Name
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			canonical = strings.Join(strings.Fields(preferred[repository.TechnologyKey(value)]), " ")
+		default:
+			return err
+		}
+		if canonical == value {
+			continue
+		}
+
+		rename, err := renameTechnologyValue(tx, c, workspaceID, value, canonical)
+		if err != nil {
+			return err
+		}
+		*renames = append(*renames, rename)
+	}
+	return nil
+}
+
+// mergeIntoTarget folds the source spellings and technologies into the target,
+// registering the target if needed and remembering the sources as its aliases
+func mergeIntoTarget(tx *gorm.DB, c *gin.Context, workspaceID uuid.UUID, sources []string, targetName string, values map[string]int64, renames *[]technologyRename) (*models.Technology, error) {
+	target, err := repository.FindTechnology(tx, workspaceID, targetName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		name := strings.Join(strings.Fields(targetName), " ")
+		target = &models.Technology{ID: uuid.New(), WorkspaceID: workspaceID, Name: name, Slug: slugify(name), Aliases: pq.StringArray{}}
+		err = tx.Create(target).Error
+		if err == nil {
+			err = recordActivity(tx, c, models.ActionCreate, models.EntityTechnology, target.ID, nil, target)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	before := *target
+
+	aliases := append([]string{}, target.Aliases...)
+	sourceKeys := make(map[string]bool)
+
+	for _, source := range sources {
+		key := repository.TechnologyKey(source)
+		if key == "" {
+			continue
+		}
+		sourceKeys[key] = true
+		aliases = append(aliases, key)
+
+		// A registered source technology is absorbed into the target
+		registered, err := repository.FindTechnology(tx, workspaceID, source)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if registered != nil && registered.ID != target.ID {
+			sourceKeys[repository.TechnologyKey(registered.Name)] = true
+			for _, alias := range registered.Aliases {
+				sourceKeys[alias] = true
+			}
+			aliases = append(aliases, registered.Name)
+			aliases = append(aliases, registered.Aliases...)
+			var children []models.Technology
+			err := tx.Model(&children).Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+				Where("parent_id = ?", registered.ID).
+				Update("parent_id", target.ID).Error
+			if err != nil {
+				return nil, err
+			}
+			for _, child := range children {
+				err := recordActivity(tx, c, models.ActionUpdate, models.EntityTechnology, child.ID,
+					map[string]uuid.UUID{"parent_id": registered.ID}, map[string]uuid.UUID{"parent_id": target.ID})
+				if err != nil {
+					return nil, err
+				}
+			}
+			if err := tx.Delete(registered).Error; err != nil {
+				return nil, err
+			}
+			if err := recordActivity(tx, c, models.ActionDelete, models.EntityTechnology, registered.ID, registered, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Spellings of the target itself ("go" for "Go") are folded in as well
+	sourceKeys[repository.TechnologyKey(target.Name)] = true
+
+	for value := range values {
+		if value == target.Name || !sourceKeys[repository.TechnologyKey(value)] {
+			continue
+		}
+		rename, err := renameTechnologyValue(tx, c, workspaceID, value, target.Name)
+		if err != nil {
+			return nil, err
+		}
+		*renames = append(*renames, rename)
+	}
+
+	target.Aliases = normalizeAliases(target.Name, aliases)
+	if err := tx.Model(target).Update("aliases", target.Aliases).Error; err != nil {
+		return nil, err
+	}
+	if err := recordActivity(tx, c, models.ActionUpdate, models.EntityTechnology, target.ID, before, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"diary-backend/internal/auth"
+	"diary-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// GetPersonalAccessTokens lists the user's personal access tokens
+func GetPersonalAccessTokens(c *gin.Context) {
+	db := dbFor(c)
+	var tokens []models.PersonalAccessToken
+	if err := db.Where("user_id = ?", currentUserID(c)).Order("created_at DESC").Find(&tokens).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tokens"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens":           tokens,
+		"available_scopes": auth.Scopes,
+	})
+}
+
+// CreatePersonalAccessToken creates a token. The token itself is only
+// returned in this response; afterwards only its prefix is shown.
+func CreatePersonalAccessToken(c *gin.Context) {
+	var req models.CreatePersonalAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scopes := pq.StringArray{}
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !auth.ValidScope(scope) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":            "Unknown scope: " + scope,
+				"available_scopes": auth.Scopes,
+			})
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+		return
+	}
+
+	secret, hash, prefix, err := auth.NewPersonalAccessToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token"})
+		return
+	}
+
+	token := models.PersonalAccessToken{
+		ID:        uuid.New(),
+		UserID:    currentUserID(c),
+		Name:      req.Name,
+		TokenHash: hash,
+		Prefix:    prefix,
+		Scopes:    scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	db := dbFor(c)
+	if err := db.Create(&token).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":               "Token created successfully; copy it now, it will not be shown again",
+		"token":                 secret,
+		"personal_access_token": token,
+	})
+}
+
+// RevokePersonalAccessToken deletes a token so it can no longer be used
+func RevokePersonalAccessToken(c *gin.Context) {
+	tokenUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	db := dbFor(c)
+	result := db.Delete(&models.PersonalAccessToken{}, "id = ? AND user_id = ?", tokenUUID, currentUserID(c))
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke token"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Token not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Token revoked successfully"})
+}
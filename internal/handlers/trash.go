@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetTaskTrash lists deleted tasks, most recently deleted first
+func (h *TaskHandler) GetTaskTrash(c *gin.Context) {
+	page, limit, ok := listPagination(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+
+	tasks, total, err := h.tasks.Trash(c.Request.Context(), currentWorkspaceID(c), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": tasks,
+		"pagination": gin.H{
+			"page":       page,
+			"limit":      limit,
+			"total":      total,
+			"totalPages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RestoreTask brings a deleted task back together with the subtasks that were
+// deleted along with it
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	taskUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		return
+	}
+
+	task, restored, err := h.tasks.Restore(c.Request.Context(), currentActor(c), taskUUID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found in trash"})
+		return
+	case errors.Is(err, repository.ErrParentInTrash):
+		c.JSON(http.StatusConflict, gin.H{"error": "Parent task is in the trash, restore it first"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Task restored successfully",
+		"task":     task,
+		"restored": restored,
+	})
+}
+
+// PurgeTask permanently deletes a task that is in the trash
+func (h *TaskHandler) PurgeTask(c *gin.Context) {
+	taskUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID format"})
+		return
+	}
+
+	err = h.tasks.Purge(c.Request.Context(), currentActor(c), taskUUID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task permanently deleted"})
+}
+
+// EmptyTaskTrash permanently deletes every task in the trash
+func (h *TaskHandler) EmptyTaskTrash(c *gin.Context) {
+	purged, err := h.tasks.EmptyTrash(c.Request.Context(), currentActor(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to empty trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trash emptied successfully",
+		"purged":  purged,
+	})
+}
+
+// GetResourceTrash lists deleted resources, most recently deleted first
+func (h *ResourceHandler) GetResourceTrash(c *gin.Context) {
+	page, limit, ok := listPagination(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+
+	resources, total, err := h.resources.Trash(c.Request.Context(), currentWorkspaceID(c), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resources": resources,
+		"pagination": gin.H{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": (total + int64(limit) - 1) / int64(limit),
+		},
+	})
+}
+
+// RestoreResource brings a deleted resource back and counts it towards its goals again
+func (h *ResourceHandler) RestoreResource(c *gin.Context) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	resource, err := h.resources.Restore(c.Request.Context(), currentActor(c), uid)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore resource"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Resource restored successfully",
+		"resource": resource,
+	})
+}
+
+// PurgeResource permanently deletes a resource that is in the trash, along
+// with its sessions
+func (h *ResourceHandler) PurgeResource(c *gin.Context) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+
+	err = h.resources.Purge(c.Request.Context(), currentActor(c), uid)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found in trash"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge resource"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource permanently deleted"})
+}
+
+// EmptyResourceTrash permanently deletes every resource in the trash
+func (h *ResourceHandler) EmptyResourceTrash(c *gin.Context) {
+	purged, err := h.resources.EmptyTrash(c.Request.Context(), currentActor(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to empty trash"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trash emptied successfully",
+		"purged":  purged,
+	})
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"diary-backend/internal/history"
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// versionNumber parses the :n path parameter
+func versionNumber(c *gin.Context) (int, bool) {
+	n, err := strconv.Atoi(c.Param("n"))
+	if err != nil || n < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version number"})
+		return 0, false
+	}
+	return n, true
+}
+
+// findTaskVersion loads version :n of the task in the path
+func (h *TaskHandler) findTaskVersion(c *gin.Context) (*models.Task, *models.TaskVersion, bool) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return nil, nil, false
+	}
+	n, ok := versionNumber(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	version, err := h.tasks.Version(c.Request.Context(), task.ID, n)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+		return nil, nil, false
+	}
+	return task, version, true
+}
+
+// GetTaskVersions lists a task's versions, newest first, each with the
+// fields that the change after it made
+func (h *TaskHandler) GetTaskVersions(c *gin.Context) {
+	task, ok := h.findParentTask(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.tasks.Versions(c.Request.Context(), task.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch versions"})
+		return
+	}
+
+	summaries := make([]models.TaskVersionSummary, len(versions))
+	for i, version := range versions {
+		next := models.TaskSnapshot(*task)
+		if i+1 < len(versions) {
+			next = versions[i+1].Snapshot
+		}
+		changes, err := history.Diff(version.Snapshot, next)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch versions"})
+			return
+		}
+		summaries[len(versions)-1-i] = models.TaskVersionSummary{
+			Version:   version.Version,
+			CreatedBy: version.CreatedBy,
+			CreatedAt: version.CreatedAt,
+			Changes:   changes,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"versions": summaries})
+}
+
+// GetTaskVersion returns one version of a task with its differences from the current task
+func (h *TaskHandler) GetTaskVersion(c *gin.Context) {
+	task, version, ok := h.findTaskVersion(c)
+	if !ok {
+		return
+	}
+
+	changes, err := history.Diff(version.Snapshot, models.TaskSnapshot(*task))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch version"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version":             version,
+		"changesSinceVersion": changes,
+	})
+}
+
+// RevertTaskVersion puts a task's fields back to how they were in a version.
+// The state being replaced is saved as a new version first. Parent, recurrence
+// and series are left alone since they tie the task to other tasks.
+func (h *TaskHandler) RevertTaskVersion(c *gin.Context) {
+	task, version, ok := h.findTaskVersion(c)
+	if !ok {
+		return
+	}
+	snapshot := version.Snapshot
+
+	if snapshot.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *snapshot.ProjectID) {
+		if !h.validateProject(c, *snapshot.ProjectID) {
+			return
+		}
+	}
+
+	task, err := h.tasks.Revert(c.Request.Context(), currentActor(c), task.ID, snapshot)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revert task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"task":       task,
+		"revertedTo": version.Version,
+	})
+}
+
+// findNotesResource loads the resource named by the :id path parameter
+func (h *ResourceHandler) findNotesResource(c *gin.Context) (*models.Resource, bool) {
+	uid, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return nil, false
+	}
+
+	resource, err := h.resources.Get(c.Request.Context(), currentWorkspaceID(c), uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return nil, false
+	}
+	return resource, true
+}
+
+// findResourceNoteVersion loads notes version :n of the resource in the path
+func (h *ResourceHandler) findResourceNoteVersion(c *gin.Context) (*models.Resource, *models.ResourceNoteVersion, bool) {
+	resource, ok := h.findNotesResource(c)
+	if !ok {
+		return nil, nil, false
+	}
+	n, ok := versionNumber(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	version, err := h.resources.NoteVersion(c.Request.Context(), resource.ID, n)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+		return nil, nil, false
+	}
+	return resource, version, true
+}
+
+// GetResourceNoteVersions lists the earlier notes of a resource, newest first
+func (h *ResourceHandler) GetResourceNoteVersions(c *gin.Context) {
+	resource, ok := h.findNotesResource(c)
+	if !ok {
+		return
+	}
+
+	versions, err := h.resources.NoteVersions(c.Request.Context(), resource.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch note versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"versions":      versions,
+		"current_notes": resource.Notes,
+	})
+}
+
+// GetResourceNoteVersion returns one earlier version of a resource's notes
+func (h *ResourceHandler) GetResourceNoteVersion(c *gin.Context) {
+	_, version, ok := h.findResourceNoteVersion(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"version": version})
+}
+
+// RevertResourceNoteVersion puts a resource's notes back to a version,
+// saving the notes being replaced as a new version first
+func (h *ResourceHandler) RevertResourceNoteVersion(c *gin.Context) {
+	resource, version, ok := h.findResourceNoteVersion(c)
+	if !ok {
+		return
+	}
+
+	resource.Notes = version.Notes
+	err := h.resources.Update(c.Request.Context(), currentActor(c), resource)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revert notes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"resource":    resource,
+		"reverted_to": version.Version,
+	})
+}
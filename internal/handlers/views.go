@@ -0,0 +1,477 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"diary-backend/internal/auth"
+	"diary-backend/internal/middleware"
+	"diary-backend/internal/models"
+	"diary-backend/internal/querylang"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// viewScopes are the scopes a token needs to run a view of each list
+var viewScopes = map[string]string{
+	models.ViewTasks:     auth.ScopeTasksRead,
+	models.ViewResources: auth.ScopeResourcesRead,
+}
+
+// viewPageSizes are the page sizes of views that do not set one, those of
+// the task and resource lists
+var viewPageSizes = map[string]int{
+	models.ViewTasks:     50,
+	models.ViewResources: 20,
+}
+
+// ViewHandler serves saved views and the tasks and resources they list
+type ViewHandler struct {
+	views     repository.ViewRepository
+	tasks     repository.TaskRepository
+	resources repository.ResourceRepository
+}
+
+// NewViewHandler returns a ViewHandler storing views in views and listing
+// tasks and resources from the given repositories
+func NewViewHandler(views repository.ViewRepository, tasks repository.TaskRepository, resources repository.ResourceRepository) *ViewHandler {
+	return &ViewHandler{views: views, tasks: tasks, resources: resources}
+}
+
+// findView loads a view the user can see: their own or one shared with the
+// workspace. It answers 400, 404 or 500 when there is none.
+func (h *ViewHandler) findView(c *gin.Context) (*models.SavedView, bool) {
+	viewUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid view ID format"})
+		return nil, false
+	}
+
+	view, err := h.views.Get(c.Request.Context(), currentActor(c), viewUUID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch view"})
+		return nil, false
+	}
+	return view, true
+}
+
+// canChangeView reports whether the user may change or delete a view: its
+// creator and the owners of the workspace may
+func canChangeView(c *gin.Context, view *models.SavedView) bool {
+	role, _ := c.Get(middleware.WorkspaceRoleKey)
+	return view.UserID == currentUserID(c) || role == models.RoleOwner
+}
+
+// viewTaskFilters turns the filters of a task view into the query
+// parameters of the task list
+func viewTaskFilters(view *models.SavedView) models.TaskFilters {
+	filters := models.TaskFilters{
+		Category:   view.Filters.Category,
+		Priority:   view.Filters.Priority,
+		Status:     view.Filters.Status,
+		Completed:  view.Filters.Completed,
+		Blocked:    view.Filters.Blocked,
+		Search:     view.Filters.Search,
+		Q:          view.Filters.Q,
+		DateFilter: view.Filters.DateFilter,
+		Tags:       strings.Join(view.Filters.Tags, ","),
+		SortBy:     view.SortBy,
+		SortOrder:  view.SortOrder,
+	}
+	if view.Filters.ProjectID != nil {
+		filters.ProjectID = view.Filters.ProjectID.String()
+	}
+	return filters
+}
+
+// viewResourceFilters turns the filters of a resource view into the query
+// parameters of the resource list
+func viewResourceFilters(view *models.SavedView) models.ResourceFilters {
+	return models.ResourceFilters{
+		Technology:    view.Filters.Technology,
+		Type:          view.Filters.Type,
+		Status:        view.Filters.Status,
+		Priority:      view.Filters.Priority,
+		Search:        view.Filters.Search,
+		Q:             view.Filters.Q,
+		Tags:          strings.Join(view.Filters.Tags, ","),
+		TagMode:       view.Filters.TagMode,
+		RatingMin:     view.Filters.RatingMin,
+		RatingMax:     view.Filters.RatingMax,
+		ProgressMin:   view.Filters.ProgressMin,
+		ProgressMax:   view.Filters.ProgressMax,
+		EstimatedTime: strings.Join(view.Filters.EstimatedTime, ","),
+		CreatedFrom:   view.Filters.CreatedFrom,
+		CreatedTo:     view.Filters.CreatedTo,
+		CompletedFrom: view.Filters.CompletedFrom,
+		CompletedTo:   view.Filters.CompletedTo,
+		Sort:          view.SortBy,
+	}
+}
+
+// viewTaskFilter builds the task list filter of a task view with the
+// filters the task list endpoint builds. paging sets the limit, offset and
+// cursors.
+func viewTaskFilter(view *models.SavedView, paging repository.ListOptions) (repository.TaskFilter, error) {
+	if err := checkSearch(view.Filters.Search); err != nil {
+		return repository.TaskFilter{}, err
+	}
+	filters := viewTaskFilters(view)
+	filters.Limit, filters.Offset, filters.After, filters.Before = paging.Limit, paging.Offset, paging.After, paging.Before
+	setTaskFilterDefaults(&filters)
+	return taskListFilter(filters), nil
+}
+
+// viewResourceFilter builds the resource list filter of a resource view with
+// the filters the resource list endpoint builds. paging sets the limit,
+// offset and cursors.
+func viewResourceFilter(view *models.SavedView, paging repository.ListOptions) (repository.ResourceFilter, error) {
+	filter, err := resourceListFilter(viewResourceFilters(view))
+	if err != nil {
+		return repository.ResourceFilter{}, err
+	}
+	if view.SortOrder == "desc" {
+		for i := range filter.Sort {
+			filter.Sort[i].Desc = !filter.Sort[i].Desc
+		}
+	}
+	filter.Limit, filter.Offset, filter.After, filter.Before = paging.Limit, paging.Offset, paging.After, paging.Before
+	return filter, nil
+}
+
+// listView lists one page of a view's tasks or resources
+func (h *ViewHandler) listView(ctx context.Context, workspaceID uuid.UUID, view *models.SavedView, paging repository.ListOptions) (interface{}, repository.Page, error) {
+	if view.EntityType == models.ViewTasks {
+		filter, err := viewTaskFilter(view, paging)
+		if err != nil {
+			return nil, repository.Page{}, err
+		}
+		tasks, page, err := h.tasks.List(ctx, workspaceID, filter)
+		return tasks, page, err
+	}
+
+	filter, err := viewResourceFilter(view, paging)
+	if err != nil {
+		return nil, repository.Page{}, err
+	}
+	resources, page, err := h.resources.List(ctx, workspaceID, filter)
+	return resources, page, err
+}
+
+// setViewCounts sets the number of items each view lists right now, counting
+// the task views and the resource views with one query each. Views the token
+// cannot read the items of, or that no longer run, have no count.
+func (h *ViewHandler) setViewCounts(c *gin.Context, views []models.SavedView) error {
+	var taskViews, resourceViews []*models.SavedView
+	var taskFilters []repository.TaskFilter
+	var resourceFilters []repository.ResourceFilter
+	for i := range views {
+		view := &views[i]
+		if !tokenAllows(c, viewScopes[view.EntityType]) {
+			continue
+		}
+		if view.EntityType == models.ViewTasks {
+			if filter, err := viewTaskFilter(view, repository.ListOptions{}); err == nil {
+				taskViews, taskFilters = append(taskViews, view), append(taskFilters, filter)
+			}
+			continue
+		}
+		if filter, err := viewResourceFilter(view, repository.ListOptions{}); err == nil {
+			resourceViews, resourceFilters = append(resourceViews, view), append(resourceFilters, filter)
+		}
+	}
+
+	ctx, workspaceID := c.Request.Context(), currentWorkspaceID(c)
+	if len(taskFilters) > 0 {
+		counts, err := h.tasks.Counts(ctx, workspaceID, taskFilters)
+		if err != nil {
+			return err
+		}
+		for i, view := range taskViews {
+			view.Count = counts[i]
+		}
+	}
+	if len(resourceFilters) > 0 {
+		counts, err := h.resources.Counts(ctx, workspaceID, resourceFilters)
+		if err != nil {
+			return err
+		}
+		for i, view := range resourceViews {
+			view.Count = counts[i]
+		}
+	}
+	return nil
+}
+
+// invalidView reports whether err is a problem with the filters or sort of a
+// view rather than a failure to list it
+func invalidView(err error) bool {
+	return errors.Is(err, repository.ErrInvalidSort) || errors.As(err, new(*querylang.Error)) || errors.As(err, new(invalidFilter))
+}
+
+// misplacedViewFilters returns the filters that are set but do not apply to
+// the list a view shows
+func misplacedViewFilters(entityType string, filters models.ViewFilters) []string {
+	var misplaced []string
+	for _, filter := range []struct {
+		name, entityType string
+		set              bool
+	}{
+		{"category", models.ViewTasks, filters.Category != ""},
+		{"completed", models.ViewTasks, filters.Completed != nil},
+		{"blocked", models.ViewTasks, filters.Blocked != nil},
+		{"date_filter", models.ViewTasks, filters.DateFilter != ""},
+		{"project_id", models.ViewTasks, filters.ProjectID != nil},
+		{"technology", models.ViewResources, filters.Technology != ""},
+		{"type", models.ViewResources, filters.Type != ""},
+		{"tag_mode", models.ViewResources, filters.TagMode != ""},
+		{"rating_min", models.ViewResources, filters.RatingMin != nil},
+		{"rating_max", models.ViewResources, filters.RatingMax != nil},
+		{"progress_min", models.ViewResources, filters.ProgressMin != nil},
+		{"progress_max", models.ViewResources, filters.ProgressMax != nil},
+		{"estimated_time", models.ViewResources, len(filters.EstimatedTime) > 0},
+		{"created_from", models.ViewResources, filters.CreatedFrom != ""},
+		{"created_to", models.ViewResources, filters.CreatedTo != ""},
+		{"completed_from", models.ViewResources, filters.CompletedFrom != ""},
+		{"completed_to", models.ViewResources, filters.CompletedTo != ""},
+	} {
+		if filter.set && filter.entityType != entityType {
+			misplaced = append(misplaced, filter.name)
+		}
+	}
+	return misplaced
+}
+
+// viewFromRequest validates a view request and applies it to view, answering
+// 400 when it is invalid. The view is run once so that filters and sorts the
+// list would reject are not saved.
+func (h *ViewHandler) viewFromRequest(c *gin.Context, view *models.SavedView) bool {
+	var req models.SavedViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	if misplaced := misplacedViewFilters(req.EntityType, req.Filters); len(misplaced) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Filters do not apply to " + req.EntityType + " views: " + strings.Join(misplaced, ", ")})
+		return false
+	}
+
+	if req.SortOrder == "" {
+		req.SortOrder = "asc"
+	}
+	if req.PageSize == 0 {
+		req.PageSize = viewPageSizes[req.EntityType]
+	}
+	view.Name = req.Name
+	view.EntityType = req.EntityType
+	view.Filters = req.Filters
+	view.SortBy = req.SortBy
+	view.SortOrder = req.SortOrder
+	view.PageSize = req.PageSize
+	view.Shared = req.Shared
+
+	_, _, err := h.listView(c.Request.Context(), currentWorkspaceID(c), view, repository.ListOptions{Limit: 1})
+	if errors.Is(err, repository.ErrInvalidSort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort_by: " + req.SortBy})
+		return false
+	}
+	if writeQueryError(c, err) {
+		return false
+	}
+	var filterErr invalidFilter
+	if errors.As(err, &filterErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": filterErr.Error()})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check view"})
+		return false
+	}
+	return true
+}
+
+// GetViews lists the views the user can see with the number of items each
+// lists. entity_type limits them to task or resource views.
+func (h *ViewHandler) GetViews(c *gin.Context) {
+	entityType := c.Query("entity_type")
+	if entityType != "" && entityType != models.ViewTasks && entityType != models.ViewResources {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity_type, expected task or resource"})
+		return
+	}
+
+	views, err := h.views.List(c.Request.Context(), currentActor(c), entityType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch views"})
+		return
+	}
+	if err := h.setViewCounts(c, views); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count view items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"views": views})
+}
+
+// GetViewByID retrieves a single view with the number of items it lists
+func (h *ViewHandler) GetViewByID(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+
+	views := []models.SavedView{*view}
+	if err := h.setViewCounts(c, views); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count view items"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"view": views[0]})
+}
+
+// CreateView saves a view for the current user
+func (h *ViewHandler) CreateView(c *gin.Context) {
+	view := models.SavedView{
+		UserID:      currentUserID(c),
+		WorkspaceID: currentWorkspaceID(c),
+	}
+	if !h.viewFromRequest(c, &view) {
+		return
+	}
+
+	if err := h.views.Create(c.Request.Context(), currentActor(c), &view); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create view"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"view": view})
+}
+
+// UpdateView replaces a view's name, filters, sort and sharing
+func (h *ViewHandler) UpdateView(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+	if !canChangeView(c, view) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator of a view or a workspace owner can change it"})
+		return
+	}
+
+	if !h.viewFromRequest(c, view) {
+		return
+	}
+
+	err := h.views.Update(c.Request.Context(), currentActor(c), view)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update view"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"view": view})
+}
+
+// DeleteView deletes a view
+func (h *ViewHandler) DeleteView(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+	if !canChangeView(c, view) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator of a view or a workspace owner can delete it"})
+		return
+	}
+
+	err := h.views.Delete(c.Request.Context(), currentActor(c), view.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "View not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete view"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "View deleted successfully"})
+}
+
+// GetViewItems runs a view and returns one page of its tasks or resources.
+// limit overrides the view's page size; offset, after and before page the
+// list like they do on the list endpoints.
+func (h *ViewHandler) GetViewItems(c *gin.Context) {
+	view, ok := h.findView(c)
+	if !ok {
+		return
+	}
+	if scope := viewScopes[view.EntityType]; !tokenAllows(c, scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Token is missing the " + scope + " scope"})
+		return
+	}
+
+	paging := repository.ListOptions{After: c.Query("after"), Before: c.Query("before")}
+	if paging.Limit, ok = queryLimit(c, view.PageSize); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidLimit})
+		return
+	}
+	if value := c.Query("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		paging.Offset = offset
+	}
+	switch {
+	case paging.After != "" && paging.Before != "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCursorConflict})
+		return
+	case (paging.After != "" || paging.Before != "") && paging.Offset != 0:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errCursorOffset})
+		return
+	case paging.Offset < 0 || paging.Offset > maxOffset:
+		c.JSON(http.StatusBadRequest, gin.H{"error": errOffsetTooDeep})
+		return
+	}
+
+	items, page, err := h.listView(c.Request.Context(), currentWorkspaceID(c), view, paging)
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errInvalidCursor})
+		return
+	}
+	if invalidView(err) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "View no longer runs: " + err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch view items"})
+		return
+	}
+	view.Count = &page.Total
+
+	setLinkHeader(c, page)
+
+	c.JSON(http.StatusOK, gin.H{
+		"view":  view,
+		"items": items,
+		"pagination": gin.H{
+			"total":       page.Total,
+			"limit":       paging.Limit,
+			"offset":      paging.Offset,
+			"next_cursor": page.NextCursor,
+			"prev_cursor": page.PrevCursor,
+		},
+	})
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// createView creates a view through the API and returns it
+func (s *testServer) createView(t *testing.T, body gin.H) models.SavedView {
+	t.Helper()
+	var out struct {
+		View models.SavedView `json:"view"`
+	}
+	expect(t, s.do(t, http.MethodPost, "/views", body, &out), http.StatusCreated)
+	return out.View
+}
+
+// viewCounts lists the views of GET /views by name with their counts, -1
+// for views without one
+func (s *testServer) viewCounts(t *testing.T, query string) map[string]int64 {
+	t.Helper()
+	var out struct {
+		Views []models.SavedView `json:"views"`
+	}
+	expect(t, s.do(t, http.MethodGet, "/views"+query, nil, &out), http.StatusOK)
+	counts := map[string]int64{}
+	for _, view := range out.Views {
+		counts[view.Name] = -1
+		if view.Count != nil {
+			counts[view.Name] = *view.Count
+		}
+	}
+	return counts
+}
+
+func TestViews(t *testing.T) {
+	s := newTestServer(t)
+	s.createTask(t, gin.H{"title": "Fix login", "dueDate": "2026-03-03T00:00:00Z", "priority": "high"})
+	s.createTask(t, gin.H{"title": "Ship release", "dueDate": "2026-03-01T00:00:00Z", "priority": "high"})
+	s.createTask(t, gin.H{"title": "Water plants", "dueDate": "2026-03-02T00:00:00Z", "priority": "low"})
+	s.createResource(t, gin.H{"title": "Go spec", "technology": "Go", "type": "documentation"})
+
+	view := s.createView(t, gin.H{"name": "Important", "entity_type": "task", "filters": gin.H{"priority": "high"}, "sort_by": "dueDate"})
+	if view.ID == uuid.Nil || view.UserID != s.userID || view.PageSize != 50 || view.SortOrder != "asc" {
+		t.Errorf("view = %+v, want it stored for the user with the task list defaults", view)
+	}
+	s.createView(t, gin.H{"name": "Go", "entity_type": "resource", "filters": gin.H{"technology": "Go"}})
+
+	if got := fmt.Sprint(s.viewCounts(t, "")); got != "map[Go:1 Important:2]" {
+		t.Errorf("views = %s, want Go with 1 item and Important with 2", got)
+	}
+	if got := fmt.Sprint(s.viewCounts(t, "?entity_type=task")); got != "map[Important:2]" {
+		t.Errorf("task views = %s, want Important only", got)
+	}
+	expect(t, s.do(t, http.MethodGet, "/views?entity_type=goal", nil, nil), http.StatusBadRequest)
+
+	path := "/views/" + view.ID.String()
+	var items struct {
+		Items []models.Task `json:"items"`
+	}
+	expect(t, s.do(t, http.MethodGet, path+"/items", nil, &items), http.StatusOK)
+	if got := fmt.Sprint(taskTitles(items.Items)); got != "[Ship release Fix login]" {
+		t.Errorf("items = %s, want the high priority tasks by due date", got)
+	}
+
+	var out struct {
+		View models.SavedView `json:"view"`
+	}
+	expect(t, s.do(t, http.MethodPut, path, gin.H{"name": "Low", "entity_type": "task", "filters": gin.H{"priority": "low"}}, &out), http.StatusOK)
+	if out.View.Name != "Low" || out.View.Filters.Priority != "low" {
+		t.Errorf("view = %+v, want it renamed to Low", out.View)
+	}
+	expect(t, s.do(t, http.MethodGet, path, nil, &out), http.StatusOK)
+	if out.View.Count == nil || *out.View.Count != 1 {
+		t.Errorf("count = %v, want 1", out.View.Count)
+	}
+
+	expect(t, s.do(t, http.MethodDelete, path, nil, nil), http.StatusOK)
+	expect(t, s.do(t, http.MethodGet, path, nil, nil), http.StatusNotFound)
+	expect(t, s.do(t, http.MethodGet, "/views/not-a-uuid", nil, nil), http.StatusBadRequest)
+}
+
+func TestViewsRejectInvalidRequests(t *testing.T) {
+	s := newTestServer(t)
+	for _, body := range []gin.H{
+		{"entity_type": "task"},
+		{"name": "x", "entity_type": "goal"},
+		{"name": "x", "entity_type": "task", "filters": gin.H{"technology": "Go"}},
+		{"name": "x", "entity_type": "task", "sort_by": "colour"},
+		{"name": "x", "entity_type": "task", "filters": gin.H{"q": "colour:red"}},
+		{"name": "x", "entity_type": "task", "filters": gin.H{"search": "!!!"}},
+		{"name": "x", "entity_type": "resource", "filters": gin.H{"rating_min": 6}},
+	} {
+		if w := s.do(t, http.MethodPost, "/views", body, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("POST /views %v = %d, want 400: %s", body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestViewVisibility(t *testing.T) {
+	s := newTestServer(t)
+	creator := s.userID
+	private := s.createView(t, gin.H{"name": "Mine", "entity_type": "task"})
+	shared := s.createView(t, gin.H{"name": "Team", "entity_type": "task", "shared": true})
+
+	// Another member sees the shared view only and cannot change it
+	s.userID = uuid.New()
+	if got := fmt.Sprint(s.viewCounts(t, "")); got != "map[Team:0]" {
+		t.Errorf("views = %s, want the shared view only", got)
+	}
+	expect(t, s.do(t, http.MethodGet, "/views/"+private.ID.String(), nil, nil), http.StatusNotFound)
+	body := gin.H{"name": "Renamed", "entity_type": "task", "shared": true}
+	expect(t, s.do(t, http.MethodPut, "/views/"+shared.ID.String(), body, nil), http.StatusForbidden)
+	expect(t, s.do(t, http.MethodDelete, "/views/"+shared.ID.String(), nil, nil), http.StatusForbidden)
+
+	// Owners of the workspace can
+	s.role = models.RoleOwner
+	expect(t, s.do(t, http.MethodPut, "/views/"+shared.ID.String(), body, nil), http.StatusOK)
+	view, err := s.repos.Views.Get(t.Context(), repository.Actor{WorkspaceID: s.workspaceID, UserID: creator}, shared.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if view.Name != "Renamed" || view.UserID != creator {
+		t.Errorf("view = %+v, want it renamed and still the creator's", view)
+	}
+	expect(t, s.do(t, http.MethodDelete, "/views/"+shared.ID.String(), nil, nil), http.StatusOK)
+}
+
+func TestViewCountsSkipViewsThatNoLongerRun(t *testing.T) {
+	s := newTestServer(t)
+	s.createTask(t, gin.H{"title": "Fix login", "dueDate": "2026-03-03T00:00:00Z"})
+	s.createView(t, gin.H{"name": "All", "entity_type": "task"})
+
+	// Saved before the q language lost a field
+	actor := repository.Actor{WorkspaceID: s.workspaceID, UserID: s.userID}
+	broken := models.SavedView{UserID: s.userID, WorkspaceID: s.workspaceID, Name: "Broken", EntityType: models.ViewTasks,
+		Filters: models.ViewFilters{Q: "colour:red"}, SortOrder: "asc", PageSize: 50}
+	if err := s.repos.Views.Create(t.Context(), actor, &broken); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := fmt.Sprint(s.viewCounts(t, "")); got != "map[All:1 Broken:-1]" {
+		t.Errorf("views = %s, want All counted and Broken without a count", got)
+	}
+	expect(t, s.do(t, http.MethodGet, "/views/"+broken.ID.String()+"/items", nil, nil), http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,585 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"diary-backend/internal/middleware"
+	"diary-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errLastOwner = errors.New("a workspace must keep at least one owner")
+
+// currentWorkspaceID returns the workspace selected by middleware.RequireWorkspace
+func currentWorkspaceID(c *gin.Context) uuid.UUID {
+	return c.MustGet(middleware.WorkspaceIDKey).(uuid.UUID)
+}
+
+// findMemberWorkspace loads the workspace in the :id path segment together
+// with the current user's role in it. Workspaces the user is not a member of
+// are reported as not found.
+func findMemberWorkspace(c *gin.Context) (*models.Workspace, string, bool) {
+	workspaceUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid workspace ID"})
+		return nil, "", false
+	}
+
+	db := dbFor(c)
+	var workspace models.WorkspaceSummary
+	err = db.Model(&models.Workspace{}).
+		Select("workspaces.*, m.role").
+		Joins("JOIN workspace_members m ON m.workspace_id = workspaces.id AND m.user_id = ?", currentUserID(c)).
+		Where("workspaces.id = ?", workspaceUUID).
+		Take(&workspace).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+		return nil, "", false
+	}
+
+	return &workspace.Workspace, workspace.Role, true
+}
+
+// findOwnedWorkspace is findMemberWorkspace for actions only owners may take
+func findOwnedWorkspace(c *gin.Context) (*models.Workspace, bool) {
+	workspace, role, ok := findMemberWorkspace(c)
+	if !ok {
+		return nil, false
+	}
+	if role != models.RoleOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only workspace owners can do this"})
+		return nil, false
+	}
+	return workspace, true
+}
+
+// countOwners returns how many owners a workspace has, locking their rows so
+// concurrent role changes cannot remove the last one
+func countOwners(tx *gorm.DB, workspaceID uuid.UUID) (int, error) {
+	var owners []models.WorkspaceMember
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("workspace_id = ? AND role = ?", workspaceID, models.RoleOwner).
+		Find(&owners).Error
+	return len(owners), err
+}
+
+// GetWorkspaces lists the workspaces the user is a member of with their role in each
+func GetWorkspaces(c *gin.Context) {
+	db := dbFor(c)
+	var workspaces []models.WorkspaceSummary
+	err := db.Model(&models.Workspace{}).
+		Select(`workspaces.*, m.role,
+			(SELECT COUNT(*) FROM workspace_members wm WHERE wm.workspace_id = workspaces.id) AS member_count`).
+		Joins("JOIN workspace_members m ON m.workspace_id = workspaces.id AND m.user_id = ?", currentUserID(c)).
+		Order("workspaces.personal DESC, workspaces.name ASC").
+		Scan(&workspaces).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspaces"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"workspaces": workspaces})
+}
+
+// GetWorkspace returns a workspace and its members
+func GetWorkspace(c *gin.Context) {
+	workspace, role, ok := findMemberWorkspace(c)
+	if !ok {
+		return
+	}
+
+	members, err := workspaceMembers(dbFor(c), workspace.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspace members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspace": workspace,
+		"role":      role,
+		"members":   members,
+	})
+}
+
+// CreateWorkspace creates a shared workspace owned by the user
+func CreateWorkspace(c *gin.Context) {
+	var req models.WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	workspace := models.Workspace{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(req.Name),
+		CreatedBy: currentUserID(c),
+	}
+
+	db := dbFor(c)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&workspace).Error; err != nil {
+			return err
+		}
+		owner := models.WorkspaceMember{WorkspaceID: workspace.ID, UserID: workspace.CreatedBy, Role: models.RoleOwner}
+		if err := tx.Create(&owner).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, workspace.ID, models.ActionCreate, models.EntityWorkspace, workspace.ID, nil, workspace)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create workspace"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Workspace created successfully",
+		"workspace": workspace,
+	})
+}
+
+// UpdateWorkspace renames a workspace
+func UpdateWorkspace(c *gin.Context) {
+	workspace, ok := findOwnedWorkspace(c)
+	if !ok {
+		return
+	}
+
+	var req models.WorkspaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	before := *workspace
+	workspace.Name = strings.TrimSpace(req.Name)
+	db := dbFor(c)
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(workspace).Update("name", workspace.Name).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, workspace.ID, models.ActionUpdate, models.EntityWorkspace, workspace.ID, before, workspace)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update workspace"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Workspace updated successfully",
+		"workspace": workspace,
+	})
+}
+
+// DeleteWorkspace deletes a shared workspace and everything in it
+func DeleteWorkspace(c *gin.Context) {
+	workspace, ok := findOwnedWorkspace(c)
+	if !ok {
+		return
+	}
+	if workspace.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Personal workspaces cannot be deleted"})
+		return
+	}
+
+	db := dbFor(c)
+	if err := db.Delete(workspace).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete workspace"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Workspace deleted successfully"})
+}
+
+// workspaceMembers lists the members of a workspace with their email and name
+func workspaceMembers(db *gorm.DB, workspaceID uuid.UUID) ([]models.WorkspaceMemberDetail, error) {
+	var members []models.WorkspaceMemberDetail
+	err := db.Model(&models.WorkspaceMember{}).
+		Select("workspace_members.*, users.email, users.name").
+		Joins("JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ?", workspaceID).
+		Order("workspace_members.created_at ASC").
+		Scan(&members).Error
+	return members, err
+}
+
+// GetWorkspaceMembers lists the members of a workspace
+func GetWorkspaceMembers(c *gin.Context) {
+	workspace, _, ok := findMemberWorkspace(c)
+	if !ok {
+		return
+	}
+
+	members, err := workspaceMembers(dbFor(c), workspace.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch workspace members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// UpdateWorkspaceMember changes a member's role
+func UpdateWorkspaceMember(c *gin.Context) {
+	workspace, ok := findOwnedWorkspace(c)
+	if !ok {
+		return
+	}
+	memberUUID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req models.UpdateMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if workspace.Personal && memberUUID == workspace.CreatedBy && req.Role != models.RoleOwner {
+		c.JSON(http.StatusConflict, gin.H{"error": "The owner of a personal workspace cannot be demoted"})
+		return
+	}
+
+	db := dbFor(c)
+	var member models.WorkspaceMember
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&member, "workspace_id = ? AND user_id = ?", workspace.ID, memberUUID).Error; err != nil {
+			return err
+		}
+		if member.Role == models.RoleOwner && req.Role != models.RoleOwner {
+			owners, err := countOwners(tx, workspace.ID)
+			if err != nil {
+				return err
+			}
+			if owners <= 1 {
+				return errLastOwner
+			}
+		}
+		before := member
+		if err := tx.Model(&member).Update("role", req.Role).Error; err != nil {
+			return err
+		}
+		member.Role = req.Role
+		return recordWorkspaceActivity(tx, c, workspace.ID, models.ActionUpdate, models.EntityWorkspaceMember, memberUUID, before, member)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	if errors.Is(err, errLastOwner) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Member updated successfully",
+		"member":  member,
+	})
+}
+
+// RemoveWorkspaceMember removes a member from a workspace. Owners can remove
+// anyone; every member can remove themselves to leave the workspace.
+func RemoveWorkspaceMember(c *gin.Context) {
+	workspace, role, ok := findMemberWorkspace(c)
+	if !ok {
+		return
+	}
+	memberUUID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if role != models.RoleOwner && memberUUID != currentUserID(c) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only workspace owners can remove other members"})
+		return
+	}
+	if workspace.Personal && memberUUID == workspace.CreatedBy {
+		c.JSON(http.StatusConflict, gin.H{"error": "The owner of a personal workspace cannot be removed"})
+		return
+	}
+
+	db := dbFor(c)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var member models.WorkspaceMember
+		if err := tx.First(&member, "workspace_id = ? AND user_id = ?", workspace.ID, memberUUID).Error; err != nil {
+			return err
+		}
+		if member.Role == models.RoleOwner {
+			owners, err := countOwners(tx, workspace.ID)
+			if err != nil {
+				return err
+			}
+			if owners <= 1 {
+				return errLastOwner
+			}
+		}
+		if err := tx.Delete(&models.WorkspaceMember{}, "workspace_id = ? AND user_id = ?", workspace.ID, memberUUID).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, workspace.ID, models.ActionDelete, models.EntityWorkspaceMember, memberUUID, member, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Member not found"})
+		return
+	}
+	if errors.Is(err, errLastOwner) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
+}
+
+// GetWorkspaceInvitations lists the pending invitations of a workspace
+func GetWorkspaceInvitations(c *gin.Context) {
+	workspace, ok := findOwnedWorkspace(c)
+	if !ok {
+		return
+	}
+
+	db := dbFor(c)
+	var invitations []models.WorkspaceInvitation
+	if err := db.Where("workspace_id = ?", workspace.ID).Order("created_at DESC").Find(&invitations).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// InviteWorkspaceMember invites an email address to join a workspace with a role
+func InviteWorkspaceMember(c *gin.Context) {
+	workspace, ok := findOwnedWorkspace(c)
+	if !ok {
+		return
+	}
+
+	var req models.InviteMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation := models.WorkspaceInvitation{
+		ID:          uuid.New(),
+		WorkspaceID: workspace.ID,
+		Email:       strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:        req.Role,
+		InvitedBy:   currentUserID(c),
+	}
+
+	db := dbFor(c)
+	var members, pending int64
+	err := db.Model(&models.WorkspaceMember{}).
+		Joins("JOIN users ON users.id = workspace_members.user_id").
+		Where("workspace_members.workspace_id = ? AND LOWER(users.email) = ?", workspace.ID, invitation.Email).
+		Count(&members).Error
+	if err == nil {
+		err = db.Model(&models.WorkspaceInvitation{}).
+			Where("workspace_id = ? AND LOWER(email) = ?", workspace.ID, invitation.Email).
+			Count(&pending).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+	if members > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This user is already a member of the workspace"})
+		return
+	}
+	if pending > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This email has already been invited"})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&invitation).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, workspace.ID, models.ActionCreate, models.EntityWorkspaceInvitation, invitation.ID, nil, invitation)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create invitation"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":    "Invitation created successfully",
+		"invitation": invitation,
+	})
+}
+
+// DeleteWorkspaceInvitation withdraws a pending invitation
+func DeleteWorkspaceInvitation(c *gin.Context) {
+	workspace, ok := findOwnedWorkspace(c)
+	if !ok {
+		return
+	}
+	invitationUUID, err := uuid.Parse(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+
+	db := dbFor(c)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var invitation models.WorkspaceInvitation
+		if err := tx.First(&invitation, "id = ? AND workspace_id = ?", invitationUUID, workspace.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&invitation).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, workspace.ID, models.ActionDelete, models.EntityWorkspaceInvitation, invitation.ID, invitation, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation deleted successfully"})
+}
+
+// currentUserEmail returns the lower-cased email invitations to the current user are addressed to
+func currentUserEmail(c *gin.Context) (string, error) {
+	db := dbFor(c)
+	var user models.User
+	if err := db.Select("email").First(&user, "id = ?", currentUserID(c)).Error; err != nil {
+		return "", err
+	}
+	return strings.ToLower(user.Email), nil
+}
+
+// GetMyInvitations lists the pending invitations addressed to the user
+func GetMyInvitations(c *gin.Context) {
+	email, err := currentUserEmail(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	db := dbFor(c)
+	var invitations []models.WorkspaceInvitationDetail
+	err = db.Model(&models.WorkspaceInvitation{}).
+		Select("workspace_invitations.*, workspaces.name AS workspace_name").
+		Joins("JOIN workspaces ON workspaces.id = workspace_invitations.workspace_id").
+		Where("LOWER(workspace_invitations.email) = ?", email).
+		Order("workspace_invitations.created_at DESC").
+		Scan(&invitations).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"invitations": invitations})
+}
+
+// AcceptInvitation makes the user a member of the workspace they were invited to
+func AcceptInvitation(c *gin.Context) {
+	invitationUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	email, err := currentUserEmail(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invitation"})
+		return
+	}
+
+	db := dbFor(c)
+	var member models.WorkspaceMember
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var invitation models.WorkspaceInvitation
+		if err := tx.First(&invitation, "id = ? AND LOWER(email) = ?", invitationUUID, email).Error; err != nil {
+			return err
+		}
+		member = models.WorkspaceMember{
+			WorkspaceID: invitation.WorkspaceID,
+			UserID:      currentUserID(c),
+			Role:        invitation.Role,
+		}
+		// Someone who joined some other way keeps the role they already have
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&member)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			err := recordWorkspaceActivity(tx, c, invitation.WorkspaceID, models.ActionCreate, models.EntityWorkspaceMember, member.UserID, nil, member)
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Delete(&invitation).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, invitation.WorkspaceID, models.ActionDelete, models.EntityWorkspaceInvitation, invitation.ID, invitation, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Invitation accepted successfully",
+		"member":  member,
+	})
+}
+
+// DeclineInvitation deletes an invitation addressed to the user
+func DeclineInvitation(c *gin.Context) {
+	invitationUUID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	email, err := currentUserEmail(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decline invitation"})
+		return
+	}
+
+	db := dbFor(c)
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var invitation models.WorkspaceInvitation
+		if err := tx.First(&invitation, "id = ? AND LOWER(email) = ?", invitationUUID, email).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&invitation).Error; err != nil {
+			return err
+		}
+		return recordWorkspaceActivity(tx, c, invitation.WorkspaceID, models.ActionDelete, models.EntityWorkspaceInvitation, invitation.ID, invitation, nil)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decline invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined successfully"})
+}
@@ -0,0 +1,130 @@
+// Package history records the audit trail of workspace changes and the
+// earlier versions of tasks and resource notes. Every function takes the
+// transaction the change itself is made in.
+package history
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// unauditedFields are left out of diffs because every write changes them
+var unauditedFields = map[string]bool{
+	"created_at": true, "createdAt": true,
+	"updated_at": true, "updatedAt": true,
+	"deleted_at": true, "deletedAt": true,
+}
+
+// fieldMap turns an entity into its API fields so diffs use the names clients see
+func fieldMap(entity interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if entity == nil || reflect.ValueOf(entity).IsZero() {
+		return fields, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Diff returns the fields that differ between two versions of an entity.
+// before is nil for creates and after is nil for deletes.
+func Diff(before, after interface{}) (models.FieldChanges, error) {
+	from, err := fieldMap(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := fieldMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := models.FieldChanges{}
+	for name, value := range from {
+		if !unauditedFields[name] && !reflect.DeepEqual(value, to[name]) {
+			changes[name] = models.FieldChange{From: value, To: to[name]}
+		}
+	}
+	for name, value := range to {
+		if _, seen := from[name]; !seen && !unauditedFields[name] && value != nil {
+			changes[name] = models.FieldChange{To: value}
+		}
+	}
+	return changes, nil
+}
+
+// Record appends an event for a change to an entity of a workspace. Pass the
+// entity as it was before and after the change; updates that changed nothing
+// are not recorded.
+func Record(tx *gorm.DB, workspaceID, actorID uuid.UUID, action, entityType string, entityID uuid.UUID, before, after interface{}) error {
+	changes, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	if action == models.ActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	event := models.ActivityEvent{
+		ID:          uuid.New(),
+		WorkspaceID: workspaceID,
+		ActorID:     &actorID,
+		EntityType:  entityType,
+		EntityID:    entityID,
+		Action:      action,
+		Changes:     changes,
+	}
+	return tx.Create(&event).Error
+}
+
+// SaveTaskVersion stores before as the task's next version when the update
+// to after changed anything. Call it after updating the task row in the same
+// transaction, so concurrent updates wait on the row lock and numbers stay unique.
+func SaveTaskVersion(tx *gorm.DB, actorID uuid.UUID, before, after models.Task) error {
+	changes, err := Diff(before, after)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+
+	var next int
+	if err := tx.Model(&models.TaskVersion{}).Where("task_id = ?", before.ID).
+		Select("COALESCE(MAX(version), 0) + 1").Scan(&next).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.TaskVersion{
+		TaskID:    before.ID,
+		Version:   next,
+		Snapshot:  models.TaskSnapshot(before),
+		CreatedBy: &actorID,
+	}).Error
+}
+
+// SaveResourceNoteVersion stores the notes of before as the resource's next
+// notes version when after changed them. Like SaveTaskVersion it runs after
+// the resource row was updated in the same transaction.
+func SaveResourceNoteVersion(tx *gorm.DB, actorID uuid.UUID, before, after models.Resource) error {
+	if before.Notes == after.Notes {
+		return nil
+	}
+
+	var next int
+	if err := tx.Model(&models.ResourceNoteVersion{}).Where("resource_id = ?", before.ID).
+		Select("COALESCE(MAX(version), 0) + 1").Scan(&next).Error; err != nil {
+		return err
+	}
+	return tx.Create(&models.ResourceNoteVersion{
+		ResourceID: before.ID,
+		Version:    next,
+		Notes:      before.Notes,
+		CreatedBy:  &actorID,
+	}).Error
+}
@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is how long a statement may take before it is logged as slow
+const slowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger sends GORM's logs and SQL statements to a slog logger, so that
+// statements run with a request's context carry its request ID
+type GormLogger struct {
+	logger *slog.Logger
+	level  gormlogger.LogLevel
+}
+
+// NewGormLogger returns a GORM logger writing to logger at level. Failed
+// statements are logged at error, slow ones at warn and the rest at info.
+func NewGormLogger(logger *slog.Logger, level gormlogger.LogLevel) *GormLogger {
+	return &GormLogger{logger: logger, level: level}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace logs a statement once it has run. Missing records are not errors:
+// handlers look records up to answer 404.
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		return []any{
+			slog.String("sql", sql),
+			slog.Int64("rows", rows),
+			slog.Float64("elapsed_ms", float64(elapsed.Microseconds())/1000),
+		}
+	}
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		l.logger.ErrorContext(ctx, "sql failed", append(attrs(), slog.String("error", err.Error()))...)
+	case elapsed > slowQueryThreshold && l.level >= gormlogger.Warn:
+		l.logger.WarnContext(ctx, "slow sql", attrs()...)
+	case l.level >= gormlogger.Info:
+		l.logger.InfoContext(ctx, "sql", attrs()...)
+	}
+}
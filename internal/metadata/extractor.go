@@ -0,0 +1,495 @@
+// Package metadata extracts resource details (title, description, type and
+// an estimated time) from a web page: its <title>, OpenGraph and Twitter meta
+// tags, JSON-LD blocks and, when advertised, its oEmbed endpoint.
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Resource types produced by the extractor, matching learning_resources.type
+const (
+	TypeArticle       = "article"
+	TypeVideo         = "video"
+	TypeCourse        = "course"
+	TypeDocumentation = "documentation"
+)
+
+// wordsPerMinute is the reading speed used for reading-time estimates
+const wordsPerMinute = 200
+
+// MaxTitleLength is the most characters kept of a title, the width of
+// learning_resources.title
+const MaxTitleLength = 500
+
+// Metadata is what could be learned about a page
+type Metadata struct {
+	URL              string `json:"url"`
+	Title            string `json:"title"`
+	Description      string `json:"description"`
+	Type             string `json:"type"`
+	SiteName         string `json:"site_name,omitempty"`
+	Image            string `json:"image,omitempty"`
+	EstimatedMinutes *int   `json:"estimated_time,omitempty"`
+	WordCount        int    `json:"word_count"`
+}
+
+// Extractor fetches pages and extracts their metadata
+type Extractor struct {
+	fetcher Fetcher
+}
+
+// NewExtractor creates an extractor that loads pages with the given fetcher
+func NewExtractor(fetcher Fetcher) *Extractor {
+	return &Extractor{fetcher: fetcher}
+}
+
+// page holds the raw signals collected from a document
+type page struct {
+	title     string
+	meta      map[string]string // lower-cased property/name -> content, first occurrence wins
+	oembedURL string
+	jsonLD    []map[string]interface{}
+	wordCount int
+}
+
+// oembed is the subset of an oEmbed response that is used
+type oembed struct {
+	Type         string `json:"type"`
+	Title        string `json:"title"`
+	ProviderName string `json:"provider_name"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// Extract fetches rawURL and builds its metadata
+func (e *Extractor) Extract(ctx context.Context, rawURL string) (*Metadata, error) {
+	fetched, err := e.fetcher.Fetch(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := parsePage(fetched.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// oEmbed is optional; a failing endpoint does not fail the import
+	var embed *oembed
+	if doc.oembedURL != "" {
+		if endpoint, err := resolveURL(fetched.URL, doc.oembedURL); err == nil {
+			embed = e.fetchOEmbed(ctx, endpoint)
+		}
+	}
+
+	ld := jsonLDSignals(doc.jsonLD)
+
+	meta := &Metadata{
+		URL: fetched.URL,
+		Title: TruncateTitle(firstNonEmpty(
+			doc.meta["og:title"],
+			doc.meta["twitter:title"],
+			ld.title,
+			embedTitle(embed),
+			doc.title,
+		)),
+		Description: firstNonEmpty(
+			doc.meta["og:description"],
+			doc.meta["twitter:description"],
+			doc.meta["description"],
+			ld.description,
+		),
+		SiteName:  firstNonEmpty(doc.meta["og:site_name"], embedProvider(embed)),
+		Image:     firstNonEmpty(doc.meta["og:image"], doc.meta["twitter:image"], embedThumbnail(embed)),
+		WordCount: doc.wordCount,
+	}
+	meta.Type = detectType(fetched.URL, doc, ld, embed)
+	meta.EstimatedMinutes = estimateMinutes(meta.Type, doc, ld)
+
+	return meta, nil
+}
+
+func (e *Extractor) fetchOEmbed(ctx context.Context, endpoint string) *oembed {
+	fetched, err := e.fetcher.Fetch(ctx, endpoint)
+	if err != nil {
+		return nil
+	}
+	var embed oembed
+	if err := json.Unmarshal(fetched.Body, &embed); err != nil {
+		return nil
+	}
+	return &embed
+}
+
+// parsePage walks the HTML tree once, collecting the title, meta tags,
+// oEmbed discovery link, JSON-LD blocks and the word count of the main text
+func parsePage(body []byte) (*page, error) {
+	root, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &page{meta: make(map[string]string)}
+	var content, bodyNode *html.Node
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "title":
+				if doc.title == "" {
+					doc.title = collapseSpace(textOf(n))
+				}
+			case "meta":
+				key := strings.ToLower(firstNonEmpty(attr(n, "property"), attr(n, "name"), attr(n, "itemprop")))
+				value := strings.TrimSpace(attr(n, "content"))
+				if key != "" && value != "" {
+					if _, exists := doc.meta[key]; !exists {
+						doc.meta[key] = value
+					}
+				}
+			case "link":
+				if strings.EqualFold(attr(n, "type"), "application/json+oembed") && doc.oembedURL == "" {
+					doc.oembedURL = attr(n, "href")
+				}
+			case "script":
+				if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") {
+					doc.jsonLD = append(doc.jsonLD, decodeJSONLD(textOf(n))...)
+				}
+				return
+			case "article", "main":
+				if content == nil {
+					content = n
+				}
+			case "body":
+				bodyNode = n
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(root)
+
+	if content == nil {
+		content = bodyNode
+	}
+	if content != nil {
+		doc.wordCount = len(strings.Fields(visibleText(content)))
+	}
+
+	return doc, nil
+}
+
+// decodeJSONLD flattens a JSON-LD block (object, array or @graph) into its objects
+func decodeJSONLD(raw string) []map[string]interface{} {
+	var value interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(raw)), &value); err != nil {
+		return nil
+	}
+
+	var objects []map[string]interface{}
+	var collect func(v interface{})
+	collect = func(v interface{}) {
+		switch typed := v.(type) {
+		case []interface{}:
+			for _, item := range typed {
+				collect(item)
+			}
+		case map[string]interface{}:
+			objects = append(objects, typed)
+			if graph, ok := typed["@graph"]; ok {
+				collect(graph)
+			}
+		}
+	}
+	collect(value)
+	return objects
+}
+
+// ldSignals is what the JSON-LD objects say about the page
+type ldSignals struct {
+	types       []string
+	title       string
+	description string
+	duration    string // ISO 8601 duration or timeRequired
+}
+
+func jsonLDSignals(objects []map[string]interface{}) ldSignals {
+	var signals ldSignals
+	for _, object := range objects {
+		types := stringsOf(object["@type"])
+		signals.types = append(signals.types, types...)
+
+		// Breadcrumbs, organisations and the like do not describe the page itself
+		if !describesContent(types) {
+			continue
+		}
+		if signals.title == "" {
+			signals.title = firstNonEmpty(stringOf(object["headline"]), stringOf(object["name"]))
+		}
+		if signals.description == "" {
+			signals.description = stringOf(object["description"])
+		}
+		if signals.duration == "" {
+			signals.duration = firstNonEmpty(stringOf(object["duration"]), stringOf(object["timeRequired"]))
+		}
+	}
+	return signals
+}
+
+func describesContent(types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "Article", "BlogPosting", "NewsArticle", "TechArticle", "APIReference",
+			"VideoObject", "Course", "LearningResource", "HowTo", "WebPage", "Book":
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	videoHosts         = []string{"youtube.com", "youtu.be", "vimeo.com", "twitch.tv", "dailymotion.com", "wistia.com"}
+	courseHosts        = []string{"coursera.org", "udemy.com", "edx.org", "pluralsight.com", "udacity.com", "frontendmasters.com", "egghead.io", "codecademy.com"}
+	documentationHosts = []string{"pkg.go.dev", "developer.mozilla.org", "readthedocs.io", "docs.rs"}
+	documentationPaths = []string{"/docs/", "/doc/", "/documentation/", "/reference/", "/api/", "/manual/"}
+)
+
+// detectType classifies the page from explicit signals first (oEmbed,
+// OpenGraph, JSON-LD) and falls back to well-known hosts and URL paths
+func detectType(pageURL string, doc *page, ld ldSignals, embed *oembed) string {
+	if embed != nil && embed.Type == "video" {
+		return TypeVideo
+	}
+	if strings.HasPrefix(doc.meta["og:type"], "video") {
+		return TypeVideo
+	}
+
+	for _, t := range ld.types {
+		switch t {
+		case "VideoObject":
+			return TypeVideo
+		case "Course":
+			return TypeCourse
+		case "TechArticle", "APIReference":
+			return TypeDocumentation
+		}
+	}
+
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return TypeArticle
+	}
+	host := strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+	path := strings.ToLower(parsed.Path) + "/"
+
+	switch {
+	case matchesHost(host, videoHosts):
+		return TypeVideo
+	case matchesHost(host, courseHosts):
+		return TypeCourse
+	case matchesHost(host, documentationHosts), strings.HasPrefix(host, "docs."):
+		return TypeDocumentation
+	}
+	for _, prefix := range documentationPaths {
+		if strings.Contains(path, prefix) {
+			return TypeDocumentation
+		}
+	}
+
+	return TypeArticle
+}
+
+func matchesHost(host string, hosts []string) bool {
+	for _, candidate := range hosts {
+		if host == candidate || strings.HasSuffix(host, "."+candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+// estimateMinutes uses a declared duration when there is one, and otherwise
+// the reading time of the main text. Videos and courses without a declared
+// duration get no estimate, since their text says nothing about their length.
+func estimateMinutes(resourceType string, doc *page, ld ldSignals) *int {
+	if minutes, ok := parseISODuration(ld.duration); ok {
+		return &minutes
+	}
+	for _, key := range []string{"video:duration", "og:video:duration"} {
+		if seconds, err := strconv.Atoi(doc.meta[key]); err == nil && seconds > 0 {
+			minutes := int(math.Ceil(float64(seconds) / 60))
+			return &minutes
+		}
+	}
+
+	if resourceType == TypeVideo || resourceType == TypeCourse || doc.wordCount == 0 {
+		return nil
+	}
+	minutes := int(math.Ceil(float64(doc.wordCount) / wordsPerMinute))
+	return &minutes
+}
+
+var isoDuration = regexp.MustCompile(`^P(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// parseISODuration converts an ISO 8601 duration such as PT1H5M30S into whole minutes, rounding up
+func parseISODuration(value string) (int, bool) {
+	match := isoDuration.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(value)))
+	if match == nil {
+		return 0, false
+	}
+
+	var seconds float64
+	for i, unit := range []float64{86400, 3600, 60, 1} {
+		if match[i+1] == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(match[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		seconds += n * unit
+	}
+	if seconds <= 0 {
+		return 0, false
+	}
+	return int(math.Ceil(seconds / 60)), true
+}
+
+func resolveURL(base, ref string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	refURL, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(refURL).String(), nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// textOf concatenates all text below a node
+func textOf(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// visibleText concatenates the text below a node, skipping non-content elements
+func visibleText(n *html.Node) string {
+	var sb strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "script", "style", "noscript", "template", "svg", "nav", "footer":
+				return
+			}
+		}
+		if n.Type == html.TextNode {
+			sb.WriteString(n.Data)
+			sb.WriteByte(' ')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return sb.String()
+}
+
+// TruncateTitle shortens title to MaxTitleLength characters, never splitting one
+func TruncateTitle(title string) string {
+	count := 0
+	for i := range title {
+		if count == MaxTitleLength {
+			return strings.TrimSpace(title[:i])
+		}
+		count++
+	}
+	return title
+}
+
+func collapseSpace(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func stringOf(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return collapseSpace(s)
+	}
+	return ""
+}
+
+func stringsOf(value interface{}) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case []interface{}:
+		var values []string
+		for _, item := range typed {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func embedTitle(embed *oembed) string {
+	if embed == nil {
+		return ""
+	}
+	return embed.Title
+}
+
+func embedProvider(embed *oembed) string {
+	if embed == nil {
+		return ""
+	}
+	return embed.ProviderName
+}
+
+func embedThumbnail(embed *oembed) string {
+	if embed == nil {
+		return ""
+	}
+	return embed.ThumbnailURL
+}
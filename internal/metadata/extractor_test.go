@@ -0,0 +1,224 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// servePage starts a test server answering every request with body
+func servePage(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// words returns n words of body text
+func words(n int) string {
+	return strings.TrimSpace(strings.Repeat("word ", n))
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		body        string
+		title       string
+		description string
+		typ         string
+		minutes     int // 0 for no estimate
+	}{
+		{
+			name: "title fallback",
+			body: `<html><head><title>  Plain
+				page </title></head><body><p>` + words(450) + `</p></body></html>`,
+			title:   "Plain page",
+			typ:     TypeArticle,
+			minutes: 3,
+		},
+		{
+			name: "OpenGraph before Twitter",
+			body: `<html><head><title>Page</title>
+				<meta name="twitter:title" content="Twitter title">
+				<meta property="og:title" content="OpenGraph title">
+				<meta name="description" content="Meta description">
+				<meta name="twitter:description" content="Twitter description">
+				<meta property="og:description" content="OpenGraph description">
+				</head><body>` + words(10) + `</body></html>`,
+			title:       "OpenGraph title",
+			description: "OpenGraph description",
+			typ:         TypeArticle,
+			minutes:     1,
+		},
+		{
+			name: "Twitter before title",
+			body: `<html><head><title>Page</title>
+				<meta name="twitter:title" content="Twitter title">
+				<meta name="description" content="Meta description">
+				</head><body>` + words(10) + `</body></html>`,
+			title:       "Twitter title",
+			description: "Meta description",
+			typ:         TypeArticle,
+			minutes:     1,
+		},
+		{
+			name: "JSON-LD object",
+			body: `<html><head><title>Page</title>
+				<script type="application/ld+json">{"@type": "TechArticle", "headline": "Handling errors", "description": "A guide"}</script>
+				</head><body>` + words(10) + `</body></html>`,
+			title:       "Handling errors",
+			description: "A guide",
+			typ:         TypeDocumentation,
+			minutes:     1,
+		},
+		{
+			name: "JSON-LD array skips objects that do not describe the page",
+			body: `<html><head><title>Page</title>
+				<script type="application/ld+json">[{"@type": "BreadcrumbList", "name": "Home"},
+					{"@type": "Course", "name": "Learning Go", "timeRequired": "PT1H5M"}]</script>
+				</head><body>` + words(10) + `</body></html>`,
+			title:   "Learning Go",
+			typ:     TypeCourse,
+			minutes: 65,
+		},
+		{
+			name: "JSON-LD graph",
+			body: `<html><head><title>Page</title>
+				<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [
+					{"@type": "Organization", "name": "Acme"},
+					{"@type": "VideoObject", "name": "Conference talk", "duration": "PT12M30S"}]}</script>
+				</head><body>` + words(10) + `</body></html>`,
+			title:   "Conference talk",
+			typ:     TypeVideo,
+			minutes: 13,
+		},
+		{
+			name: "OpenGraph video with duration",
+			body: `<html><head><title>Talk</title>
+				<meta property="og:type" content="video.other">
+				<meta property="video:duration" content="125">
+				</head><body>` + words(10) + `</body></html>`,
+			title:   "Talk",
+			typ:     TypeVideo,
+			minutes: 3,
+		},
+		{
+			name: "video without duration has no estimate",
+			body: `<html><head><title>Talk</title><meta property="og:type" content="video.movie"></head>
+				<body>` + words(1000) + `</body></html>`,
+			title: "Talk",
+			typ:   TypeVideo,
+		},
+		{
+			name:    "documentation path",
+			path:    "/docs/getting-started",
+			body:    `<html><head><title>Getting started</title></head><body>` + words(10) + `</body></html>`,
+			title:   "Getting started",
+			typ:     TypeDocumentation,
+			minutes: 1,
+		},
+		{
+			name: "reading time counts the main text only",
+			body: `<html><head><title>Post</title></head><body><nav>` + words(300) + `</nav>
+				<article>` + words(401) + `<script>var x = 1;</script></article>
+				<footer>` + words(300) + `</footer></body></html>`,
+			title:   "Post",
+			typ:     TypeArticle,
+			minutes: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := servePage(t, tt.body)
+			meta, err := NewExtractor(allowOnly(t, DefaultMaxBytes, server)).Extract(context.Background(), server.URL+tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta.Title != tt.title || meta.Description != tt.description || meta.Type != tt.typ {
+				t.Errorf("metadata = %+v, want title %q, description %q and type %s", meta, tt.title, tt.description, tt.typ)
+			}
+			minutes := 0
+			if meta.EstimatedMinutes != nil {
+				minutes = *meta.EstimatedMinutes
+			}
+			if minutes != tt.minutes {
+				t.Errorf("estimated minutes = %d, want %d", minutes, tt.minutes)
+			}
+		})
+	}
+}
+
+func TestExtractDiscoversOEmbed(t *testing.T) {
+	oembed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"type": "video", "title": "Embedded talk", "provider_name": "Tube", "thumbnail_url": "https://tube.example/t.jpg"}`)
+	}))
+	defer oembed.Close()
+	page := servePage(t, fmt.Sprintf(`<html><head><title>Page title</title>
+		<link rel="alternate" type="application/json+oembed" href="%s/oembed?url=x"></head>
+		<body>`+words(1000)+`</body></html>`, oembed.URL))
+
+	meta, err := NewExtractor(allowOnly(t, DefaultMaxBytes, page, oembed)).Extract(context.Background(), page.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Embedded talk" || meta.Type != TypeVideo || meta.SiteName != "Tube" || meta.Image != "https://tube.example/t.jpg" {
+		t.Errorf("metadata = %+v, want the oEmbed title, type, provider and thumbnail", meta)
+	}
+	if meta.EstimatedMinutes != nil {
+		t.Errorf("estimated minutes = %d, want none for a video", *meta.EstimatedMinutes)
+	}
+}
+
+func TestExtractTruncatesLongTitles(t *testing.T) {
+	page := servePage(t, `<html><head><meta property="og:title" content="`+strings.Repeat("é", MaxTitleLength+100)+`"></head></html>`)
+
+	meta, err := NewExtractor(allowOnly(t, DefaultMaxBytes, page)).Extract(context.Background(), page.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := utf8.RuneCountInString(meta.Title); n != MaxTitleLength || !utf8.ValidString(meta.Title) {
+		t.Errorf("title has %d characters (valid UTF-8: %v), want %d", n, utf8.ValidString(meta.Title), MaxTitleLength)
+	}
+}
+
+func TestDetectTypeFromHost(t *testing.T) {
+	for pageURL, want := range map[string]string{
+		"https://www.youtube.com/watch?v=1":         TypeVideo,
+		"https://player.vimeo.com/video/1":          TypeVideo,
+		"https://www.coursera.org/learn/go":         TypeCourse,
+		"https://pkg.go.dev/net/http":               TypeDocumentation,
+		"https://docs.python.org/3/":                TypeDocumentation,
+		"https://example.com/blog/post":             TypeArticle,
+		"https://notyoutube.com/watch":              TypeArticle,
+		"https://example.com/api/v1/reference.html": TypeDocumentation,
+	} {
+		if got := detectType(pageURL, &page{meta: map[string]string{}}, ldSignals{}, nil); got != want {
+			t.Errorf("detectType(%s) = %s, want %s", pageURL, got, want)
+		}
+	}
+}
+
+func TestTruncateTitle(t *testing.T) {
+	for _, tt := range []struct {
+		title string
+		want  int
+	}{
+		{"short", 5},
+		{strings.Repeat("a", MaxTitleLength), MaxTitleLength},
+		{strings.Repeat("日本", MaxTitleLength), MaxTitleLength},
+	} {
+		got := TruncateTitle(tt.title)
+		if utf8.RuneCountInString(got) != tt.want || !utf8.ValidString(got) {
+			t.Errorf("TruncateTitle(%d characters) has %d characters, want %d", utf8.RuneCountInString(tt.title), utf8.RuneCountInString(got), tt.want)
+		}
+	}
+}
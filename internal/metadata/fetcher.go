@@ -0,0 +1,160 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultTimeout bounds the whole request, including redirects and reading the body
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxBytes is how much of a response body is read; metadata lives in the head of a page
+	DefaultMaxBytes = 2 << 20
+	maxRedirects    = 5
+	userAgent       = "diary-backend/1.0 (+metadata import)"
+)
+
+// ErrBlockedAddress is returned for requests to an address that is not on the
+// public internet, such as loopback, private networks or the cloud metadata
+// service, so that imports cannot be used to reach internal services
+var ErrBlockedAddress = errors.New("address is not public")
+
+// blockedPrefixes are special-purpose ranges not covered by the netip.Addr
+// predicates checked in PublicAddress
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved and broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which can embed any IPv4 address
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+}
+
+// PublicAddress reports whether addr is a public unicast address
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Page is a fetched document
+type Page struct {
+	URL         string // final URL after redirects
+	ContentType string
+	Body        []byte
+	Truncated   bool // the body was cut off at the size limit
+}
+
+// Fetcher retrieves a document by URL. It is an interface so the extractor
+// can be pointed at a local server or a stub.
+type Fetcher interface {
+	Fetch(ctx context.Context, rawURL string) (*Page, error)
+}
+
+// HTTPFetcher fetches documents over HTTP(S) with a timeout and a body size limit
+type HTTPFetcher struct {
+	Client   *http.Client
+	MaxBytes int64
+}
+
+// NewHTTPFetcher creates a fetcher with the given timeout and body size limit
+// that only connects to public addresses
+func NewHTTPFetcher(timeout time.Duration, maxBytes int64) *HTTPFetcher {
+	return newHTTPFetcher(timeout, maxBytes, func(addr netip.AddrPort) bool { return PublicAddress(addr.Addr()) })
+}
+
+// newHTTPFetcher creates a fetcher that only connects to the addresses allow
+// accepts. The check runs on every connection after DNS resolution, so it
+// covers redirects and hostnames resolving to internal addresses. Proxies are
+// not used since they would connect on the fetcher's behalf.
+func newHTTPFetcher(timeout time.Duration, maxBytes int64, allow func(netip.AddrPort) bool) *HTTPFetcher {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addr, err := netip.ParseAddrPort(address)
+			if err != nil || !allow(addr) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+			}
+			return nil
+		},
+	}
+	return &HTTPFetcher{
+		Client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxRedirects)
+				}
+				return nil
+			},
+		},
+		MaxBytes: maxBytes,
+	}
+}
+
+// Fetch performs a GET request and reads at most MaxBytes of the body
+func (f *HTTPFetcher) Fetch(ctx context.Context, rawURL string) (*Page, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("unsupported URL %q", rawURL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/json;q=0.9,*/*;q=0.8")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{
+		URL:         resp.Request.URL.String(),
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        body,
+	}
+	if int64(len(body)) > f.MaxBytes {
+		page.Body = body[:f.MaxBytes]
+		page.Truncated = true
+	}
+	return page, nil
+}
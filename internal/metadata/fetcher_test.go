@@ -0,0 +1,211 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// serverAddr returns the address of a test server
+func serverAddr(t *testing.T, server *httptest.Server) netip.AddrPort {
+	t.Helper()
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, err := netip.ParseAddrPort(parsed.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return addr
+}
+
+// allowOnly returns a fetcher that may only connect to the given test servers
+func allowOnly(t *testing.T, maxBytes int64, servers ...*httptest.Server) *HTTPFetcher {
+	allowed := map[netip.AddrPort]bool{}
+	for _, server := range servers {
+		allowed[serverAddr(t, server)] = true
+	}
+	return newHTTPFetcher(5*time.Second, maxBytes, func(addr netip.AddrPort) bool { return allowed[addr] })
+}
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"64:ff9b::a9fe:a9fe", false},
+	}
+	for _, tt := range tests {
+		if got := PublicAddress(netip.MustParseAddr(tt.addr)); got != tt.public {
+			t.Errorf("PublicAddress(%s) = %v, want %v", tt.addr, got, tt.public)
+		}
+	}
+}
+
+func TestFetchBlocksInternalAddresses(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	fetcher := NewHTTPFetcher(2*time.Second, DefaultMaxBytes)
+	for _, target := range []string{
+		server.URL,
+		strings.Replace(server.URL, "127.0.0.1", "localhost", 1),
+		"http://169.254.169.254/latest/meta-data/",
+		"http://10.0.0.1/",
+		"http://[::1]:" + fmt.Sprint(serverAddr(t, server).Port()) + "/",
+		"http://0.0.0.0:" + fmt.Sprint(serverAddr(t, server).Port()) + "/",
+	} {
+		_, err := fetcher.Fetch(context.Background(), target)
+		if !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("Fetch(%s) error = %v, want ErrBlockedAddress", target, err)
+		}
+	}
+	if hits.Load() != 0 {
+		t.Errorf("server was reached %d times", hits.Load())
+	}
+}
+
+func TestFetchBlocksRedirectsToInternalAddresses(t *testing.T) {
+	var internalHits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		internalHits.Add(1)
+	}))
+	defer internal.Close()
+	public := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := internal.URL
+		if r.URL.Path == "/metadata" {
+			target = "http://169.254.169.254/latest/meta-data/"
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+	}))
+	defer public.Close()
+
+	fetcher := allowOnly(t, DefaultMaxBytes, public)
+	for _, path := range []string{"/", "/metadata"} {
+		_, err := fetcher.Fetch(context.Background(), public.URL+path)
+		if !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("Fetch(%s) error = %v, want ErrBlockedAddress", path, err)
+		}
+	}
+	if internalHits.Load() != 0 {
+		t.Errorf("internal server was reached %d times", internalHits.Load())
+	}
+}
+
+func TestFetchFollowsRedirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var hop int
+		fmt.Sscanf(r.URL.Query().Get("hop"), "%d", &hop)
+		if hop < maxRedirects-1 || r.URL.Query().Get("loop") != "" {
+			http.Redirect(w, r, fmt.Sprintf("/?hop=%d&loop=%s", hop+1, r.URL.Query().Get("loop")), http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "<title>Done</title>")
+	}))
+	defer server.Close()
+
+	fetcher := allowOnly(t, DefaultMaxBytes, server)
+	page, err := fetcher.Fetch(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("Fetch within the redirect limit: %v", err)
+	}
+	if !strings.HasSuffix(page.URL, fmt.Sprintf("hop=%d&loop=", maxRedirects-1)) {
+		t.Errorf("page.URL = %s, want the last hop", page.URL)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), server.URL+"/?loop=1"); err == nil || !strings.Contains(err.Error(), "redirects") {
+		t.Errorf("Fetch of a redirect loop error = %v, want the redirect limit", err)
+	}
+}
+
+func TestFetchCapsBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := 1024
+		if r.URL.Path == "/large" {
+			size = 4096
+		}
+		fmt.Fprint(w, strings.Repeat("a", size))
+	}))
+	defer server.Close()
+
+	fetcher := allowOnly(t, 1024, server)
+	tests := []struct {
+		path      string
+		truncated bool
+	}{
+		{"/exact", false},
+		{"/large", true},
+	}
+	for _, tt := range tests {
+		page, err := fetcher.Fetch(context.Background(), server.URL+tt.path)
+		if err != nil {
+			t.Fatalf("Fetch(%s): %v", tt.path, err)
+		}
+		if len(page.Body) != 1024 || page.Truncated != tt.truncated {
+			t.Errorf("Fetch(%s) = %d bytes, truncated %v; want 1024 bytes, truncated %v", tt.path, len(page.Body), page.Truncated, tt.truncated)
+		}
+	}
+}
+
+func TestFetchRejectsOtherSchemes(t *testing.T) {
+	fetcher := NewHTTPFetcher(time.Second, DefaultMaxBytes)
+	for _, target := range []string{"file:///etc/passwd", "gopher://example.com/", "http://"} {
+		if _, err := fetcher.Fetch(context.Background(), target); err == nil {
+			t.Errorf("Fetch(%s) succeeded", target)
+		}
+	}
+}
+
+func TestExtractDoesNotFetchInternalOEmbed(t *testing.T) {
+	var oembedHits atomic.Int32
+	oembed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		oembedHits.Add(1)
+		fmt.Fprint(w, `{"type": "video", "title": "Internal"}`)
+	}))
+	defer oembed.Close()
+	page := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `<html><head><title>Public page</title>
+			<link rel="alternate" type="application/json+oembed" href="%s/oembed"></head></html>`, oembed.URL)
+	}))
+	defer page.Close()
+
+	meta, err := NewExtractor(allowOnly(t, DefaultMaxBytes, page)).Extract(context.Background(), page.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Public page" || meta.Type == TypeVideo {
+		t.Errorf("metadata = %+v, want the page's own title and type", meta)
+	}
+	if oembedHits.Load() != 0 {
+		t.Errorf("oEmbed endpoint was reached %d times", oembedHits.Load())
+	}
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"diary-backend/internal/auth"
+	"diary-backend/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// UserIDKey is the context key the authenticated user's ID is stored under
+	UserIDKey = "userID"
+	// TokenScopesKey holds the scopes of the personal access token a request
+	// was made with. It is not set for requests made with a login session,
+	// which may do anything the user can.
+	TokenScopesKey = "tokenScopes"
+)
+
+// RequireAuth rejects requests without a valid access token or personal
+// access token in the Authorization header and stores the user ID in the context
+func RequireAuth(issuer *auth.TokenIssuer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Missing bearer token"})
+			return
+		}
+
+		if auth.IsPersonalAccessToken(token) {
+			pat, err := database.AuthenticatePersonalAccessToken(c.Request.Context(), auth.HashPersonalAccessToken(token))
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+				return
+			}
+			c.Set(UserIDKey, pat.UserID)
+			c.Set(TokenScopesKey, []string(pat.Scopes))
+			c.Next()
+			return
+		}
+
+		claims, err := issuer.Parse(token, auth.AccessToken)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		userID, _ := claims.UserID()
+		c.Set(UserIDKey, userID)
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests made with a personal access token that was
+// not granted scope. Login sessions are not restricted.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if granted, ok := c.Get(TokenScopesKey); ok && !auth.HasScope(granted.([]string), scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token is missing the " + scope + " scope"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireLogin rejects requests made with a personal access token, for
+// routes such as token management that need a login session
+func RequireLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(TokenScopesKey); ok {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint cannot be used with a personal access token"})
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"regexp"
+
+	"diary-backend/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// RequestIDHeader carries the ID that ties a request to its log lines
+	RequestIDHeader = "X-Request-ID"
+	// RequestIDKey is the context key the request ID is stored under
+	RequestIDKey = "requestID"
+)
+
+// validRequestID limits the request IDs taken from clients to what is safe to log
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// RequestID keeps the X-Request-ID a client or proxy sent, or generates one,
+// and echoes it in the response. The ID is added to the request context so
+// that everything logged with it, SQL included, carries the ID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+	}
+}
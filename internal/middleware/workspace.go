@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+
+	"diary-backend/internal/database"
+	"diary-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// WorkspaceHeader selects the workspace a request works on. Without it
+	// the user's personal workspace is used.
+	WorkspaceHeader = "X-Workspace-ID"
+	// WorkspaceIDKey is the context key the selected workspace's ID is stored under
+	WorkspaceIDKey = "workspaceID"
+	// WorkspaceRoleKey holds the user's role in the selected workspace
+	WorkspaceRoleKey = "workspaceRole"
+)
+
+// RequireWorkspace resolves the workspace selected by the X-Workspace-ID
+// header, checks the user is a member and stores the workspace ID and role in
+// the context. Viewers may only make read requests. It must run after RequireAuth.
+func RequireWorkspace() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.MustGet(UserIDKey).(uuid.UUID)
+		ctx := c.Request.Context()
+
+		var workspaceID uuid.UUID
+		if header := c.GetHeader(WorkspaceHeader); header != "" {
+			id, err := uuid.Parse(header)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid " + WorkspaceHeader + " header"})
+				return
+			}
+			workspaceID = id
+		} else {
+			id, err := database.PersonalWorkspaceID(ctx, userID)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve workspace"})
+				return
+			}
+			workspaceID = id
+		}
+
+		// Workspaces the user is not a member of are reported as missing
+		role, err := database.WorkspaceRole(ctx, workspaceID, userID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Workspace not found"})
+			return
+		}
+
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+		default:
+			if role == models.RoleViewer {
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Viewers cannot modify this workspace"})
+				return
+			}
+		}
+
+		c.Set(WorkspaceIDKey, workspaceID)
+		c.Set(WorkspaceRoleKey, role)
+		c.Next()
+	}
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actions recorded in the activity log
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+)
+
+// Entity types recorded in the activity log
+const (
+	EntityTask                = "task"
+	EntityChecklistItem       = "checklist_item"
+	EntityTaskDependency      = "task_dependency"
+	EntityProject             = "project"
+	EntityResource            = "resource"
+	EntitySession             = "session"
+	EntityTechnology          = "technology"
+	EntityGoal                = "goal"
+	EntityWorkspace           = "workspace"
+	EntityWorkspaceMember     = "workspace_member"
+	EntityWorkspaceInvitation = "workspace_invitation"
+	EntityView                = "view"
+)
+
+// FieldChange is the value of a field before and after a change. From is
+// null for creates and To is null for deletes.
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// FieldChanges maps field names, as they appear in the API, to their change
+type FieldChanges map[string]FieldChange
+
+// Value stores the changes as JSON
+func (f FieldChanges) Value() (driver.Value, error) {
+	if f == nil {
+		return "{}", nil
+	}
+	data, err := json.Marshal(f)
+	return string(data), err
+}
+
+// Scan reads the changes from JSON
+func (f *FieldChanges) Scan(value interface{}) error {
+	return scanJSON(value, f)
+}
+
+// scanJSON decodes a JSON column into dest
+func scanJSON(value interface{}, dest interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", value, dest)
+	}
+}
+
+// ActivityEvent records one change to an entity of a workspace
+type ActivityEvent struct {
+	ID          uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	WorkspaceID uuid.UUID    `json:"workspace_id" gorm:"type:uuid;not null;column:workspace_id"`
+	ActorID     *uuid.UUID   `json:"actor_id" gorm:"type:uuid;column:actor_id"`
+	ActorEmail  *string      `json:"actor_email,omitempty" gorm:"->;column:actor_email"`
+	ActorName   *string      `json:"actor_name,omitempty" gorm:"->;column:actor_name"`
+	EntityType  string       `json:"entity_type" gorm:"not null;column:entity_type"`
+	EntityID    uuid.UUID    `json:"entity_id" gorm:"type:uuid;not null;column:entity_id"`
+	Action      string       `json:"action" gorm:"not null;column:action"`
+	Changes     FieldChanges `json:"changes" gorm:"type:jsonb;column:changes"`
+	CreatedAt   time.Time    `json:"created_at" gorm:"column:created_at"`
+}
+
+func (ActivityEvent) TableName() string {
+	return "activity_events"
+}
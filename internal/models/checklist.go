@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChecklistItem is a lightweight to-do entry inside a task
+type ChecklistItem struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	TaskID    uuid.UUID `json:"taskId" gorm:"type:uuid;not null;column:task_id"`
+	Title     string    `json:"title" gorm:"not null;column:title"`
+	Done      bool      `json:"done" gorm:"default:false;column:done"`
+	Position  int       `json:"position" gorm:"default:0;column:position"`
+	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+	UpdatedAt time.Time `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (ChecklistItem) TableName() string {
+	return "task_checklist_items"
+}
+
+// BeforeCreate hook to set default values
+func (i *ChecklistItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// CreateChecklistItemRequest represents the request body for adding a checklist item
+type CreateChecklistItemRequest struct {
+	Title    string `json:"title" binding:"required,min=1,max=255"`
+	Done     bool   `json:"done"`
+	Position *int   `json:"position"`
+}
+
+// UpdateChecklistItemRequest represents the request body for updating a checklist item
+type UpdateChecklistItemRequest struct {
+	Title    *string `json:"title" binding:"omitempty,min=1,max=255"`
+	Done     *bool   `json:"done"`
+	Position *int    `json:"position"`
+}
+
+// TaskProgress summarises how far a task is, based on its subtasks and checklist
+type TaskProgress struct {
+	SubtasksTotal     int64 `json:"subtasksTotal"`
+	SubtasksCompleted int64 `json:"subtasksCompleted"`
+	ChecklistTotal    int64 `json:"checklistTotal"`
+	ChecklistDone     int64 `json:"checklistDone"`
+	Percentage        int   `json:"percentage"`
+}
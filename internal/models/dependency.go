@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskDependency records that TaskID is blocked by DependsOnID
+type TaskDependency struct {
+	TaskID      uuid.UUID `json:"taskId" gorm:"type:uuid;primaryKey;column:task_id"`
+	DependsOnID uuid.UUID `json:"dependsOnId" gorm:"type:uuid;primaryKey;column:depends_on_id"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+// TableName specifies the table name for GORM
+func (TaskDependency) TableName() string {
+	return "task_dependencies"
+}
+
+// CreateTaskDependencyRequest represents the request body for adding a blocker to a task
+type CreateTaskDependencyRequest struct {
+	DependsOnID uuid.UUID `json:"dependsOnId" binding:"required"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Goal struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	UserID      uuid.UUID  `json:"-" gorm:"type:uuid;not null;column:user_id"`
+	WorkspaceID uuid.UUID  `json:"-" gorm:"type:uuid;not null;column:workspace_id"`
+	Title       string     `json:"title" gorm:"not null;column:title"`
+	Description string     `json:"description" gorm:"column:description"`
+	Technology  string     `json:"technology" gorm:"not null;column:technology"`
+	TargetDate  *time.Time `json:"target_date" gorm:"type:date;column:target_date"`
+	Status      string     `json:"status" gorm:"default:'active';column:status"`
+	Progress    int        `json:"progress" gorm:"default:0;column:progress"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Goal) TableName() string {
+	return "learning_goals"
+}
+
+// GoalResource links a goal to one of the resources that make it up
+type GoalResource struct {
+	GoalID     uuid.UUID `json:"goal_id" gorm:"type:uuid;primaryKey;column:goal_id"`
+	ResourceID uuid.UUID `json:"resource_id" gorm:"type:uuid;primaryKey;column:resource_id"`
+	CreatedAt  time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (GoalResource) TableName() string {
+	return "learning_goal_resources"
+}
+
+// GoalRequest represents the request body for creating or updating a goal.
+// Progress is not accepted: it is computed from the linked resources. A
+// status given here is kept as is rather than completed automatically.
+type GoalRequest struct {
+	Title       string      `json:"title" binding:"required,max=255"`
+	Description string      `json:"description,omitempty"`
+	Technology  string      `json:"technology" binding:"required,max=100"`
+	TargetDate  *time.Time  `json:"target_date,omitempty"`
+	Status      string      `json:"status,omitempty" binding:"omitempty,oneof=active completed paused cancelled"`
+	ResourceIDs []uuid.UUID `json:"resource_ids,omitempty"`
+}
+
+// GoalResourcesRequest represents the request body for linking resources to a goal
+type GoalResourcesRequest struct {
+	ResourceIDs []uuid.UUID `json:"resource_ids" binding:"required,min=1"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Project struct {
+	ID          uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	UserID      uuid.UUID  `json:"-" gorm:"type:uuid;not null;column:user_id"`
+	WorkspaceID uuid.UUID  `json:"-" gorm:"type:uuid;not null;column:workspace_id"`
+	Name        string     `json:"name" gorm:"not null;column:name"`
+	Description *string    `json:"description,omitempty" gorm:"column:description"`
+	Color       string     `json:"color,omitempty" gorm:"column:color"`
+	ArchivedAt  *time.Time `json:"archivedAt" gorm:"column:archived_at"`
+	CreatedAt   time.Time  `json:"createdAt" gorm:"column:created_at"`
+	UpdatedAt   time.Time  `json:"updatedAt" gorm:"column:updated_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Project) TableName() string {
+	return "projects"
+}
+
+// BeforeCreate hook to set default values
+func (p *Project) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// ProjectSummary is a project together with its task counts
+type ProjectSummary struct {
+	Project
+	TaskCount            int64   `json:"taskCount"`
+	CompletedCount       int64   `json:"completedCount"`
+	CompletionPercentage float64 `json:"completionPercentage"`
+}
+
+// ProjectRequest represents the request body for creating or updating a project
+type ProjectRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=255"`
+	Description *string `json:"description"`
+	Color       string  `json:"color" binding:"omitempty,max=20"`
+}
@@ -5,10 +5,13 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
 type Resource struct {
 	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	UserID        uuid.UUID      `json:"-" gorm:"type:uuid;not null;column:user_id"`
+	WorkspaceID   uuid.UUID      `json:"-" gorm:"type:uuid;not null;column:workspace_id"`
 	Title         string         `json:"title" gorm:"not null;column:title"`
 	URL           string         `json:"url" gorm:"column:url"`
 	Description   string         `json:"description" gorm:"column:description"`
@@ -24,8 +27,32 @@ type Resource struct {
 	CompletedAt   *time.Time     `json:"completed_at" gorm:"column:completed_at"`
 	CreatedAt     time.Time      `json:"created_at" gorm:"column:created_at"`
 	UpdatedAt     time.Time      `json:"updated_at" gorm:"column:updated_at"`
+	DeletedAt     gorm.DeletedAt `json:"deleted_at" gorm:"column:deleted_at"`
 }
 
 func (Resource) TableName() string {
 	return "learning_resources"
 }
+
+// ResourceFilters represents query parameters for filtering resources. Ranges
+// are inclusive and dates are YYYY-MM-DD.
+type ResourceFilters struct {
+	Technology    string `form:"technology"`
+	Type          string `form:"type"`
+	Status        string `form:"status"`
+	Priority      string `form:"priority"`
+	Search        string `form:"search"`
+	Q             string `form:"q"`        // filter language, e.g. rating>=4 -status:completed
+	Tags          string `form:"tags"`     // comma-separated
+	TagMode       string `form:"tag_mode"` // all (default) or any of the tags
+	RatingMin     *int   `form:"rating_min"`
+	RatingMax     *int   `form:"rating_max"`
+	ProgressMin   *int   `form:"progress_min"`
+	ProgressMax   *int   `form:"progress_max"`
+	EstimatedTime string `form:"estimated_time"` // comma-separated: short, medium, long, none
+	CreatedFrom   string `form:"created_from"`
+	CreatedTo     string `form:"created_to"`
+	CompletedFrom string `form:"completed_from"`
+	CompletedTo   string `form:"completed_to"`
+	Sort          string `form:"sort"` // fields in order of precedence, e.g. -priority,created_at
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Session struct {
+	ID              uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	ResourceID      uuid.UUID `json:"resource_id" gorm:"type:uuid;not null;column:resource_id"`
+	DurationMinutes int       `json:"duration_minutes" gorm:"not null;column:duration_minutes"`
+	Notes           string    `json:"notes" gorm:"column:notes"`
+	SessionDate     time.Time `json:"session_date" gorm:"type:date;column:session_date"`
+	CreatedAt       time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (Session) TableName() string {
+	return "learning_sessions"
+}
+
+// SessionRequest represents the request body for logging or updating a session
+type SessionRequest struct {
+	DurationMinutes int        `json:"duration_minutes" binding:"required,min=1"`
+	Notes           string     `json:"notes,omitempty"`
+	SessionDate     *time.Time `json:"session_date,omitempty"`
+}
+
+// SessionFilters represents query parameters for listing sessions
+type SessionFilters struct {
+	ResourceID string `form:"resource_id"`
+	Technology string `form:"technology"`
+	From       string `form:"from"` // YYYY-MM-DD, inclusive
+	To         string `form:"to"`   // YYYY-MM-DD, inclusive
+	Page       int    `form:"page"`
+}
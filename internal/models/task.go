@@ -10,6 +10,8 @@ import (
 
 type Task struct {
 	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	UserID      uuid.UUID      `json:"-" gorm:"type:uuid;not null;column:user_id"`
+	WorkspaceID uuid.UUID      `json:"-" gorm:"type:uuid;not null;column:workspace_id"`
 	Title       string         `json:"title" gorm:"not null;column:title" validate:"required,min=1,max=255"`
 	Description *string        `json:"description,omitempty" gorm:"column:description"`
 	Completed   bool           `json:"completed" gorm:"default:false;column:completed"`
@@ -18,9 +20,14 @@ type Task struct {
 	Category    string         `json:"category" gorm:"default:'personal';column:category" validate:"oneof=personal office learning research"`
 	Status      string         `json:"status" gorm:"default:'pending';column:status" validate:"oneof=pending in-progress review completed cancelled"`
 	ProjectID   *uuid.UUID     `json:"projectId,omitempty" gorm:"type:uuid;column:project_id"`
+	ParentID    *uuid.UUID     `json:"parentId,omitempty" gorm:"type:uuid;column:parent_id"`
 	Tags        pq.StringArray `json:"tags" gorm:"type:text[];column:tags"`
+	Recurrence  *string        `json:"recurrence,omitempty" gorm:"column:recurrence_rule"`
+	SeriesID    *uuid.UUID     `json:"seriesId,omitempty" gorm:"type:uuid;column:series_id"`
+	SeriesStart *time.Time     `json:"seriesStart,omitempty" gorm:"type:date;column:series_start"`
 	CreatedAt   time.Time      `json:"createdAt" gorm:"column:created_at"`
 	UpdatedAt   time.Time      `json:"updatedAt" gorm:"column:updated_at"`
+	DeletedAt   gorm.DeletedAt `json:"deletedAt" gorm:"column:deleted_at"`
 }
 
 // TableName specifies the table name for GORM
@@ -46,6 +53,7 @@ type CreateTaskRequest struct {
 	Status      string     `json:"status" validate:"oneof=pending in-progress review completed cancelled"`
 	ProjectID   *uuid.UUID `json:"projectId"`
 	Tags        []string   `json:"tags"`
+	Recurrence  *string    `json:"recurrence"` // RFC 5545 RRULE, e.g. FREQ=WEEKLY;BYDAY=MO,FR
 }
 
 // UpdateTaskRequest represents the request body for updating a task
@@ -59,6 +67,7 @@ type UpdateTaskRequest struct {
 	Status      *string    `json:"status" validate:"omitempty,oneof=pending in-progress review completed cancelled"`
 	ProjectID   *uuid.UUID `json:"projectId"`
 	Tags        *[]string  `json:"tags"`
+	Recurrence  *string    `json:"recurrence"` // empty string removes the rule
 }
 
 // TaskFilters represents query parameters for filtering tasks
@@ -67,12 +76,29 @@ type TaskFilters struct {
 	Priority   string `form:"priority"`
 	Status     string `form:"status"`
 	Completed  *bool  `form:"completed"`
+	Blocked    *bool  `form:"blocked"` // has unfinished blockers
 	Search     string `form:"search"`
+	Q          string `form:"q"`          // filter language, e.g. priority:high,urgent due<friday
 	DateFilter string `form:"dateFilter"` // today, tomorrow, this-week, overdue
 	ProjectID  string `form:"projectId"`
 	Tags       string `form:"tags"`
 	Limit      int    `form:"limit"`
 	Offset     int    `form:"offset"`
+	After      string `form:"after"`     // cursor: the page after this one
+	Before     string `form:"before"`    // cursor: the page before this one
 	SortBy     string `form:"sortBy"`    // dueDate, priority, created, title, category
 	SortOrder  string `form:"sortOrder"` // asc, desc
+	Expand     bool   `form:"expand"`    // include upcoming occurrences of recurring tasks
+	From       string `form:"from"`      // occurrence window start, YYYY-MM-DD
+	To         string `form:"to"`        // occurrence window end, YYYY-MM-DD
+}
+
+// TaskOccurrence is an upcoming, not yet generated occurrence of a recurring task
+type TaskOccurrence struct {
+	TaskID   uuid.UUID  `json:"taskId"`
+	SeriesID *uuid.UUID `json:"seriesId,omitempty"`
+	Title    string     `json:"title"`
+	DueDate  time.Time  `json:"dueDate"`
+	Priority string     `json:"priority"`
+	Category string     `json:"category"`
 }
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type Technology struct {
+	ID          uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	WorkspaceID uuid.UUID      `json:"-" gorm:"type:uuid;not null;column:workspace_id"`
+	Name        string         `json:"name" gorm:"not null;column:name"`
+	Slug        string         `json:"slug" gorm:"not null;column:slug"`
+	Aliases     pq.StringArray `json:"aliases" gorm:"type:text[];column:aliases"`
+	ParentID    *uuid.UUID     `json:"parent_id" gorm:"type:uuid;column:parent_id"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Technology) TableName() string {
+	return "technologies"
+}
+
+// TechnologyRequest represents the request body for creating or updating a technology
+type TechnologyRequest struct {
+	Name     string     `json:"name" binding:"required,max=100"`
+	Aliases  []string   `json:"aliases,omitempty"`
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+}
+
+// MergeTechnologiesRequest represents the request body for merging duplicate
+// technology values. Without sources every existing value is normalized.
+type MergeTechnologiesRequest struct {
+	Sources []string `json:"sources,omitempty"`
+	Target  string   `json:"target,omitempty"`
+}
+
+// TechnologyUsage is a technology with the resources and study time recorded against it
+type TechnologyUsage struct {
+	ID              *uuid.UUID `json:"id"`
+	Name            string     `json:"name"`
+	Parent          string     `json:"parent,omitempty"`
+	Registered      bool       `json:"registered"`
+	ResourceCount   int64      `json:"resource_count"`
+	CompletedCount  int64      `json:"completed_count"`
+	CompletionRatio float64    `json:"completion_ratio"`
+	TotalMinutes    int64      `json:"total_minutes"`
+}
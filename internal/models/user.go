@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// BootstrapUserID owns the rows that existed before user accounts were added
+var BootstrapUserID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type User struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	Email        string    `json:"email" gorm:"not null;column:email"`
+	PasswordHash string    `json:"-" gorm:"not null;column:password_hash"`
+	Name         string    `json:"name" gorm:"column:name"`
+	CreatedAt    time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
+
+// RefreshTokenRecord tracks an issued refresh token so it can be rotated and revoked
+type RefreshTokenRecord struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;column:id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;column:user_id"`
+	ExpiresAt time.Time  `gorm:"not null;column:expires_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at"`
+	CreatedAt time.Time  `gorm:"column:created_at"`
+}
+
+func (RefreshTokenRecord) TableName() string {
+	return "refresh_tokens"
+}
+
+type RegisterRequest struct {
+	Email    string `json:"email" binding:"required,email,max=255"`
+	Password string `json:"password" binding:"required,min=8,max=72"` // bcrypt ignores anything past 72 bytes
+	Name     string `json:"name" binding:"max=255"`
+}
+
+type LoginRequest struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// PersonalAccessToken is a long-lived API token. Only its hash is stored.
+type PersonalAccessToken struct {
+	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	UserID     uuid.UUID      `json:"-" gorm:"type:uuid;not null;column:user_id"`
+	Name       string         `json:"name" gorm:"not null;column:name"`
+	TokenHash  string         `json:"-" gorm:"not null;column:token_hash"`
+	Prefix     string         `json:"prefix" gorm:"not null;column:prefix"`
+	Scopes     pq.StringArray `json:"scopes" gorm:"type:text[];column:scopes"`
+	ExpiresAt  *time.Time     `json:"expires_at" gorm:"column:expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at" gorm:"column:last_used_at"`
+	CreatedAt  time.Time      `json:"created_at" gorm:"column:created_at"`
+}
+
+func (PersonalAccessToken) TableName() string {
+	return "personal_access_tokens"
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name      string     `json:"name" binding:"required,max=100"`
+	Scopes    []string   `json:"scopes" binding:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // omit for a token that never expires
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskSnapshot is a task as stored in a version, saved as JSON
+type TaskSnapshot Task
+
+// Value stores the snapshot as JSON
+func (s TaskSnapshot) Value() (driver.Value, error) {
+	data, err := json.Marshal(Task(s))
+	return string(data), err
+}
+
+// Scan reads the snapshot from JSON
+func (s *TaskSnapshot) Scan(value interface{}) error {
+	return scanJSON(value, (*Task)(s))
+}
+
+// TaskVersion is the state a task was in before one of its updates.
+// Versions are numbered from 1 per task.
+type TaskVersion struct {
+	ID        uuid.UUID    `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	TaskID    uuid.UUID    `json:"taskId" gorm:"type:uuid;not null;column:task_id"`
+	Version   int          `json:"version" gorm:"not null;column:version"`
+	Snapshot  TaskSnapshot `json:"snapshot" gorm:"type:jsonb;not null;column:snapshot"`
+	CreatedBy *uuid.UUID   `json:"createdBy" gorm:"type:uuid;column:created_by"`
+	CreatedAt time.Time    `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (TaskVersion) TableName() string {
+	return "task_versions"
+}
+
+// TaskVersionSummary lists a version with the fields the following change
+// made to it, leaving out the snapshot itself
+type TaskVersionSummary struct {
+	Version   int          `json:"version"`
+	CreatedBy *uuid.UUID   `json:"createdBy"`
+	CreatedAt time.Time    `json:"createdAt"`
+	Changes   FieldChanges `json:"changes"`
+}
+
+// ResourceNoteVersion is the notes a resource had before one of their edits
+type ResourceNoteVersion struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	ResourceID uuid.UUID  `json:"resource_id" gorm:"type:uuid;not null;column:resource_id"`
+	Version    int        `json:"version" gorm:"not null;column:version"`
+	Notes      string     `json:"notes" gorm:"not null;column:notes"`
+	CreatedBy  *uuid.UUID `json:"created_by" gorm:"type:uuid;column:created_by"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"column:created_at"`
+}
+
+func (ResourceNoteVersion) TableName() string {
+	return "resource_note_versions"
+}
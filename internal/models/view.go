@@ -0,0 +1,95 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lists a saved view can show
+const (
+	ViewTasks     = "task"
+	ViewResources = "resource"
+)
+
+// ViewFilters are the filters a saved view applies, the query parameters of
+// the list it shows. Category, completed, blocked, date_filter and project_id
+// only apply to tasks; the others below them only to resources.
+type ViewFilters struct {
+	Search   string   `json:"search,omitempty"`
+	Q        string   `json:"q,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Priority string   `json:"priority,omitempty"`
+	Status   string   `json:"status,omitempty"`
+
+	Category   string     `json:"category,omitempty"`
+	Completed  *bool      `json:"completed,omitempty"`
+	Blocked    *bool      `json:"blocked,omitempty"`
+	DateFilter string     `json:"date_filter,omitempty"`
+	ProjectID  *uuid.UUID `json:"project_id,omitempty"`
+
+	Technology    string   `json:"technology,omitempty"`
+	Type          string   `json:"type,omitempty"`
+	TagMode       string   `json:"tag_mode,omitempty"`
+	RatingMin     *int     `json:"rating_min,omitempty"`
+	RatingMax     *int     `json:"rating_max,omitempty"`
+	ProgressMin   *int     `json:"progress_min,omitempty"`
+	ProgressMax   *int     `json:"progress_max,omitempty"`
+	EstimatedTime []string `json:"estimated_time,omitempty"`
+	CreatedFrom   string   `json:"created_from,omitempty"`
+	CreatedTo     string   `json:"created_to,omitempty"`
+	CompletedFrom string   `json:"completed_from,omitempty"`
+	CompletedTo   string   `json:"completed_to,omitempty"`
+}
+
+// Value stores the filters as JSON
+func (f ViewFilters) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	return string(data), err
+}
+
+// Scan reads the filters from JSON
+func (f *ViewFilters) Scan(value interface{}) error {
+	return scanJSON(value, f)
+}
+
+// SavedView is a named list of tasks or resources: filters with the sort and
+// page size to show them in. Views are private to their creator unless shared
+// with the workspace. Task views sort by one sortBy field of the task list;
+// resource views take the sort parameter of the resource list, which a
+// descending sort order reverses.
+type SavedView struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	UserID      uuid.UUID   `json:"created_by" gorm:"type:uuid;not null;column:user_id"`
+	WorkspaceID uuid.UUID   `json:"-" gorm:"type:uuid;not null;column:workspace_id"`
+	Name        string      `json:"name" gorm:"not null;column:name"`
+	EntityType  string      `json:"entity_type" gorm:"not null;column:entity_type"`
+	Filters     ViewFilters `json:"filters" gorm:"type:jsonb;not null;column:filters"`
+	SortBy      string      `json:"sort_by" gorm:"column:sort_by"`
+	SortOrder   string      `json:"sort_order" gorm:"column:sort_order"`
+	PageSize    int         `json:"page_size" gorm:"column:page_size"`
+	Shared      bool        `json:"shared" gorm:"default:false;column:shared"`
+	CreatedAt   time.Time   `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"column:updated_at"`
+
+	// Count is the number of items the view shows right now
+	Count *int64 `json:"count,omitempty" gorm:"-"`
+}
+
+func (SavedView) TableName() string {
+	return "saved_views"
+}
+
+// SavedViewRequest represents the request body for creating or updating a
+// saved view. The sort and page size default to those of the list.
+type SavedViewRequest struct {
+	Name       string      `json:"name" binding:"required,max=100"`
+	EntityType string      `json:"entity_type" binding:"required,oneof=task resource"`
+	Filters    ViewFilters `json:"filters"`
+	SortBy     string      `json:"sort_by,omitempty" binding:"max=50"`
+	SortOrder  string      `json:"sort_order,omitempty" binding:"omitempty,oneof=asc desc"`
+	PageSize   int         `json:"page_size,omitempty" binding:"omitempty,min=1,max=100"`
+	Shared     bool        `json:"shared"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BootstrapWorkspaceID is the personal workspace of the bootstrap user
+var BootstrapWorkspaceID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+// Workspace member roles. Owners manage the workspace and its members,
+// editors change its data and viewers can only read it.
+const (
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+)
+
+type Workspace struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	Name      string    `json:"name" gorm:"not null;column:name"`
+	Personal  bool      `json:"personal" gorm:"not null;default:false;column:personal"`
+	CreatedBy uuid.UUID `json:"created_by" gorm:"type:uuid;not null;column:created_by"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Workspace) TableName() string {
+	return "workspaces"
+}
+
+// WorkspaceSummary is a workspace together with the current user's role in it
+type WorkspaceSummary struct {
+	Workspace
+	Role        string `json:"role"`
+	MemberCount int64  `json:"member_count"`
+}
+
+type WorkspaceMember struct {
+	WorkspaceID uuid.UUID `json:"workspace_id" gorm:"type:uuid;primaryKey;column:workspace_id"`
+	UserID      uuid.UUID `json:"user_id" gorm:"type:uuid;primaryKey;column:user_id"`
+	Role        string    `json:"role" gorm:"not null;column:role"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (WorkspaceMember) TableName() string {
+	return "workspace_members"
+}
+
+// WorkspaceMemberDetail is a member together with the user's email and name
+type WorkspaceMemberDetail struct {
+	WorkspaceMember
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+// WorkspaceInvitation invites whoever has an account with Email to join a workspace
+type WorkspaceInvitation struct {
+	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid();column:id"`
+	WorkspaceID uuid.UUID `json:"workspace_id" gorm:"type:uuid;not null;column:workspace_id"`
+	Email       string    `json:"email" gorm:"not null;column:email"`
+	Role        string    `json:"role" gorm:"not null;column:role"`
+	InvitedBy   uuid.UUID `json:"invited_by" gorm:"type:uuid;not null;column:invited_by"`
+	CreatedAt   time.Time `json:"created_at" gorm:"column:created_at"`
+}
+
+func (WorkspaceInvitation) TableName() string {
+	return "workspace_invitations"
+}
+
+// WorkspaceInvitationDetail is an invitation together with the workspace's name
+type WorkspaceInvitationDetail struct {
+	WorkspaceInvitation
+	WorkspaceName string `json:"workspace_name"`
+}
+
+// WorkspaceRequest represents the request body for creating or renaming a workspace
+type WorkspaceRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=255"`
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" binding:"required,email,max=255"`
+	Role  string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner editor viewer"`
+}
@@ -0,0 +1,265 @@
+// Package querylang parses the filter language of the q parameter of the list
+// endpoints, such as
+//
+//	priority:high,urgent tag:go due<2026-11-01 -status:cancelled "exact phrase"
+//
+// A query is a list of terms that must all hold. A term is a field, an
+// operator and one or more comma-separated values, any of which may match, or
+// free text matched against the searchable fields. A leading - negates a
+// term and double quotes keep spaces and punctuation in a value. Parsing only
+// checks the syntax; which fields exist and what their values mean is up to
+// the list the query filters.
+package querylang
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Limits that keep queries cheap to parse and to run
+const (
+	MaxLength = 1000
+	MaxTerms  = 50
+)
+
+// Op compares a field with its values
+type Op string
+
+// Operators; OpMatch matches any of its values, the others compare with one
+const (
+	OpMatch     Op = ":"
+	OpLess      Op = "<"
+	OpLessEq    Op = "<="
+	OpGreater   Op = ">"
+	OpGreaterEq Op = ">="
+)
+
+// Query is a parsed query: every term must hold
+type Query struct {
+	Terms []Term
+}
+
+// Term is one condition of a query. Field is empty for free text, which has
+// a single value and no operator.
+type Term struct {
+	Pos     int // position of the term, counted in characters from 1
+	Negated bool
+	Field   string // lowercased
+	Op      Op
+	Values  []Value
+}
+
+// Value is one value of a term
+type Value struct {
+	Pos    int
+	Text   string
+	Quoted bool // written in double quotes, e.g. a phrase
+}
+
+// Error is a problem with a query and the position it was found at
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", e.Msg, e.Pos)
+}
+
+// Errorf returns an Error at pos. Lists report invalid fields and values
+// with it so that every problem with a query carries its position.
+func Errorf(pos int, format string, args ...interface{}) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// parser reads a query one character at a time
+type parser struct {
+	input []rune
+	at    int // index of the next character
+}
+
+// Parse parses a query. An empty query has no terms.
+func Parse(input string) (*Query, error) {
+	p := &parser{input: []rune(input)}
+	if len(p.input) > MaxLength {
+		return nil, Errorf(MaxLength+1, "query is longer than %d characters", MaxLength)
+	}
+
+	query := &Query{}
+	for {
+		p.skipSpace()
+		if p.done() {
+			return query, nil
+		}
+		if len(query.Terms) == MaxTerms {
+			return nil, Errorf(p.pos(), "query has more than %d terms", MaxTerms)
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		query.Terms = append(query.Terms, term)
+	}
+}
+
+func (p *parser) done() bool {
+	return p.at >= len(p.input)
+}
+
+// pos is the position of the next character as reported in errors
+func (p *parser) pos() int {
+	return p.at + 1
+}
+
+func (p *parser) peek() rune {
+	if p.done() {
+		return 0
+	}
+	return p.input[p.at]
+}
+
+func (p *parser) skipSpace() {
+	for !p.done() && unicode.IsSpace(p.peek()) {
+		p.at++
+	}
+}
+
+// term reads [-] field op values, or [-] free text
+func (p *parser) term() (Term, error) {
+	term := Term{Pos: p.pos()}
+	if p.peek() == '-' {
+		term.Negated = true
+		p.at++
+		if p.done() || unicode.IsSpace(p.peek()) {
+			return Term{}, Errorf(term.Pos, "expected a term after -")
+		}
+	}
+
+	if p.peek() == '"' {
+		value, err := p.quoted()
+		if err != nil {
+			return Term{}, err
+		}
+		if !p.done() && !unicode.IsSpace(p.peek()) {
+			return Term{}, Errorf(p.pos(), "expected a space after a quote")
+		}
+		term.Values = []Value{value}
+		return term, nil
+	}
+
+	if field, op, ok := p.fieldPrefix(); ok {
+		term.Field, term.Op = strings.ToLower(field), op
+		return p.values(term)
+	}
+
+	// Free text runs to the next space
+	value := Value{Pos: p.pos()}
+	start := p.at
+	for !p.done() && !unicode.IsSpace(p.peek()) {
+		if p.peek() == '"' {
+			return Term{}, Errorf(p.pos(), "unexpected quote inside a word")
+		}
+		p.at++
+	}
+	value.Text = string(p.input[start:p.at])
+	term.Values = []Value{value}
+	return term, nil
+}
+
+// fieldPrefix reads a field name and its operator when the input continues
+// with one, leaving the input untouched otherwise
+func (p *parser) fieldPrefix() (string, Op, bool) {
+	end := p.at
+	for end < len(p.input) && (unicode.IsLetter(p.input[end]) || p.input[end] == '_') {
+		end++
+	}
+	if end == p.at || end == len(p.input) {
+		return "", "", false
+	}
+
+	var op Op
+	switch p.input[end] {
+	case ':':
+		op = OpMatch
+	case '<', '>':
+		op = Op(p.input[end])
+		if end+1 < len(p.input) && p.input[end+1] == '=' {
+			op += "="
+		}
+	default:
+		return "", "", false
+	}
+	field := string(p.input[p.at:end])
+	p.at = end + len(op)
+	return field, op, true
+}
+
+// values reads the comma-separated values after a field's operator
+func (p *parser) values(term Term) (Term, error) {
+	for {
+		if p.done() || unicode.IsSpace(p.peek()) || p.peek() == ',' {
+			return Term{}, Errorf(p.pos(), "expected a value after %s%s", term.Field, term.Op)
+		}
+
+		var value Value
+		if p.peek() == '"' {
+			var err error
+			if value, err = p.quoted(); err != nil {
+				return Term{}, err
+			}
+		} else {
+			value.Pos = p.pos()
+			start := p.at
+			for !p.done() && !unicode.IsSpace(p.peek()) && p.peek() != ',' {
+				if p.peek() == '"' {
+					return Term{}, Errorf(p.pos(), "unexpected quote inside a value")
+				}
+				p.at++
+			}
+			value.Text = string(p.input[start:p.at])
+		}
+		term.Values = append(term.Values, value)
+
+		if p.peek() != ',' {
+			break
+		}
+		p.at++
+	}
+
+	if !p.done() && !unicode.IsSpace(p.peek()) {
+		return Term{}, Errorf(p.pos(), "expected a space or a comma after a value")
+	}
+	if term.Op != OpMatch && len(term.Values) > 1 {
+		return Term{}, Errorf(term.Values[1].Pos, "%s compares with a single value", term.Op)
+	}
+	return term, nil
+}
+
+// quoted reads a value in double quotes; \" and \\ stand for " and \
+func (p *parser) quoted() (Value, error) {
+	value := Value{Pos: p.pos(), Quoted: true}
+	p.at++
+
+	var text strings.Builder
+	for {
+		if p.done() {
+			return Value{}, Errorf(value.Pos, "unterminated quote")
+		}
+		c := p.peek()
+		p.at++
+		switch {
+		case c == '"':
+			value.Text = text.String()
+			if value.Text == "" {
+				return Value{}, Errorf(value.Pos, "empty quotes")
+			}
+			return value, nil
+		case c == '\\' && !p.done() && (p.peek() == '"' || p.peek() == '\\'):
+			text.WriteRune(p.peek())
+			p.at++
+		default:
+			text.WriteRune(c)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package querylang
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []Term
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"priority:high,urgent", []Term{
+			{Pos: 1, Field: "priority", Op: OpMatch, Values: []Value{{Pos: 10, Text: "high"}, {Pos: 15, Text: "urgent"}}},
+		}},
+		{"-status:cancelled", []Term{
+			{Pos: 1, Negated: true, Field: "status", Op: OpMatch, Values: []Value{{Pos: 9, Text: "cancelled"}}},
+		}},
+		{"  Priority:HIGH  ", []Term{
+			{Pos: 3, Field: "priority", Op: OpMatch, Values: []Value{{Pos: 12, Text: "HIGH"}}},
+		}},
+		{"due<=2026-11-01 rating>=4", []Term{
+			{Pos: 1, Field: "due", Op: OpLessEq, Values: []Value{{Pos: 6, Text: "2026-11-01"}}},
+			{Pos: 17, Field: "rating", Op: OpGreaterEq, Values: []Value{{Pos: 25, Text: "4"}}},
+		}},
+		{"due<friday due>monday", []Term{
+			{Pos: 1, Field: "due", Op: OpLess, Values: []Value{{Pos: 5, Text: "friday"}}},
+			{Pos: 12, Field: "due", Op: OpGreater, Values: []Value{{Pos: 16, Text: "monday"}}},
+		}},
+		{"go rout", []Term{
+			{Pos: 1, Values: []Value{{Pos: 1, Text: "go"}}},
+			{Pos: 4, Values: []Value{{Pos: 4, Text: "rout"}}},
+		}},
+		{`"exact phrase"`, []Term{
+			{Pos: 1, Values: []Value{{Pos: 1, Text: "exact phrase", Quoted: true}}},
+		}},
+		{`-"not this" -word`, []Term{
+			{Pos: 1, Negated: true, Values: []Value{{Pos: 2, Text: "not this", Quoted: true}}},
+			{Pos: 13, Negated: true, Values: []Value{{Pos: 14, Text: "word"}}},
+		}},
+		{`note:"say \"hi\" \\ now \n"`, []Term{
+			{Pos: 1, Field: "note", Op: OpMatch, Values: []Value{{Pos: 6, Text: `say "hi" \ now \n`, Quoted: true}}},
+		}},
+		{`tag:"a b",c,"d"`, []Term{
+			{Pos: 1, Field: "tag", Op: OpMatch, Values: []Value{{Pos: 5, Text: "a b", Quoted: true}, {Pos: 11, Text: "c"}, {Pos: 13, Text: "d", Quoted: true}}},
+		}},
+		// Only letters and underscores make a field name
+		{"foo-bar due_date:x 2x:y", []Term{
+			{Pos: 1, Values: []Value{{Pos: 1, Text: "foo-bar"}}},
+			{Pos: 9, Field: "due_date", Op: OpMatch, Values: []Value{{Pos: 18, Text: "x"}}},
+			{Pos: 20, Values: []Value{{Pos: 20, Text: "2x:y"}}},
+		}},
+		{"a:b:c", []Term{
+			{Pos: 1, Field: "a", Op: OpMatch, Values: []Value{{Pos: 3, Text: "b:c"}}},
+		}},
+		// Positions count characters, not bytes
+		{"été:x tag:y", []Term{
+			{Pos: 1, Field: "été", Op: OpMatch, Values: []Value{{Pos: 5, Text: "x"}}},
+			{Pos: 7, Field: "tag", Op: OpMatch, Values: []Value{{Pos: 11, Text: "y"}}},
+		}},
+	}
+	for _, tt := range tests {
+		query, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(query.Terms, tt.want) {
+			t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tt.input, query.Terms, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{"-", 1, "expected a term after -"},
+		{"go - x", 4, "expected a term after -"},
+		{"priority:", 10, "expected a value after priority:"},
+		{"priority: high", 10, "expected a value after priority:"},
+		{"priority:high,", 15, "expected a value after priority:"},
+		{"priority:,high", 10, "expected a value after priority:"},
+		{"due>", 5, "expected a value after due>"},
+		{"due<1,2", 7, "< compares with a single value"},
+		{"rating>=4,5", 11, ">= compares with a single value"},
+		{`"unterminated`, 1, "unterminated quote"},
+		{`tag:"open`, 5, "unterminated quote"},
+		{`go ""`, 4, "empty quotes"},
+		{`"a"b`, 4, "expected a space after a quote"},
+		{`wo"rd`, 3, "unexpected quote inside a word"},
+		{`tag:a"b`, 6, "unexpected quote inside a value"},
+		{`tag:"a"b`, 8, "expected a space or a comma after a value"},
+		{strings.Repeat("a", MaxLength+1), MaxLength + 1, "longer than 1000 characters"},
+		{strings.Repeat("a ", MaxTerms+1), 2*MaxTerms + 1, "more than 50 terms"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var queryErr *Error
+		if !errors.As(err, &queryErr) {
+			t.Errorf("Parse(%q) error = %v, want an *Error", tt.input, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos || !strings.Contains(queryErr.Msg, tt.msg) {
+			t.Errorf("Parse(%q) error = %q at %d, want %q at %d", tt.input, queryErr.Msg, queryErr.Pos, tt.msg, tt.pos)
+		}
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	for _, input := range []string{
+		strings.Repeat("a", MaxLength),
+		strings.Repeat("é", MaxLength),
+		strings.TrimSpace(strings.Repeat("a ", MaxTerms)),
+	} {
+		if _, err := Parse(input); err != nil {
+			t.Errorf("Parse of %d characters: %v", len([]rune(input)), err)
+		}
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	err := Errorf(7, "unknown field %q", "colour")
+	if got, want := err.Error(), `unknown field "colour" at position 7`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package recurrence
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func date(value string) time.Time {
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func mustParse(t *testing.T, value string) *Rule {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", value, err)
+	}
+	return rule
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"FREQ=DAILY;INTERVAL=1", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=mo,fr", "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{" FREQ=WEEKLY ; INTERVAL=2 ; BYDAY=TU ", "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31,-1;COUNT=3", "FREQ=MONTHLY;BYMONTHDAY=31,-1;COUNT=3"},
+		{"FREQ=WEEKLY;WKST=SU;UNTIL=20261231T235959Z", "FREQ=WEEKLY;WKST=SU;UNTIL=20261231"},
+		{"FREQ=DAILY;UNTIL=20260305", "FREQ=DAILY;UNTIL=20260305"},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.value).String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"RRULE:",
+		"FREQ",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260301",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-32",
+		"FREQ=WEEKLY;WKST=XX",
+		"FREQ=DAILY;BYSETPOS=1",
+	} {
+		if rule, err := Parse(value); err == nil {
+			t.Errorf("Parse(%q) = %v, want an error", value, rule)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		rule    string
+		dtstart string
+		after   string
+		want    string // empty when the series has ended
+	}{
+		{"FREQ=DAILY;INTERVAL=2", "2026-03-02", "2026-03-02", "2026-03-04"},
+		{"FREQ=DAILY;INTERVAL=2", "2026-03-02", "2026-03-05", "2026-03-06"},
+		{"FREQ=DAILY", "2026-03-02", "2026-02-01", "2026-03-02"},
+
+		// 2026-03-02 is a Monday
+		{"FREQ=WEEKLY;BYDAY=MO,FR", "2026-03-02", "2026-03-02", "2026-03-06"},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", "2026-03-02", "2026-03-06", "2026-03-09"},
+		{"FREQ=WEEKLY;BYDAY=MO,FR", "2026-03-04", "2026-03-04", "2026-03-06"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "2026-03-02", "2026-03-02", "2026-03-03"},
+		{"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU", "2026-03-02", "2026-03-03", "2026-03-17"},
+		{"FREQ=WEEKLY;WKST=SU;INTERVAL=2;BYDAY=SU", "2026-03-02", "2026-03-02", "2026-03-15"},
+
+		// Months without the day are skipped
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31", "2026-01-31", "2026-03-31"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31", "2026-03-31", "2026-05-31"},
+		{"FREQ=MONTHLY", "2026-01-31", "2026-01-31", "2026-03-31"},
+		{"FREQ=MONTHLY;BYMONTHDAY=-1", "2026-01-31", "2026-01-31", "2026-02-28"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,15", "2026-03-01", "2026-03-01", "2026-03-15"},
+
+		// DTSTART counts towards COUNT; UNTIL is inclusive
+		{"FREQ=DAILY;COUNT=3", "2026-03-02", "2026-03-03", "2026-03-04"},
+		{"FREQ=DAILY;COUNT=3", "2026-03-02", "2026-03-04", ""},
+		{"FREQ=DAILY;COUNT=1", "2026-03-02", "2026-03-02", ""},
+		{"FREQ=DAILY;UNTIL=20260305", "2026-03-02", "2026-03-04", "2026-03-05"},
+		{"FREQ=DAILY;UNTIL=20260305", "2026-03-02", "2026-03-05", ""},
+
+		// February never has a 30th, so the search stops after maxPeriods
+		{"FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", "2026-02-01", "2026-02-01", ""},
+	}
+	for _, tt := range tests {
+		got, ok := mustParse(t, tt.rule).Next(date(tt.dtstart), date(tt.after))
+		if tt.want == "" {
+			if ok {
+				t.Errorf("%s from %s: Next(%s) = %s, want the series to have ended", tt.rule, tt.dtstart, tt.after, got.Format("2006-01-02"))
+			}
+			continue
+		}
+		if !ok || !got.Equal(date(tt.want)) {
+			t.Errorf("%s from %s: Next(%s) = %s, %v; want %s", tt.rule, tt.dtstart, tt.after, got.Format("2006-01-02"), ok, tt.want)
+		}
+	}
+}
+
+func TestNextIgnoresTimeOfDay(t *testing.T) {
+	rule := mustParse(t, "FREQ=DAILY")
+	got, ok := rule.Next(time.Date(2026, 3, 2, 18, 30, 0, 0, time.UTC), time.Date(2026, 3, 2, 23, 59, 0, 0, time.UTC))
+	if !ok || !got.Equal(date("2026-03-03")) {
+		t.Errorf("Next = %s, %v; want 2026-03-03", got, ok)
+	}
+}
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		rule     string
+		dtstart  string
+		from, to string
+		limit    int
+		want     []string
+	}{
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR", "2026-03-02", "2026-03-04", "2026-03-11", 10,
+			[]string{"2026-03-04", "2026-03-06", "2026-03-09", "2026-03-11"}},
+		{"FREQ=WEEKLY;BYDAY=MO,WE,FR", "2026-03-02", "2026-03-04", "2026-03-11", 2,
+			[]string{"2026-03-04", "2026-03-06"}},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31", "2026-01-01", "2026-12-31", 100,
+			[]string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31", "2026-08-31", "2026-10-31", "2026-12-31"}},
+		{"FREQ=DAILY;COUNT=3", "2026-03-02", "2026-03-01", "2026-03-31", 100,
+			[]string{"2026-03-02", "2026-03-03", "2026-03-04"}},
+		{"FREQ=DAILY;UNTIL=20260304", "2026-03-02", "2026-03-03", "2026-03-31", 100,
+			[]string{"2026-03-03", "2026-03-04"}},
+		{"FREQ=DAILY", "2026-03-02", "2026-02-01", "2026-02-28", 100, nil},
+		{"FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", "2026-02-01", "2026-01-01", "2100-01-01", 100,
+			[]string{"2026-02-01"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, occurrence := range mustParse(t, tt.rule).Between(date(tt.dtstart), date(tt.from), date(tt.to), tt.limit) {
+			got = append(got, occurrence.Format("2006-01-02"))
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s from %s: Between(%s, %s, %d) = %v, want %v", tt.rule, tt.dtstart, tt.from, tt.to, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package repository
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"diary-backend/internal/querylang"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// valueKind is how the values of a filter field are read and compared
+type valueKind int
+
+const (
+	kindEnum   valueKind = iota // one of a fixed set of values
+	kindText                    // any text, matched case-insensitively
+	kindTags                    // a tags array holding any of the values
+	kindDate                    // YYYY-MM-DD, today, tomorrow, yesterday or a weekday
+	kindNumber                  // a whole number
+	kindBool                    // true or false
+	kindUUID                    // an ID, or none when unset
+)
+
+// filterField is a field the q parameter can filter a list on
+type filterField[T any] struct {
+	kind   valueKind
+	column string   // SQL expression the field is read from
+	values []string // allowed values of an enum
+	// ordered enums compare by the position of their values
+	ordered bool
+	// get reads the field for the in-memory repositories: a string for enums
+	// and text, []string for tags, time.Time or *time.Time for dates, *int for
+	// numbers, bool or *uuid.UUID
+	get func(T) interface{}
+}
+
+// filterSpec is what the q parameter of a list can filter on
+type filterSpec[T any] struct {
+	fields map[string]filterField[T]
+	// text returns the fields free text is matched against, most important
+	// first, like the search_vector column
+	text func(T) []string
+}
+
+// condition is a compiled term of a q filter: SQL for the GORM repositories
+// and the same test for the in-memory ones
+type condition[T any] struct {
+	sql   string
+	vars  []interface{}
+	match func(T) bool
+}
+
+// applyConditions adds compiled q filter terms to a query
+func applyConditions[T any](query *gorm.DB, conditions []condition[T]) *gorm.DB {
+	for _, cond := range conditions {
+		query = query.Where(cond.sql, cond.vars...)
+	}
+	return query
+}
+
+// matchesConditions reports whether an item passes every compiled q filter term
+func matchesConditions[T any](item T, conditions []condition[T]) bool {
+	for _, cond := range conditions {
+		if !cond.match(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// compile parses a q filter and compiles each of its terms. Errors are
+// *querylang.Error with the position of the problem.
+func (s filterSpec[T]) compile(q string, now time.Time) ([]condition[T], error) {
+	query, err := querylang.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := make([]condition[T], 0, len(query.Terms))
+	for _, term := range query.Terms {
+		cond, err := s.term(term, now)
+		if err != nil {
+			return nil, err
+		}
+		if term.Negated {
+			// Unset fields satisfy negated terms, as "not in project X"
+			// includes tasks without a project
+			match := cond.match
+			cond.sql = "NOT coalesce((" + cond.sql + "), false)"
+			cond.match = func(item T) bool { return !match(item) }
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+// term compiles one term, not yet negated
+func (s filterSpec[T]) term(term querylang.Term, now time.Time) (condition[T], error) {
+	if term.Field == "" {
+		return s.freeText(term.Values[0])
+	}
+
+	field, ok := s.fields[term.Field]
+	if !ok {
+		names := make([]string, 0, len(s.fields))
+		for name := range s.fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return condition[T]{}, querylang.Errorf(term.Pos, "unknown field %q, expected one of %s", term.Field, strings.Join(names, ", "))
+	}
+	ordered := field.kind == kindDate || field.kind == kindNumber || (field.kind == kindEnum && field.ordered)
+	if term.Op != querylang.OpMatch && !ordered {
+		return condition[T]{}, querylang.Errorf(term.Pos, "%s cannot be compared with %s, use %s:", term.Field, term.Op, term.Field)
+	}
+
+	switch field.kind {
+	case kindEnum:
+		return enumCondition(term, field)
+	case kindText:
+		values := make([]string, len(term.Values))
+		for i, value := range term.Values {
+			values[i] = strings.ToLower(value.Text)
+		}
+		return condition[T]{
+			sql:  "lower(" + field.column + ") IN ?",
+			vars: []interface{}{values},
+			match: func(item T) bool {
+				return slices.Contains(values, strings.ToLower(field.get(item).(string)))
+			},
+		}, nil
+	case kindTags:
+		tags := make([]string, len(term.Values))
+		for i, value := range term.Values {
+			tags[i] = value.Text
+		}
+		return condition[T]{
+			sql:  field.column + " && ?",
+			vars: []interface{}{pq.StringArray(tags)},
+			match: func(item T) bool {
+				for _, tag := range field.get(item).([]string) {
+					if slices.Contains(tags, tag) {
+						return true
+					}
+				}
+				return false
+			},
+		}, nil
+	case kindDate:
+		return dateCondition(term, field, now)
+	case kindNumber:
+		return numberCondition(term, field)
+	case kindBool:
+		values := make([]bool, len(term.Values))
+		for i, value := range term.Values {
+			b, ok := map[string]bool{"true": true, "yes": true, "false": false, "no": false}[strings.ToLower(value.Text)]
+			if !ok {
+				return condition[T]{}, querylang.Errorf(value.Pos, "%s must be true or false, not %q", term.Field, value.Text)
+			}
+			values[i] = b
+		}
+		return condition[T]{
+			sql:   "(" + field.column + ") IN ?",
+			vars:  []interface{}{values},
+			match: func(item T) bool { return slices.Contains(values, field.get(item).(bool)) },
+		}, nil
+	case kindUUID:
+		return uuidCondition(term, field)
+	}
+	return condition[T]{}, querylang.Errorf(term.Pos, "%s cannot be filtered on", term.Field)
+}
+
+// freeText matches a word as a prefix of the searchable words, like the search
+// parameter, and a quoted phrase as those words in that order.
+//
+// Phrases match differently per backend. Postgres uses phraseto_tsquery, so
+// phrase words are stemmed and stop words are dropped or only hold a place:
+// "running tests" also matches "run test" and "the plan" matches any "plan".
+// The in-memory
+// repositories, which have no stemmer, match the exact words in order,
+// ignoring case and punctuation. Tests against them should stick to phrases
+// both backends agree on.
+func (s filterSpec[T]) freeText(value querylang.Value) (condition[T], error) {
+	if value.Quoted {
+		phrase := strings.ToLower(strings.Join(searchTerms(value.Text), " "))
+		if phrase == "" {
+			return condition[T]{}, querylang.Errorf(value.Pos, "phrase has no words to search for")
+		}
+		return condition[T]{
+			sql:  "search_vector @@ phraseto_tsquery('english', ?)",
+			vars: []interface{}{value.Text},
+			match: func(item T) bool {
+				for _, field := range s.text(item) {
+					if strings.Contains(" "+strings.Join(searchTerms(field), " ")+" ", " "+phrase+" ") {
+						return true
+					}
+				}
+				return false
+			},
+		}, nil
+	}
+
+	tsquery := prefixQuery(value.Text)
+	if tsquery == "" {
+		return condition[T]{}, querylang.Errorf(value.Pos, "%q has no words to search for", value.Text)
+	}
+	terms := searchTerms(value.Text)
+	return condition[T]{
+		sql:   "search_vector @@ to_tsquery('english', ?)",
+		vars:  []interface{}{tsquery},
+		match: func(item T) bool { return textMatch(terms, s.text(item)...) > 0 },
+	}, nil
+}
+
+// enumCondition matches any of the values of an enum, or compares an ordered
+// enum by the position of its values
+func enumCondition[T any](term querylang.Term, field filterField[T]) (condition[T], error) {
+	values := make([]string, len(term.Values))
+	for i, value := range term.Values {
+		values[i] = strings.ToLower(value.Text)
+		if !slices.Contains(field.values, values[i]) {
+			return condition[T]{}, querylang.Errorf(value.Pos, "%s must be one of %s, not %q", term.Field, strings.Join(field.values, ", "), value.Text)
+		}
+	}
+
+	if term.Op == querylang.OpMatch {
+		return condition[T]{
+			sql:   field.column + " IN ?",
+			vars:  []interface{}{values},
+			match: func(item T) bool { return slices.Contains(values, field.get(item).(string)) },
+		}, nil
+	}
+
+	rank := func(value string) int { return slices.Index(field.values, value) }
+	var rankSQL strings.Builder
+	rankSQL.WriteString("CASE " + field.column)
+	for i, value := range field.values {
+		rankSQL.WriteString(" WHEN '" + value + "' THEN " + strconv.Itoa(i))
+	}
+	rankSQL.WriteString(" END")
+
+	want := rank(values[0])
+	return condition[T]{
+		sql:  rankSQL.String() + " " + string(term.Op) + " ?",
+		vars: []interface{}{want},
+		match: func(item T) bool {
+			got := rank(field.get(item).(string))
+			return got >= 0 && compareInts(got, term.Op, want)
+		},
+	}, nil
+}
+
+// dateCondition matches any of the dates, or compares with one
+func dateCondition[T any](term querylang.Term, field filterField[T], now time.Time) (condition[T], error) {
+	dates := make([]string, len(term.Values))
+	for i, value := range term.Values {
+		date, ok := parseFilterDate(value.Text, now)
+		if !ok {
+			return condition[T]{}, querylang.Errorf(value.Pos, "%s must be a date such as 2026-11-01, today, tomorrow, yesterday or a weekday, not %q", term.Field, value.Text)
+		}
+		dates[i] = date
+	}
+
+	get := func(item T) (string, bool) {
+		switch value := field.get(item).(type) {
+		case time.Time:
+			return value.Format("2006-01-02"), true
+		case *time.Time:
+			if value != nil {
+				return value.Format("2006-01-02"), true
+			}
+		}
+		return "", false
+	}
+	if term.Op == querylang.OpMatch {
+		return condition[T]{
+			sql:  field.column + " IN ?",
+			vars: []interface{}{dates},
+			match: func(item T) bool {
+				date, ok := get(item)
+				return ok && slices.Contains(dates, date)
+			},
+		}, nil
+	}
+	return condition[T]{
+		sql:  field.column + " " + string(term.Op) + " ?",
+		vars: []interface{}{dates[0]},
+		match: func(item T) bool {
+			date, ok := get(item)
+			return ok && compareInts(strings.Compare(date, dates[0]), term.Op, 0)
+		},
+	}, nil
+}
+
+// numberCondition matches any of the numbers, or compares with one
+func numberCondition[T any](term querylang.Term, field filterField[T]) (condition[T], error) {
+	numbers := make([]int, len(term.Values))
+	for i, value := range term.Values {
+		n, err := strconv.Atoi(value.Text)
+		if err != nil {
+			return condition[T]{}, querylang.Errorf(value.Pos, "%s must be a whole number, not %q", term.Field, value.Text)
+		}
+		numbers[i] = n
+	}
+
+	if term.Op == querylang.OpMatch {
+		return condition[T]{
+			sql:  field.column + " IN ?",
+			vars: []interface{}{numbers},
+			match: func(item T) bool {
+				got := field.get(item).(*int)
+				return got != nil && slices.Contains(numbers, *got)
+			},
+		}, nil
+	}
+	return condition[T]{
+		sql:  field.column + " " + string(term.Op) + " ?",
+		vars: []interface{}{numbers[0]},
+		match: func(item T) bool {
+			got := field.get(item).(*int)
+			return got != nil && compareInts(*got, term.Op, numbers[0])
+		},
+	}, nil
+}
+
+// uuidCondition matches any of the IDs; none matches an unset field
+func uuidCondition[T any](term querylang.Term, field filterField[T]) (condition[T], error) {
+	var ids []uuid.UUID
+	none := false
+	for _, value := range term.Values {
+		if strings.EqualFold(value.Text, "none") {
+			none = true
+			continue
+		}
+		id, err := uuid.Parse(value.Text)
+		if err != nil {
+			return condition[T]{}, querylang.Errorf(value.Pos, "%s must be an ID or none, not %q", term.Field, value.Text)
+		}
+		ids = append(ids, id)
+	}
+
+	var parts []string
+	var vars []interface{}
+	if none {
+		parts = append(parts, field.column+" IS NULL")
+	}
+	if len(ids) > 0 {
+		parts = append(parts, field.column+" IN ?")
+		vars = append(vars, ids)
+	}
+	return condition[T]{
+		sql:  "(" + strings.Join(parts, " OR ") + ")",
+		vars: vars,
+		match: func(item T) bool {
+			got := field.get(item).(*uuid.UUID)
+			if got == nil {
+				return none
+			}
+			return slices.Contains(ids, *got)
+		},
+	}, nil
+}
+
+// parseFilterDate reads a date of the filter language as YYYY-MM-DD.
+// Weekdays name the next such day, today included.
+func parseFilterDate(value string, now time.Time) (string, bool) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date.Format("2006-01-02"), true
+	}
+	switch strings.ToLower(value) {
+	case "today":
+		return now.Format("2006-01-02"), true
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), true
+	case "yesterday":
+		return now.AddDate(0, 0, -1).Format("2006-01-02"), true
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(value, day.String()) || strings.EqualFold(value, day.String()[:3]) {
+			ahead := (int(day) - int(now.Weekday()) + 7) % 7
+			return now.AddDate(0, 0, ahead).Format("2006-01-02"), true
+		}
+	}
+	return "", false
+}
+
+// compareInts applies a comparison operator
+func compareInts(a int, op querylang.Op, b int) bool {
+	switch op {
+	case querylang.OpLess:
+		return a < b
+	case querylang.OpLessEq:
+		return a <= b
+	case querylang.OpGreater:
+		return a > b
+	case querylang.OpGreaterEq:
+		return a >= b
+	}
+	return a == b
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/querylang"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// filterNow is a Wednesday
+var filterNow = time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+
+// filterTasks are the tasks the q filter tests run against
+func filterTasks(projectID uuid.UUID) []models.Task {
+	description := "Users cannot sign in with SSO"
+	return []models.Task{
+		{Title: "Fix login bug", Description: &description, Priority: "high", Status: "in-progress", Category: "office",
+			Tags: pq.StringArray{"auth", "bug"}, DueDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), ProjectID: &projectID},
+		{Title: "Write tests", Priority: "low", Status: "pending", Category: "office",
+			Tags: pq.StringArray{"testing"}, DueDate: time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{Title: "Running errands", Priority: "urgent", Status: "completed", Category: "personal", Completed: true,
+			DueDate: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+// matchingTitles compiles q and returns the titles of the items it matches
+func matchingTitles[T any](t *testing.T, spec filterSpec[T], q string, items []T, title func(T) string) []string {
+	t.Helper()
+	conditions, err := spec.compile(q, filterNow)
+	if err != nil {
+		t.Fatalf("compile(%q): %v", q, err)
+	}
+	titles := []string{}
+	for _, item := range items {
+		if matchesConditions(item, conditions) {
+			titles = append(titles, title(item))
+		}
+	}
+	return titles
+}
+
+func TestCompileTaskFilters(t *testing.T) {
+	projectID := uuid.New()
+	tasks := filterTasks(projectID)
+	spec := taskFilterSpec(func(task models.Task) bool { return task.Title == "Write tests" })
+	title := func(task models.Task) string { return task.Title }
+
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"", []string{"Fix login bug", "Write tests", "Running errands"}},
+		{"priority:high,urgent", []string{"Fix login bug", "Running errands"}},
+		{"PRIORITY:HIGH", []string{"Fix login bug"}},
+		{"priority>=high", []string{"Fix login bug", "Running errands"}},
+		{"priority<medium", []string{"Write tests"}},
+		{"-status:completed", []string{"Fix login bug", "Write tests"}},
+		{"category:office -tag:auth", []string{"Write tests"}},
+		{"tag:bug,testing", []string{"Fix login bug", "Write tests"}},
+		{"-tag:bug", []string{"Write tests", "Running errands"}},
+		{"due<today", []string{"Fix login bug"}},
+		{"due:today", []string{"Running errands"}},
+		{"due<=friday", []string{"Fix login bug", "Write tests", "Running errands"}},
+		{"due>wednesday", []string{"Write tests"}},
+		{"due:2026-03-03,yesterday", []string{"Fix login bug"}},
+		{"completed:true", []string{"Running errands"}},
+		{"completed:no", []string{"Fix login bug", "Write tests"}},
+		{"blocked:true", []string{"Write tests"}},
+		{"project:none", []string{"Write tests", "Running errands"}},
+		{"project:" + projectID.String(), []string{"Fix login bug"}},
+		{"project:none," + projectID.String(), []string{"Fix login bug", "Write tests", "Running errands"}},
+		// Negated terms hold for unset fields
+		{"-project:" + projectID.String(), []string{"Write tests", "Running errands"}},
+
+		// Free text matches word prefixes in any searchable field
+		{"log", []string{"Fix login bug"}},
+		{"sso users", []string{"Fix login bug"}},
+		{"-bug", []string{"Write tests", "Running errands"}},
+		// Phrases match the words in order
+		{`"sign in"`, []string{"Fix login bug"}},
+		{`"SIGN, IN!"`, []string{"Fix login bug"}},
+		{`"in sign"`, []string{}},
+		{`"running errands"`, []string{"Running errands"}},
+		// Unlike Postgres, the in-memory repositories do not stem phrases
+		{`"run errand"`, []string{}},
+		{`"sign"`, []string{"Fix login bug"}},
+		{`"sig"`, []string{}},
+	}
+	for _, tt := range tests {
+		if got := matchingTitles(t, spec, tt.q, tasks, title); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("q=%s matches %v, want %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestCompileResourceFilters(t *testing.T) {
+	four, five := 4, 5
+	completed := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	resources := []models.Resource{
+		{Title: "Tour of Go", Type: "course", Status: "completed", Priority: "high", Technology: "Go", Rating: &five, CompletedAt: &completed},
+		{Title: "Effective Go", Type: "article", Status: "reading", Priority: "medium", Technology: "go", Rating: &four},
+		{Title: "Rust book", Type: "book", Status: "to-read", Priority: "low", Technology: "Rust"},
+	}
+	title := func(resource models.Resource) string { return resource.Title }
+
+	tests := []struct {
+		q    string
+		want []string
+	}{
+		{"technology:GO", []string{"Tour of Go", "Effective Go"}},
+		{"rating>=4", []string{"Tour of Go", "Effective Go"}},
+		{"rating:4,5 -type:course", []string{"Effective Go"}},
+		{"rating<5", []string{"Effective Go"}},
+		// Unrated resources hold for negated rating terms only
+		{"-rating:5", []string{"Effective Go", "Rust book"}},
+		{"priority>low", []string{"Tour of Go", "Effective Go"}},
+		{"completed<=2026-03-01", []string{"Tour of Go"}},
+		{"-completed:2026-03-01", []string{"Effective Go", "Rust book"}},
+	}
+	for _, tt := range tests {
+		if got := matchingTitles(t, resourceFilterSpec, tt.q, resources, title); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("q=%s matches %v, want %v", tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	spec := taskFilterSpec(nil)
+	tests := []struct {
+		q   string
+		pos int
+		msg string
+	}{
+		{"colour:red", 1, `unknown field "colour"`},
+		{"tag:go colour:red", 8, `unknown field "colour"`},
+		{"status>pending", 1, "status cannot be compared with >"},
+		{"tag<=go", 1, "tag cannot be compared with <="},
+		{"priority:high,extreme", 15, "priority must be one of"},
+		{"due:someday", 5, "due must be a date"},
+		{"completed:maybe", 11, "completed must be true or false"},
+		{"project:none,abc", 14, "project must be an ID or none"},
+		{`"!!"`, 1, "phrase has no words"},
+		{"tag:x !!!", 7, `"!!!" has no words`},
+		{"priority:", 10, "expected a value"},
+	}
+	for _, tt := range tests {
+		_, err := spec.compile(tt.q, filterNow)
+		var queryErr *querylang.Error
+		if !errors.As(err, &queryErr) {
+			t.Errorf("compile(%q) error = %v, want a *querylang.Error", tt.q, err)
+			continue
+		}
+		if queryErr.Pos != tt.pos || !strings.Contains(queryErr.Msg, tt.msg) {
+			t.Errorf("compile(%q) error = %q at %d, want %q at %d", tt.q, queryErr.Msg, queryErr.Pos, tt.msg, tt.pos)
+		}
+	}
+
+	if _, err := resourceFilterSpec.compile("rating:high", filterNow); err == nil || !strings.Contains(err.Error(), "rating must be a whole number") {
+		t.Errorf("compile(rating:high) error = %v, want a whole number error", err)
+	}
+}
+
+func TestMemoryListAppliesQuery(t *testing.T) {
+	repo := NewMemoryTaskRepository()
+	workspaceID := uuid.New()
+	for _, task := range filterTasks(uuid.New()) {
+		task.WorkspaceID = workspaceID
+		if err := repo.Create(context.Background(), Actor{WorkspaceID: workspaceID}, &task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tasks, page, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: ListOptions{Query: "category:office -status:completed"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	slices.Sort(titles)
+	if fmt.Sprint(titles) != "[Fix login bug Write tests]" || page.Total != 2 {
+		t.Errorf("List = %v with total %d, want the two open office tasks", titles, page.Total)
+	}
+
+	if _, _, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: ListOptions{Query: "colour:red"}}); !errors.As(err, new(*querylang.Error)) {
+		t.Errorf("List with an unknown field error = %v, want a *querylang.Error", err)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	repo := NewMemoryTaskRepository()
+	workspaceID := uuid.New()
+	for _, task := range filterTasks(uuid.New()) {
+		task.WorkspaceID = workspaceID
+		if err := repo.Create(context.Background(), Actor{WorkspaceID: workspaceID}, &task); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := repo.Counts(context.Background(), workspaceID, []TaskFilter{
+		{ListOptions: ListOptions{Query: "category:office", Limit: 1, After: "not-a-cursor"}},
+		{ListOptions: ListOptions{Query: "colour:red"}},
+		{ListOptions: ListOptions{Sort: []SortField{{Field: "colour"}}}},
+		{Priority: "urgent"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, count := range counts {
+		if count == nil {
+			got = append(got, "nil")
+			continue
+		}
+		got = append(got, fmt.Sprint(*count))
+	}
+	// Paging is ignored; filters List rejects are not counted
+	if fmt.Sprint(got) != "[2 nil nil 1]" {
+		t.Errorf("Counts = %v, want [2 nil nil 1]", got)
+	}
+}
+
+func TestGormCountsQueryOnce(t *testing.T) {
+	db, sql := dryRunDB(t)
+	repo := NewGormResourceRepository(db)
+	// A dry run cannot scan the counts, so only the query is checked
+	repo.Counts(context.Background(), uuid.New(), []ResourceFilter{
+		{Type: "book"},
+		{ListOptions: ListOptions{Query: "colour:red"}},
+		{Technology: "Go", ListOptions: ListOptions{Limit: 5}},
+	})
+	for _, want := range []string{
+		`SELECT ARRAY[(SELECT count(*) FROM "learning_resources" WHERE workspace_id = `,
+		`AND type = 'book' AND "learning_resources"."deleted_at" IS NULL), (SELECT count(*) FROM "learning_resources" WHERE `,
+		`AND technology = 'Go' AND "learning_resources"."deleted_at" IS NULL)] AS counts`,
+	} {
+		if !strings.Contains(*sql, want) {
+			t.Errorf("SQL\n%s\ndoes not contain\n%s", *sql, want)
+		}
+	}
+	if strings.Contains(*sql, "LIMIT") {
+		t.Errorf("SQL\n%s\npages the counts", *sql)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"math"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RecalculateGoal derives a goal's progress from its linked resources. With
+// complete, an active goal is completed once every linked resource is. A
+// status is never moved back: goals completed by hand stay completed.
+func RecalculateGoal(tx *gorm.DB, goalID uuid.UUID, complete bool) error {
+	var goal models.Goal
+	if err := tx.First(&goal, "id = ?", goalID).Error; err != nil {
+		return err
+	}
+
+	var rollup struct {
+		Total     int64
+		Completed int64
+		Average   float64
+	}
+	err := tx.Table("learning_goal_resources AS gr").
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE r.status = 'completed') AS completed,
+			COALESCE(AVG(CASE WHEN r.status = 'completed' THEN 100 ELSE COALESCE(r.progress, 0) END), 0) AS average`).
+		Joins("JOIN learning_resources r ON r.id = gr.resource_id").
+		Where("gr.goal_id = ? AND r.deleted_at IS NULL", goalID).
+		Scan(&rollup).Error
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{
+		"progress": int(math.Round(rollup.Average)),
+	}
+	if complete && goal.Status == "active" && rollup.Total > 0 && rollup.Completed == rollup.Total {
+		updates["status"] = "completed"
+	}
+
+	return tx.Model(&goal).Updates(updates).Error
+}
+
+// RecalculateGoalsForResource refreshes every goal the resource is linked to
+func RecalculateGoalsForResource(tx *gorm.DB, resourceID uuid.UUID) error {
+	var goalIDs []uuid.UUID
+	err := tx.Model(&models.GoalResource{}).
+		Where("resource_id = ?", resourceID).
+		Pluck("goal_id", &goalIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, goalID := range goalIDs {
+		if err := RecalculateGoal(tx, goalID, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,463 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"diary-backend/internal/history"
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormResourceRepository stores learning resources in Postgres
+type GormResourceRepository struct {
+	db *gorm.DB
+}
+
+// NewGormResourceRepository returns a resource repository using db
+func NewGormResourceRepository(db *gorm.DB) *GormResourceRepository {
+	return &GormResourceRepository{db: db}
+}
+
+func (r *GormResourceRepository) List(ctx context.Context, workspaceID uuid.UUID, filter ResourceFilter) ([]models.Resource, Page, error) {
+	order, sort, err := filter.sortKeys(resourceSortKeys, "created_at")
+	if err != nil {
+		return nil, Page{}, err
+	}
+	query, err := r.filtered(r.db.WithContext(ctx), workspaceID, filter)
+	if err != nil {
+		return nil, Page{}, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, Page{}, err
+	}
+	return gormPage(query, "learning_resources", order, sort, filter.ListOptions, total, func(resource models.Resource) uuid.UUID { return resource.ID })
+}
+
+func (r *GormResourceRepository) Counts(ctx context.Context, workspaceID uuid.UUID, filters []ResourceFilter) ([]*int64, error) {
+	return gormCounts(r.db.WithContext(ctx), filters, func(filter ResourceFilter) (*gorm.DB, error) {
+		if _, _, err := filter.sortKeys(resourceSortKeys, "created_at"); err != nil {
+			return nil, err
+		}
+		return r.filtered(r.db, workspaceID, filter)
+	})
+}
+
+// filtered builds the query for the resources of the workspace a filter matches
+func (r *GormResourceRepository) filtered(db *gorm.DB, workspaceID uuid.UUID, filter ResourceFilter) (*gorm.DB, error) {
+	conditions, err := resourceFilterSpec.compile(filter.Query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.Model(&models.Resource{}).Where("workspace_id = ?", workspaceID)
+	if filter.Technology != "" {
+		query = query.Where("technology = ?", filter.Technology)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	if filter.RatingMin != nil {
+		query = query.Where("rating >= ?", *filter.RatingMin)
+	}
+	if filter.RatingMax != nil {
+		query = query.Where("rating <= ?", *filter.RatingMax)
+	}
+	if filter.ProgressMin != nil {
+		query = query.Where("coalesce(progress, 0) >= ?", *filter.ProgressMin)
+	}
+	if filter.ProgressMax != nil {
+		query = query.Where("coalesce(progress, 0) <= ?", *filter.ProgressMax)
+	}
+	if len(filter.EstimatedTime) > 0 {
+		var buckets []string
+		var vars []interface{}
+		for _, name := range filter.EstimatedTime {
+			bucket, ok := estimateBuckets[name]
+			switch {
+			case !ok:
+				buckets = append(buckets, "estimated_time IS NULL")
+			case bucket.max == 0:
+				buckets = append(buckets, "estimated_time >= ?")
+				vars = append(vars, bucket.min)
+			default:
+				buckets = append(buckets, "(estimated_time >= ? AND estimated_time < ?)")
+				vars = append(vars, bucket.min, bucket.max)
+			}
+		}
+		query = query.Where(strings.Join(buckets, " OR "), vars...)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", filter.CreatedTo.AddDate(0, 0, 1))
+	}
+	if filter.CompletedFrom != nil {
+		query = query.Where("completed_at >= ?", *filter.CompletedFrom)
+	}
+	if filter.CompletedTo != nil {
+		query = query.Where("completed_at < ?", filter.CompletedTo.AddDate(0, 0, 1))
+	}
+	return applyConditions(filter.ListOptions.apply(query), conditions).Session(&gorm.Session{}), nil
+}
+
+func (r *GormResourceRepository) Get(ctx context.Context, workspaceID, id uuid.UUID) (*models.Resource, error) {
+	var resource models.Resource
+	if err := r.db.WithContext(ctx).First(&resource, "id = ? AND workspace_id = ?", id, workspaceID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &resource, nil
+}
+
+func (r *GormResourceRepository) Highlights(ctx context.Context, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error) {
+	return highlights(r.db.WithContext(ctx), "learning_resources", "concat_ws(' ', description, notes)", workspaceID, search, ids)
+}
+
+func (r *GormResourceRepository) NormalizeTechnology(ctx context.Context, workspaceID uuid.UUID, value string) (string, error) {
+	return NormalizeTechnology(r.db.WithContext(ctx), workspaceID, value)
+}
+
+func (r *GormResourceRepository) NoteVersions(ctx context.Context, id uuid.UUID) ([]models.ResourceNoteVersion, error) {
+	var versions []models.ResourceNoteVersion
+	err := r.db.WithContext(ctx).Where("resource_id = ?", id).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *GormResourceRepository) NoteVersion(ctx context.Context, id uuid.UUID, n int) (*models.ResourceNoteVersion, error) {
+	var version models.ResourceNoteVersion
+	if err := r.db.WithContext(ctx).First(&version, "resource_id = ? AND version = ?", id, n).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &version, nil
+}
+
+func (r *GormResourceRepository) History(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error) {
+	return gormHistory(r.db.WithContext(ctx), workspaceID, models.EntityResource, id, limit, offset)
+}
+
+func (r *GormResourceRepository) Trash(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]models.Resource, int64, error) {
+	return gormTrash[models.Resource](r.db.WithContext(ctx), workspaceID, limit, offset)
+}
+
+// workspaceSessions selects the sessions of the workspace's live resources
+func workspaceSessions(db *gorm.DB, workspaceID uuid.UUID) *gorm.DB {
+	return db.Model(&models.Session{}).
+		Joins("JOIN learning_resources ON learning_resources.id = learning_sessions.resource_id").
+		Where("learning_resources.workspace_id = ? AND learning_resources.deleted_at IS NULL", workspaceID)
+}
+
+func (r *GormResourceRepository) Sessions(ctx context.Context, workspaceID uuid.UUID, filter SessionFilter) ([]models.Session, SessionTotals, error) {
+	query := workspaceSessions(r.db.WithContext(ctx), workspaceID)
+	if filter.ResourceID != nil {
+		query = query.Where("learning_sessions.resource_id = ?", *filter.ResourceID)
+	}
+	if filter.Technology != "" {
+		query = query.Where("learning_resources.technology = ?", filter.Technology)
+	}
+	if filter.From != nil {
+		query = query.Where("learning_sessions.session_date >= ?", filter.From.Format("2006-01-02"))
+	}
+	if filter.To != nil {
+		query = query.Where("learning_sessions.session_date <= ?", filter.To.Format("2006-01-02"))
+	}
+
+	// Finishers below each run on a fresh copy of the filtered query
+	query = query.Session(&gorm.Session{})
+
+	var totals SessionTotals
+	err := query.Select("COUNT(*) AS count, COALESCE(SUM(learning_sessions.duration_minutes), 0) AS minutes").Scan(&totals).Error
+	if err != nil {
+		return nil, SessionTotals{}, err
+	}
+
+	var sessions []models.Session
+	query = query.Select("learning_sessions.*").
+		Order("learning_sessions.session_date DESC, learning_sessions.created_at DESC").
+		Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if err := query.Find(&sessions).Error; err != nil {
+		return nil, SessionTotals{}, err
+	}
+	return sessions, totals, nil
+}
+
+func (r *GormResourceRepository) Session(ctx context.Context, workspaceID, resourceID, id uuid.UUID) (*models.Session, error) {
+	var session models.Session
+	err := workspaceSessions(r.db.WithContext(ctx), workspaceID).
+		Where("learning_sessions.id = ? AND learning_sessions.resource_id = ?", id, resourceID).
+		Select("learning_sessions.*").
+		First(&session).Error
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &session, nil
+}
+
+func (r *GormResourceRepository) Create(ctx context.Context, actor Actor, resource *models.Resource) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(resource).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntityResource, resource.ID, nil, resource)
+	})
+}
+
+func (r *GormResourceRepository) Update(ctx context.Context, actor Actor, resource *models.Resource) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Resource
+		if err := tx.First(&before, "id = ? AND workspace_id = ?", resource.ID, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Save(resource).Error; err != nil {
+			return err
+		}
+		if err := history.SaveResourceNoteVersion(tx, actor.UserID, before, *resource); err != nil {
+			return err
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityResource, resource.ID, before, resource); err != nil {
+			return err
+		}
+		return RecalculateGoalsForResource(tx, resource.ID)
+	})
+}
+
+func (r *GormResourceRepository) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	// Goals no longer count a resource in the trash
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var resource models.Resource
+		if err := tx.First(&resource, "id = ? AND workspace_id = ?", id, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Delete(&resource).Error; err != nil {
+			return err
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionDelete, models.EntityResource, resource.ID, resource, nil); err != nil {
+			return err
+		}
+		return RecalculateGoalsForResource(tx, id)
+	})
+}
+
+func (r *GormResourceRepository) Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.Resource, error) {
+	// Goals count the resource again once it is back
+	var resource models.Resource
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.Resource{}).
+			Where("id = ? AND workspace_id = ? AND deleted_at IS NOT NULL", id, actor.WorkspaceID).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionRestore, models.EntityResource, id, nil, nil); err != nil {
+			return err
+		}
+		if err := RecalculateGoalsForResource(tx, id); err != nil {
+			return err
+		}
+		return tx.First(&resource, "id = ?", id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (r *GormResourceRepository) Purge(ctx context.Context, actor Actor, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Where("workspace_id = ? AND deleted_at IS NOT NULL", actor.WorkspaceID).
+			Delete(&models.Resource{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionPurge, models.EntityResource, id, nil, nil)
+	})
+}
+
+func (r *GormResourceRepository) EmptyTrash(ctx context.Context, actor Actor) (int64, error) {
+	var purged []models.Resource
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("workspace_id = ? AND deleted_at IS NOT NULL", actor.WorkspaceID).
+			Delete(&purged).Error
+		if err != nil {
+			return err
+		}
+		for _, resource := range purged {
+			if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionPurge, models.EntityResource, resource.ID, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return int64(len(purged)), err
+}
+
+func (r *GormResourceRepository) CreateSession(ctx context.Context, actor Actor, session *models.Session) (*models.Resource, error) {
+	var resource models.Resource
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&resource, "id = ? AND workspace_id = ?", session.ResourceID, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntitySession, session.ID, nil, session); err != nil {
+			return err
+		}
+		if resource.Status == "" || resource.Status == "to-read" || resource.Status == "bookmarked" {
+			before := resource
+			resource.Status = "reading"
+			if err := tx.Model(&resource).Update("status", resource.Status).Error; err != nil {
+				return err
+			}
+			return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityResource, resource.ID, before, resource)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &resource, nil
+}
+
+func (r *GormResourceRepository) UpdateSession(ctx context.Context, actor Actor, session *models.Session) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Session
+		err := workspaceSessions(tx, actor.WorkspaceID).
+			Where("learning_sessions.id = ?", session.ID).
+			Select("learning_sessions.*").
+			First(&before).Error
+		if err != nil {
+			return notFound(err)
+		}
+		if err := tx.Save(session).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntitySession, session.ID, before, session)
+	})
+}
+
+func (r *GormResourceRepository) DeleteSession(ctx context.Context, actor Actor, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var session models.Session
+		err := workspaceSessions(tx, actor.WorkspaceID).
+			Where("learning_sessions.id = ?", id).
+			Select("learning_sessions.*").
+			First(&session).Error
+		if err != nil {
+			return notFound(err)
+		}
+		if err := tx.Delete(&session).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionDelete, models.EntitySession, session.ID, session, nil)
+	})
+}
+
+func (r *GormResourceRepository) Stats(ctx context.Context, workspaceID uuid.UUID, filter ResourceStatsFilter) (*ResourceStats, error) {
+	db := r.db.WithContext(ctx)
+
+	// resources builds the filtered base query shared by the resource aggregates
+	resources := func() *gorm.DB {
+		query := db.Model(&models.Resource{}).Where("workspace_id = ?", workspaceID)
+		if filter.Technology != "" {
+			query = query.Where("technology = ?", filter.Technology)
+		}
+		if filter.From != nil {
+			query = query.Where("created_at >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			query = query.Where("created_at < ?", filter.To.AddDate(0, 0, 1))
+		}
+		return query
+	}
+
+	var summary struct {
+		Total      int64
+		Completed  int64
+		Reading    int64
+		ToRead     int64
+		Bookmarked int64
+		AvgRating  *float64
+	}
+	err := resources().Select(`COUNT(*) AS total,
+		COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+		COUNT(*) FILTER (WHERE status = 'reading') AS reading,
+		COUNT(*) FILTER (WHERE status = 'to-read') AS to_read,
+		COUNT(*) FILTER (WHERE status = 'bookmarked') AS bookmarked,
+		AVG(rating) AS avg_rating`).Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	stats := ResourceStats{
+		Total:      summary.Total,
+		Completed:  summary.Completed,
+		Reading:    summary.Reading,
+		ToRead:     summary.ToRead,
+		Bookmarked: summary.Bookmarked,
+		AvgRating:  summary.AvgRating,
+	}
+
+	type breakdownRow struct {
+		Key   string
+		Count int64
+	}
+	breakdown := func(column string) (map[string]int64, error) {
+		var rows []breakdownRow
+		if err := resources().Select(column + " AS key, COUNT(*) AS count").Group(column).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		counts := make(map[string]int64, len(rows))
+		for _, row := range rows {
+			counts[row.Key] = row.Count
+		}
+		return counts, nil
+	}
+	if stats.Technologies, err = breakdown("technology"); err != nil {
+		return nil, err
+	}
+	if stats.Types, err = breakdown("type"); err != nil {
+		return nil, err
+	}
+
+	var sessions struct {
+		Count   int64
+		Minutes int64
+	}
+	sessionQuery := db.Model(&models.Session{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(learning_sessions.duration_minutes), 0) AS minutes").
+		Joins("JOIN learning_resources ON learning_resources.id = learning_sessions.resource_id").
+		Where("learning_resources.workspace_id = ? AND learning_resources.deleted_at IS NULL", workspaceID).
+		Where("learning_sessions.session_date BETWEEN ? AND ?", filter.SessionFrom.Format("2006-01-02"), filter.SessionTo.Format("2006-01-02"))
+	if filter.Technology != "" {
+		sessionQuery = sessionQuery.Where("learning_resources.technology = ?", filter.Technology)
+	}
+	if err := sessionQuery.Scan(&sessions).Error; err != nil {
+		return nil, err
+	}
+	stats.SessionCount, stats.SessionMinutes = sessions.Count, sessions.Minutes
+
+	return &stats, nil
+}
@@ -0,0 +1,665 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"diary-backend/internal/history"
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormTaskRepository stores tasks in Postgres
+type GormTaskRepository struct {
+	db *gorm.DB
+}
+
+// NewGormTaskRepository returns a task repository using db
+func NewGormTaskRepository(db *gorm.DB) *GormTaskRepository {
+	return &GormTaskRepository{db: db}
+}
+
+// notFound maps GORM's missing record error to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *GormTaskRepository) List(ctx context.Context, workspaceID uuid.UUID, filter TaskFilter) ([]models.Task, Page, error) {
+	order, sort, err := filter.sortKeys(taskSortKeys, "createdAt")
+	if err != nil {
+		return nil, Page{}, err
+	}
+	query, err := r.filtered(r.db.WithContext(ctx), workspaceID, filter)
+	if err != nil {
+		return nil, Page{}, err
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, Page{}, err
+	}
+	return gormPage(query, "tasks", order, sort, filter.ListOptions, total, func(task models.Task) uuid.UUID { return task.ID })
+}
+
+func (r *GormTaskRepository) Counts(ctx context.Context, workspaceID uuid.UUID, filters []TaskFilter) ([]*int64, error) {
+	return gormCounts(r.db.WithContext(ctx), filters, func(filter TaskFilter) (*gorm.DB, error) {
+		if _, _, err := filter.sortKeys(taskSortKeys, "createdAt"); err != nil {
+			return nil, err
+		}
+		return r.filtered(r.db, workspaceID, filter)
+	})
+}
+
+// filtered builds the query for the tasks of the workspace a filter matches
+func (r *GormTaskRepository) filtered(db *gorm.DB, workspaceID uuid.UUID, filter TaskFilter) (*gorm.DB, error) {
+	conditions, err := taskFilterSpec(nil).compile(filter.Query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	query := db.Model(&models.Task{}).Where("workspace_id = ?", workspaceID)
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.Priority != "" {
+		query = query.Where("priority = ?", filter.Priority)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Completed != nil {
+		query = query.Where("completed = ?", *filter.Completed)
+	}
+	if filter.Blocked != nil {
+		if *filter.Blocked {
+			query = query.Where(BlockedTaskSQL)
+		} else {
+			query = query.Where("NOT " + BlockedTaskSQL)
+		}
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("project_id = ?", *filter.ProjectID)
+	}
+	from, to, openOnly := dueWindow(filter.DateFilter, time.Now())
+	if from != "" {
+		query = query.Where("due_date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("due_date <= ?", to)
+	}
+	if openOnly {
+		query = query.Where("completed = false")
+	}
+	return applyConditions(filter.ListOptions.apply(query), conditions).Session(&gorm.Session{}), nil
+}
+
+func (r *GormTaskRepository) Get(ctx context.Context, workspaceID, id uuid.UUID) (*models.Task, error) {
+	var task models.Task
+	if err := r.db.WithContext(ctx).First(&task, "id = ? AND workspace_id = ?", id, workspaceID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &task, nil
+}
+
+func (r *GormTaskRepository) Highlights(ctx context.Context, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error) {
+	return highlights(r.db.WithContext(ctx), "tasks", "coalesce(description, '')", workspaceID, search, ids)
+}
+
+func (r *GormTaskRepository) Subtasks(ctx context.Context, id uuid.UUID) ([]models.Task, error) {
+	var subtasks []models.Task
+	err := r.db.WithContext(ctx).Where("parent_id = ?", id).Order("due_date ASC, created_at ASC").Find(&subtasks).Error
+	return subtasks, err
+}
+
+func (r *GormTaskRepository) Checklist(ctx context.Context, id uuid.UUID) ([]models.ChecklistItem, error) {
+	var checklist []models.ChecklistItem
+	err := r.db.WithContext(ctx).Where("task_id = ?", id).Order("position ASC, created_at ASC").Find(&checklist).Error
+	return checklist, err
+}
+
+func (r *GormTaskRepository) Project(ctx context.Context, workspaceID, projectID uuid.UUID) (*models.Project, error) {
+	var project models.Project
+	if err := r.db.WithContext(ctx).First(&project, "id = ? AND workspace_id = ?", projectID, workspaceID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &project, nil
+}
+
+func (r *GormTaskRepository) OpenBlockers(ctx context.Context, id uuid.UUID) ([]models.Task, error) {
+	var blockers []models.Task
+	err := r.db.WithContext(ctx).Model(&models.Task{}).
+		Joins("JOIN task_dependencies td ON td.depends_on_id = tasks.id").
+		Where("td.task_id = ? AND tasks.completed = false AND tasks.status != 'cancelled'", id).
+		Find(&blockers).Error
+	return blockers, err
+}
+
+func (r *GormTaskRepository) CountOpenDescendants(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Task{}).
+		Where("id IN ("+DescendantIDsSQL+")", id).
+		Where("completed = false AND status != 'cancelled'").
+		Count(&count).Error
+	return count, err
+}
+
+func (r *GormTaskRepository) ChecklistItem(ctx context.Context, taskID, id uuid.UUID) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	if err := r.db.WithContext(ctx).First(&item, "id = ? AND task_id = ?", id, taskID).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &item, nil
+}
+
+func (r *GormTaskRepository) Dependencies(ctx context.Context, id uuid.UUID) (blockedBy, blocking []models.Task, err error) {
+	db := r.db.WithContext(ctx)
+	err = db.Model(&models.Task{}).
+		Joins("JOIN task_dependencies td ON td.depends_on_id = tasks.id").
+		Where("td.task_id = ?", id).
+		Order("tasks.due_date ASC").
+		Find(&blockedBy).Error
+	if err != nil {
+		return nil, nil, err
+	}
+	err = db.Model(&models.Task{}).
+		Joins("JOIN task_dependencies td ON td.task_id = tasks.id").
+		Where("td.depends_on_id = ?", id).
+		Order("tasks.due_date ASC").
+		Find(&blocking).Error
+	return blockedBy, blocking, err
+}
+
+func (r *GormTaskRepository) ProjectGraph(ctx context.Context, workspaceID, projectID uuid.UUID) ([]models.Task, []models.TaskDependency, error) {
+	db := r.db.WithContext(ctx)
+	var tasks []models.Task
+	if err := db.Where("project_id = ? AND workspace_id = ?", projectID, workspaceID).Order("due_date ASC, created_at ASC").Find(&tasks).Error; err != nil {
+		return nil, nil, err
+	}
+
+	// Only edges between tasks of this project are part of its graph
+	var edges []models.TaskDependency
+	err := db.Model(&models.TaskDependency{}).
+		Joins("JOIN tasks t ON t.id = task_dependencies.task_id").
+		Joins("JOIN tasks d ON d.id = task_dependencies.depends_on_id").
+		Where("t.project_id = ? AND d.project_id = ?", projectID, projectID).
+		Where("t.deleted_at IS NULL AND d.deleted_at IS NULL").
+		Find(&edges).Error
+	return tasks, edges, err
+}
+
+func (r *GormTaskRepository) Versions(ctx context.Context, id uuid.UUID) ([]models.TaskVersion, error) {
+	var versions []models.TaskVersion
+	err := r.db.WithContext(ctx).Where("task_id = ?", id).Order("version ASC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *GormTaskRepository) Version(ctx context.Context, id uuid.UUID, n int) (*models.TaskVersion, error) {
+	var version models.TaskVersion
+	if err := r.db.WithContext(ctx).First(&version, "task_id = ? AND version = ?", id, n).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &version, nil
+}
+
+func (r *GormTaskRepository) History(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error) {
+	return gormHistory(r.db.WithContext(ctx), workspaceID, models.EntityTask, id, limit, offset)
+}
+
+func (r *GormTaskRepository) Trash(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]models.Task, int64, error) {
+	return gormTrash[models.Task](r.db.WithContext(ctx), workspaceID, limit, offset)
+}
+
+func (r *GormTaskRepository) Create(ctx context.Context, actor Actor, task *models.Task) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntityTask, task.ID, nil, task)
+	})
+}
+
+// columns returns the update as the column values to write
+func (u TaskUpdate) columns() map[string]interface{} {
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if u.Title != nil {
+		updates["title"] = *u.Title
+	}
+	if u.Description != nil {
+		updates["description"] = *u.Description
+	}
+	if u.Completed != nil {
+		updates["completed"] = *u.Completed
+	}
+	if u.DueDate != nil {
+		updates["due_date"] = *u.DueDate
+	}
+	if u.Priority != nil {
+		updates["priority"] = *u.Priority
+	}
+	if u.Category != nil {
+		updates["category"] = *u.Category
+	}
+	if u.Status != nil {
+		updates["status"] = *u.Status
+	}
+	if u.ProjectID != nil {
+		updates["project_id"] = *u.ProjectID
+	}
+	if u.Tags != nil {
+		updates["tags"] = pq.StringArray(*u.Tags)
+	}
+	if u.ClearRecurrence {
+		updates["recurrence_rule"] = nil
+	} else if u.Recurrence != nil {
+		updates["recurrence_rule"] = *u.Recurrence
+		updates["series_id"] = u.SeriesID
+		updates["series_start"] = u.SeriesStart
+	}
+	return updates
+}
+
+func (r *GormTaskRepository) Update(ctx context.Context, actor Actor, id uuid.UUID, update TaskUpdate) (*models.Task, *models.Task, error) {
+	var task models.Task
+	var next *models.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Task
+		if err := tx.First(&before, "id = ? AND workspace_id = ?", id, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Model(&models.Task{}).Where("id = ?", id).Updates(update.columns()).Error; err != nil {
+			return err
+		}
+		if update.CompleteDescendants {
+			if err := completeDescendants(tx, actor, id); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.First(&task, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := history.SaveTaskVersion(tx, actor.UserID, before, task); err != nil {
+			return err
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityTask, task.ID, before, task); err != nil {
+			return err
+		}
+
+		if update.NextOccurrence == nil {
+			return nil
+		}
+		occurrence, err := update.NextOccurrence(task)
+		if err != nil || occurrence == nil {
+			return err
+		}
+		created, err := createOccurrence(tx, actor, occurrence)
+		if created {
+			next = occurrence
+		}
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &task, next, nil
+}
+
+func (r *GormTaskRepository) Revert(ctx context.Context, actor Actor, id uuid.UUID, snapshot models.TaskSnapshot) (*models.Task, error) {
+	updates := map[string]interface{}{
+		"title":       snapshot.Title,
+		"description": snapshot.Description,
+		"completed":   snapshot.Completed,
+		"due_date":    snapshot.DueDate,
+		"priority":    snapshot.Priority,
+		"category":    snapshot.Category,
+		"status":      snapshot.Status,
+		"project_id":  snapshot.ProjectID,
+		"tags":        pq.StringArray(snapshot.Tags),
+		"updated_at":  time.Now(),
+	}
+
+	var task models.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.Task
+		if err := tx.First(&before, "id = ? AND workspace_id = ?", id, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Model(&models.Task{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&task, "id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := history.SaveTaskVersion(tx, actor.UserID, before, task); err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityTask, task.ID, before, task)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// completeDescendants completes every open subtask below a task and ticks
+// off the checklists of the whole tree
+func completeDescendants(tx *gorm.DB, actor Actor, taskID uuid.UUID) error {
+	var open []models.Task
+	err := tx.Where("id IN ("+DescendantIDsSQL+")", taskID).
+		Where("completed = false AND status != 'cancelled'").
+		Find(&open).Error
+	if err != nil {
+		return err
+	}
+	var unticked []models.ChecklistItem
+	err = tx.Where("task_id = ? OR task_id IN ("+DescendantIDsSQL+")", taskID, taskID).
+		Where("done = false").
+		Find(&unticked).Error
+	if err != nil {
+		return err
+	}
+
+	for _, task := range open {
+		completed := task
+		completed.Completed, completed.Status = true, "completed"
+		if err := tx.Model(&models.Task{}).Where("id = ?", task.ID).Updates(map[string]interface{}{"completed": true, "status": "completed"}).Error; err != nil {
+			return err
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityTask, task.ID, task, completed); err != nil {
+			return err
+		}
+	}
+	for _, item := range unticked {
+		ticked := item
+		ticked.Done = true
+		if err := tx.Model(&models.ChecklistItem{}).Where("id = ?", item.ID).Update("done", true).Error; err != nil {
+			return err
+		}
+		if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityChecklistItem, item.ID, item, ticked); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormTaskRepository) Delete(ctx context.Context, actor Actor, id uuid.UUID) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task models.Task
+		if err := tx.First(&task, "id = ? AND workspace_id = ?", id, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+		var removed []models.Task
+		if err := tx.Where("id = ? OR id IN ("+DescendantIDsSQL+")", id, id).Find(&removed).Error; err != nil {
+			return err
+		}
+
+		// One statement gives the task and its subtasks the same deleted_at,
+		// which is how RestoreTask finds them again
+		result := tx.Where("id = ? OR id IN ("+DescendantIDsSQL+")", id, id).Delete(&models.Task{})
+		if result.Error != nil {
+			return result.Error
+		}
+		deleted = result.RowsAffected
+
+		for _, task := range removed {
+			if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionDelete, models.EntityTask, task.ID, task, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+func (r *GormTaskRepository) Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.Task, int64, error) {
+	var task models.Task
+	var restored []models.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("workspace_id = ? AND deleted_at IS NOT NULL", actor.WorkspaceID).First(&task, "id = ?", id).Error
+		if err != nil {
+			return notFound(err)
+		}
+		if task.ParentID != nil {
+			var parent models.Task
+			if err := tx.Unscoped().First(&parent, "id = ?", *task.ParentID).Error; err == nil && parent.DeletedAt.Valid {
+				return ErrParentInTrash
+			}
+		}
+
+		// The subtasks deleted along with the task share its deleted_at
+		batch := tx.Unscoped().
+			Where("id = ? OR id IN ("+DescendantIDsSQL+")", id, id).
+			Where("deleted_at = ?", task.DeletedAt.Time).
+			Session(&gorm.Session{})
+		if err := batch.Find(&restored).Error; err != nil {
+			return err
+		}
+		if err := batch.Model(&models.Task{}).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		for _, task := range restored {
+			if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionRestore, models.EntityTask, task.ID, nil, nil); err != nil {
+				return err
+			}
+		}
+		return tx.First(&task, "id = ?", id).Error
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &task, int64(len(restored)), nil
+}
+
+func (r *GormTaskRepository) Purge(ctx context.Context, actor Actor, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Where("workspace_id = ? AND deleted_at IS NOT NULL", actor.WorkspaceID).
+			Delete(&models.Task{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionPurge, models.EntityTask, id, nil, nil)
+	})
+}
+
+func (r *GormTaskRepository) EmptyTrash(ctx context.Context, actor Actor) (int64, error) {
+	var purged []models.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+			Where("workspace_id = ? AND deleted_at IS NOT NULL", actor.WorkspaceID).
+			Delete(&purged).Error
+		if err != nil {
+			return err
+		}
+		for _, task := range purged {
+			if err := history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionPurge, models.EntityTask, task.ID, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return int64(len(purged)), err
+}
+
+func (r *GormTaskRepository) CreateChecklistItem(ctx context.Context, actor Actor, item *models.ChecklistItem, atEnd bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if atEnd {
+			// Locking the task keeps items appended at the same time apart
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.Task{}, "id = ?", item.TaskID).Error; err != nil {
+				return notFound(err)
+			}
+			var last *int
+			if err := tx.Model(&models.ChecklistItem{}).Where("task_id = ?", item.TaskID).Select("MAX(position)").Scan(&last).Error; err != nil {
+				return err
+			}
+			if last != nil {
+				item.Position = *last + 1
+			}
+		}
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntityChecklistItem, item.ID, nil, item)
+	})
+}
+
+// columns returns the update as the column values to write
+func (u ChecklistItemUpdate) columns() map[string]interface{} {
+	updates := map[string]interface{}{"updated_at": time.Now()}
+	if u.Title != nil {
+		updates["title"] = *u.Title
+	}
+	if u.Done != nil {
+		updates["done"] = *u.Done
+	}
+	if u.Position != nil {
+		updates["position"] = *u.Position
+	}
+	return updates
+}
+
+func (r *GormTaskRepository) UpdateChecklistItem(ctx context.Context, actor Actor, id uuid.UUID, update ChecklistItemUpdate) (*models.ChecklistItem, error) {
+	var item models.ChecklistItem
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.ChecklistItem
+		if err := tx.First(&before, "id = ?", id).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Model(&models.ChecklistItem{}).Where("id = ?", id).Updates(update.columns()).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&item, "id = ?", id).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityChecklistItem, item.ID, before, item)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *GormTaskRepository) DeleteChecklistItem(ctx context.Context, actor Actor, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var item models.ChecklistItem
+		if err := tx.First(&item, "id = ?", id).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionDelete, models.EntityChecklistItem, item.ID, item, nil)
+	})
+}
+
+// dependencyChainSQL selects whether a task transitively depends on another
+const dependencyChainSQL = `
+	WITH RECURSIVE chain AS (
+		SELECT depends_on_id FROM task_dependencies WHERE task_id = ?
+		UNION
+		SELECT td.depends_on_id FROM task_dependencies td JOIN chain ON td.task_id = chain.depends_on_id
+	)
+	SELECT EXISTS (SELECT 1 FROM chain WHERE depends_on_id = ?)`
+
+func (r *GormTaskRepository) CreateDependency(ctx context.Context, actor Actor, dependency *models.TaskDependency) error {
+	if dependency.TaskID == dependency.DependsOnID {
+		return ErrSelfDependency
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var blocker models.Task
+		if err := tx.First(&blocker, "id = ? AND workspace_id = ?", dependency.DependsOnID, actor.WorkspaceID).Error; err != nil {
+			return notFound(err)
+		}
+
+		// Serialise link creation so two concurrent requests cannot close a cycle together
+		if err := tx.Exec("LOCK TABLE task_dependencies IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		// The new edge closes a cycle if the blocker already depends on this task
+		var cycle bool
+		if err := tx.Raw(dependencyChainSQL, dependency.DependsOnID, dependency.TaskID).Scan(&cycle).Error; err != nil {
+			return err
+		}
+		if cycle {
+			return ErrDependencyCycle
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(dependency)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		// Dependencies have no ID of their own and are logged against the blocked task
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntityTaskDependency, dependency.TaskID, nil, dependency)
+	})
+}
+
+func (r *GormTaskRepository) DeleteDependency(ctx context.Context, actor Actor, dependency models.TaskDependency) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.TaskDependency{}, "task_id = ? AND depends_on_id = ?", dependency.TaskID, dependency.DependsOnID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionDelete, models.EntityTaskDependency, dependency.TaskID, dependency, nil)
+	})
+}
+
+// createOccurrence stores the next occurrence of a recurring task. It
+// returns false without storing anything when the series already has a task
+// on that due date.
+func createOccurrence(tx *gorm.DB, actor Actor, task *models.Task) (bool, error) {
+	// The series/due date unique index makes generation idempotent if a task is completed twice
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(task)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	return true, history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntityTask, task.ID, nil, task)
+}
+
+func (r *GormTaskRepository) Stats(ctx context.Context, workspaceID uuid.UUID, topLevel bool) (*TaskStats, error) {
+	tasks := func() *gorm.DB {
+		query := r.db.WithContext(ctx).Model(&models.Task{}).Where("workspace_id = ?", workspaceID)
+		if topLevel {
+			query = query.Where("parent_id IS NULL")
+		}
+		return query
+	}
+
+	today := time.Now().Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	var stats TaskStats
+	counts := []struct {
+		into  *int64
+		query *gorm.DB
+	}{
+		{&stats.Total, tasks()},
+		{&stats.Today, tasks().Where("due_date = ?", today)},
+		{&stats.TodayCompleted, tasks().Where("due_date = ? AND completed = true", today)},
+		{&stats.InProgress, tasks().Where("completed = false AND status != 'cancelled'")},
+		{&stats.Completed, tasks().Where("completed = true")},
+		{&stats.Overdue, tasks().Where("due_date <= ? AND completed = false", yesterday)},
+	}
+	for _, count := range counts {
+		if err := count.query.Count(count.into).Error; err != nil {
+			return nil, fmt.Errorf("counting tasks: %w", err)
+		}
+	}
+	return &stats, nil
+}
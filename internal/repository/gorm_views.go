@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+
+	"diary-backend/internal/history"
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GormViewRepository stores saved views in Postgres
+type GormViewRepository struct {
+	db *gorm.DB
+}
+
+// NewGormViewRepository returns a view repository using db
+func NewGormViewRepository(db *gorm.DB) *GormViewRepository {
+	return &GormViewRepository{db: db}
+}
+
+// visibleViews builds the query for the views the actor sees
+func visibleViews(db *gorm.DB, actor Actor) *gorm.DB {
+	return db.Where("workspace_id = ? AND (user_id = ? OR shared)", actor.WorkspaceID, actor.UserID)
+}
+
+func (r *GormViewRepository) List(ctx context.Context, actor Actor, entityType string) ([]models.SavedView, error) {
+	query := visibleViews(r.db.WithContext(ctx), actor)
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	views := []models.SavedView{}
+	if err := query.Order("name ASC, created_at ASC").Find(&views).Error; err != nil {
+		return nil, err
+	}
+	return views, nil
+}
+
+func (r *GormViewRepository) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.SavedView, error) {
+	var view models.SavedView
+	if err := visibleViews(r.db.WithContext(ctx), actor).First(&view, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &view, nil
+}
+
+func (r *GormViewRepository) Create(ctx context.Context, actor Actor, view *models.SavedView) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(view).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionCreate, models.EntityView, view.ID, nil, view)
+	})
+}
+
+func (r *GormViewRepository) Update(ctx context.Context, actor Actor, view *models.SavedView) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var before models.SavedView
+		if err := visibleViews(tx, actor).First(&before, "id = ?", view.ID).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Save(view).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionUpdate, models.EntityView, view.ID, before, view)
+	})
+}
+
+func (r *GormViewRepository) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var view models.SavedView
+		if err := visibleViews(tx, actor).First(&view, "id = ?", id).Error; err != nil {
+			return notFound(err)
+		}
+		if err := tx.Delete(&view).Error; err != nil {
+			return err
+		}
+		return history.Record(tx, actor.WorkspaceID, actor.UserID, models.ActionDelete, models.EntityView, view.ID, view, nil)
+	})
+}
@@ -0,0 +1,494 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MemoryResourceRepository keeps learning resources and their sessions in
+// memory. It keeps earlier notes but no audit trail or goal progress. The
+// technology registry, which other handlers manage, is added with
+// AddTechnology.
+type MemoryResourceRepository struct {
+	mu           sync.Mutex
+	resources    map[uuid.UUID]models.Resource
+	sessions     []models.Session
+	noteVersions map[uuid.UUID][]models.ResourceNoteVersion
+	technologies []models.Technology
+}
+
+// NewMemoryResourceRepository returns an empty in-memory resource repository
+func NewMemoryResourceRepository() *MemoryResourceRepository {
+	return &MemoryResourceRepository{
+		resources:    map[uuid.UUID]models.Resource{},
+		noteVersions: map[uuid.UUID][]models.ResourceNoteVersion{},
+	}
+}
+
+// AddTechnology registers a technology resources are normalized to
+func (r *MemoryResourceRepository) AddTechnology(technology models.Technology) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.technologies = append(r.technologies, technology)
+}
+
+// live reports whether a resource exists in the workspace and is not in the trash
+func (r *MemoryResourceRepository) live(workspaceID, id uuid.UUID) (models.Resource, bool) {
+	resource, ok := r.resources[id]
+	return resource, ok && resource.WorkspaceID == workspaceID && !resource.DeletedAt.Valid
+}
+
+func (r *MemoryResourceRepository) List(ctx context.Context, workspaceID uuid.UUID, filter ResourceFilter) ([]models.Resource, Page, error) {
+	_, order, err := filter.sortKeys(resourceSortKeys, "created_at")
+	if err != nil {
+		return nil, Page{}, err
+	}
+	var rank func(models.Resource) float64
+	if filter.rankedSearch() {
+		rank = func(resource models.Resource) float64 { return filter.searchRank(resourceSearchFields(resource)...) }
+	}
+	conditions, err := resourceFilterSpec.compile(filter.Query, time.Now())
+	if err != nil {
+		return nil, Page{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Every resource of the workspace in list order, so that cursors of
+	// resources filtered out or deleted since can still be located
+	ordered := []models.Resource{}
+	for _, resource := range r.resources {
+		if resource.WorkspaceID == workspaceID {
+			ordered = append(ordered, resource)
+		}
+	}
+	memorySort(ordered, filter.sortFields("created_at"), rank, lessResource, func(resource models.Resource) uuid.UUID { return resource.ID })
+
+	matches := []models.Resource{}
+	for _, resource := range ordered {
+		switch {
+		case resource.DeletedAt.Valid,
+			filter.Technology != "" && resource.Technology != filter.Technology,
+			filter.Type != "" && resource.Type != filter.Type,
+			filter.Status != "" && resource.Status != filter.Status,
+			filter.Priority != "" && resource.Priority != filter.Priority,
+			filter.RatingMin != nil && (resource.Rating == nil || *resource.Rating < *filter.RatingMin),
+			filter.RatingMax != nil && (resource.Rating == nil || *resource.Rating > *filter.RatingMax),
+			filter.ProgressMin != nil && intOrZero(resource.Progress) < *filter.ProgressMin,
+			filter.ProgressMax != nil && intOrZero(resource.Progress) > *filter.ProgressMax,
+			len(filter.EstimatedTime) > 0 && !slices.ContainsFunc(filter.EstimatedTime, func(name string) bool {
+				return inEstimateBucket(name, resource.EstimatedTime)
+			}),
+			filter.CreatedFrom != nil && resource.CreatedAt.Before(*filter.CreatedFrom),
+			filter.CreatedTo != nil && !resource.CreatedAt.Before(filter.CreatedTo.AddDate(0, 0, 1)),
+			filter.CompletedFrom != nil && (resource.CompletedAt == nil || resource.CompletedAt.Before(*filter.CompletedFrom)),
+			filter.CompletedTo != nil && (resource.CompletedAt == nil || !resource.CompletedAt.Before(filter.CompletedTo.AddDate(0, 0, 1))),
+			filter.searchRank(resourceSearchFields(resource)...) == 0,
+			!filter.matchesTags(resource.Tags),
+			!matchesConditions(resource, conditions):
+			continue
+		}
+		matches = append(matches, resource)
+	}
+
+	return memoryPage(ordered, matches, order, filter.ListOptions, func(resource models.Resource) uuid.UUID { return resource.ID })
+}
+
+// lessResource compares two resources by a sort field the way their sort
+// keys do
+func lessResource(field string, a, b models.Resource) bool {
+	switch field {
+	case "title":
+		return a.Title < b.Title
+	case "technology":
+		return a.Technology < b.Technology
+	case "priority":
+		return priorityRank[a.Priority] < priorityRank[b.Priority]
+	case "rating":
+		return intOrZero(a.Rating) < intOrZero(b.Rating)
+	case "progress":
+		return intOrZero(a.Progress) < intOrZero(b.Progress)
+	case "estimated_time":
+		return intOrZero(a.EstimatedTime) < intOrZero(b.EstimatedTime)
+	case "created_at":
+		return a.CreatedAt.Before(b.CreatedAt)
+	case "updated_at":
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	case "completed_at":
+		if a.CompletedAt == nil || b.CompletedAt == nil {
+			return a.CompletedAt != nil && b.CompletedAt == nil
+		}
+		return a.CompletedAt.Before(*b.CompletedAt)
+	}
+	return false
+}
+
+// intOrZero is the value of an optional number, 0 when unset
+func intOrZero(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+// resourceSearchFields are the fields of a resource searched, in the order of
+// their weight in the learning_resources search vector
+func resourceSearchFields(resource models.Resource) []string {
+	return []string{resource.Title, strings.Join(resource.Tags, " "), resource.Description, resource.Notes}
+}
+
+func (r *MemoryResourceRepository) Highlights(ctx context.Context, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[uuid.UUID]Highlight, len(ids))
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return result, nil
+	}
+	for _, id := range ids {
+		resource, ok := r.live(workspaceID, id)
+		if !ok {
+			continue
+		}
+		result[id] = Highlight{
+			Title:   markTerms(resource.Title, terms),
+			Snippet: snippet(resource.Description+"\n"+resource.Notes, terms),
+			Rank:    textMatch(terms, resourceSearchFields(resource)...),
+		}
+	}
+	return result, nil
+}
+
+func (r *MemoryResourceRepository) Counts(ctx context.Context, workspaceID uuid.UUID, filters []ResourceFilter) ([]*int64, error) {
+	return memoryCounts(filters, func(filter ResourceFilter) (Page, error) {
+		filter.Limit, filter.Offset, filter.After, filter.Before = 0, 0, "", ""
+		_, page, err := r.List(ctx, workspaceID, filter)
+		return page, err
+	})
+}
+
+func (r *MemoryResourceRepository) Get(ctx context.Context, workspaceID, id uuid.UUID) (*models.Resource, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resource, ok := r.live(workspaceID, id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &resource, nil
+}
+
+func (r *MemoryResourceRepository) NormalizeTechnology(ctx context.Context, workspaceID uuid.UUID, value string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	trimmed := strings.Join(strings.Fields(value), " ")
+	key := TechnologyKey(trimmed)
+	for _, technology := range r.technologies {
+		if technology.WorkspaceID != workspaceID {
+			continue
+		}
+		if TechnologyKey(technology.Name) == key || technology.Slug == key {
+			return technology.Name, nil
+		}
+		for _, alias := range technology.Aliases {
+			if alias == key {
+				return technology.Name, nil
+			}
+		}
+	}
+	return trimmed, nil
+}
+
+func (r *MemoryResourceRepository) NoteVersions(ctx context.Context, id uuid.UUID) ([]models.ResourceNoteVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := append([]models.ResourceNoteVersion{}, r.noteVersions[id]...)
+	slices.Reverse(versions)
+	return versions, nil
+}
+
+func (r *MemoryResourceRepository) NoteVersion(ctx context.Context, id uuid.UUID, n int) (*models.ResourceNoteVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := r.noteVersions[id]
+	if n < 1 || n > len(versions) {
+		return nil, ErrNotFound
+	}
+	version := versions[n-1]
+	return &version, nil
+}
+
+func (r *MemoryResourceRepository) History(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error) {
+	return []models.ActivityEvent{}, 0, nil
+}
+
+func (r *MemoryResourceRepository) Trash(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]models.Resource, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	trash := []models.Resource{}
+	for _, resource := range r.resources {
+		if resource.WorkspaceID == workspaceID && resource.DeletedAt.Valid {
+			trash = append(trash, resource)
+		}
+	}
+	sort.Slice(trash, func(i, j int) bool {
+		if !trash[i].DeletedAt.Time.Equal(trash[j].DeletedAt.Time) {
+			return trash[i].DeletedAt.Time.After(trash[j].DeletedAt.Time)
+		}
+		return trash[i].CreatedAt.After(trash[j].CreatedAt)
+	})
+	return pageOf(trash, limit, offset), int64(len(trash)), nil
+}
+
+func (r *MemoryResourceRepository) Sessions(ctx context.Context, workspaceID uuid.UUID, filter SessionFilter) ([]models.Session, SessionTotals, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var totals SessionTotals
+	sessions := []models.Session{}
+	for _, session := range r.sessions {
+		resource, ok := r.live(workspaceID, session.ResourceID)
+		date := session.SessionDate.Format("2006-01-02")
+		switch {
+		case !ok,
+			filter.ResourceID != nil && session.ResourceID != *filter.ResourceID,
+			filter.Technology != "" && resource.Technology != filter.Technology,
+			filter.From != nil && date < filter.From.Format("2006-01-02"),
+			filter.To != nil && date > filter.To.Format("2006-01-02"):
+			continue
+		}
+		sessions = append(sessions, session)
+		totals.Count++
+		totals.Minutes += int64(session.DurationMinutes)
+	}
+	sort.SliceStable(sessions, func(i, j int) bool {
+		a, b := sessions[i].SessionDate.Format("2006-01-02"), sessions[j].SessionDate.Format("2006-01-02")
+		if a != b {
+			return a > b
+		}
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return pageOf(sessions, filter.Limit, filter.Offset), totals, nil
+}
+
+// session returns a session of a live resource of the workspace and where it is
+func (r *MemoryResourceRepository) session(workspaceID, id uuid.UUID) (int, bool) {
+	for i, session := range r.sessions {
+		if _, ok := r.live(workspaceID, session.ResourceID); ok && session.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (r *MemoryResourceRepository) Session(ctx context.Context, workspaceID, resourceID, id uuid.UUID) (*models.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.session(workspaceID, id)
+	if !ok || r.sessions[i].ResourceID != resourceID {
+		return nil, ErrNotFound
+	}
+	session := r.sessions[i]
+	return &session, nil
+}
+
+func (r *MemoryResourceRepository) Create(ctx context.Context, actor Actor, resource *models.Resource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if resource.ID == uuid.Nil {
+		resource.ID = uuid.New()
+	}
+	now := time.Now()
+	resource.CreatedAt, resource.UpdatedAt = now, now
+	r.resources[resource.ID] = *resource
+	return nil
+}
+
+func (r *MemoryResourceRepository) Update(ctx context.Context, actor Actor, resource *models.Resource) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before, ok := r.live(actor.WorkspaceID, resource.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	// Same as the set_completed_at trigger
+	if resource.Status == "completed" && before.Status != "completed" {
+		now, progress := time.Now(), 100
+		resource.CompletedAt, resource.Progress = &now, &progress
+	} else if resource.Status != "completed" {
+		resource.CompletedAt = nil
+	}
+	resource.UpdatedAt = time.Now()
+	if resource.Notes != before.Notes {
+		versions := r.noteVersions[resource.ID]
+		r.noteVersions[resource.ID] = append(versions, models.ResourceNoteVersion{
+			ID:         uuid.New(),
+			ResourceID: resource.ID,
+			Version:    len(versions) + 1,
+			Notes:      before.Notes,
+			CreatedBy:  &actor.UserID,
+			CreatedAt:  time.Now(),
+		})
+	}
+	r.resources[resource.ID] = *resource
+	return nil
+}
+
+func (r *MemoryResourceRepository) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	resource, ok := r.live(actor.WorkspaceID, id)
+	if !ok {
+		return ErrNotFound
+	}
+	resource.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.resources[id] = resource
+	return nil
+}
+
+func (r *MemoryResourceRepository) Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.Resource, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resource, ok := r.resources[id]
+	if !ok || resource.WorkspaceID != actor.WorkspaceID || !resource.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	resource.DeletedAt = gorm.DeletedAt{}
+	r.resources[id] = resource
+	return &resource, nil
+}
+
+// remove permanently deletes a resource with its sessions and earlier notes
+func (r *MemoryResourceRepository) remove(id uuid.UUID) {
+	delete(r.resources, id)
+	delete(r.noteVersions, id)
+	r.sessions = slices.DeleteFunc(r.sessions, func(session models.Session) bool { return session.ResourceID == id })
+}
+
+func (r *MemoryResourceRepository) Purge(ctx context.Context, actor Actor, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resource, ok := r.resources[id]
+	if !ok || resource.WorkspaceID != actor.WorkspaceID || !resource.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	r.remove(id)
+	return nil
+}
+
+func (r *MemoryResourceRepository) EmptyTrash(ctx context.Context, actor Actor) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var purged int64
+	for id, resource := range r.resources {
+		if resource.WorkspaceID == actor.WorkspaceID && resource.DeletedAt.Valid {
+			r.remove(id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (r *MemoryResourceRepository) CreateSession(ctx context.Context, actor Actor, session *models.Session) (*models.Resource, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	resource, ok := r.live(actor.WorkspaceID, session.ResourceID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	session.CreatedAt = time.Now()
+	r.sessions = append(r.sessions, *session)
+	if resource.Status == "" || resource.Status == "to-read" || resource.Status == "bookmarked" {
+		resource.Status = "reading"
+		r.resources[resource.ID] = resource
+	}
+	return &resource, nil
+}
+
+func (r *MemoryResourceRepository) UpdateSession(ctx context.Context, actor Actor, session *models.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.session(actor.WorkspaceID, session.ID)
+	if !ok {
+		return ErrNotFound
+	}
+	r.sessions[i] = *session
+	return nil
+}
+
+func (r *MemoryResourceRepository) DeleteSession(ctx context.Context, actor Actor, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i, ok := r.session(actor.WorkspaceID, id)
+	if !ok {
+		return ErrNotFound
+	}
+	r.sessions = slices.Delete(r.sessions, i, i+1)
+	return nil
+}
+
+func (r *MemoryResourceRepository) Stats(ctx context.Context, workspaceID uuid.UUID, filter ResourceStatsFilter) (*ResourceStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := ResourceStats{Technologies: map[string]int64{}, Types: map[string]int64{}}
+	var ratingSum, rated int
+	for _, resource := range r.resources {
+		switch {
+		case resource.WorkspaceID != workspaceID || resource.DeletedAt.Valid,
+			filter.Technology != "" && resource.Technology != filter.Technology,
+			filter.From != nil && resource.CreatedAt.Before(*filter.From),
+			filter.To != nil && !resource.CreatedAt.Before(filter.To.AddDate(0, 0, 1)):
+			continue
+		}
+		stats.Total++
+		switch resource.Status {
+		case "completed":
+			stats.Completed++
+		case "reading":
+			stats.Reading++
+		case "to-read":
+			stats.ToRead++
+		case "bookmarked":
+			stats.Bookmarked++
+		}
+		if resource.Rating != nil {
+			ratingSum += *resource.Rating
+			rated++
+		}
+		stats.Technologies[resource.Technology]++
+		stats.Types[resource.Type]++
+	}
+	if rated > 0 {
+		avg := float64(ratingSum) / float64(rated)
+		stats.AvgRating = &avg
+	}
+
+	from, to := filter.SessionFrom.Format("2006-01-02"), filter.SessionTo.Format("2006-01-02")
+	for _, session := range r.sessions {
+		resource, ok := r.live(workspaceID, session.ResourceID)
+		date := session.SessionDate.Format("2006-01-02")
+		if !ok || date < from || date > to || (filter.Technology != "" && resource.Technology != filter.Technology) {
+			continue
+		}
+		stats.SessionCount++
+		stats.SessionMinutes += int64(session.DurationMinutes)
+	}
+	return &stats, nil
+}
@@ -0,0 +1,781 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"diary-backend/internal/history"
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// MemoryTaskRepository keeps tasks in memory. It keeps the versions of tasks
+// but no audit trail. Projects, which other handlers manage, are added with
+// AddProject.
+type MemoryTaskRepository struct {
+	mu           sync.Mutex
+	tasks        map[uuid.UUID]models.Task
+	checklist    map[uuid.UUID]models.ChecklistItem
+	dependencies []models.TaskDependency
+	versions     map[uuid.UUID][]models.TaskVersion
+	projects     map[uuid.UUID]models.Project
+}
+
+// NewMemoryTaskRepository returns an empty in-memory task repository
+func NewMemoryTaskRepository() *MemoryTaskRepository {
+	return &MemoryTaskRepository{
+		tasks:     map[uuid.UUID]models.Task{},
+		checklist: map[uuid.UUID]models.ChecklistItem{},
+		versions:  map[uuid.UUID][]models.TaskVersion{},
+		projects:  map[uuid.UUID]models.Project{},
+	}
+}
+
+// AddProject stores a project tasks can be assigned to
+func (r *MemoryTaskRepository) AddProject(project models.Project) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if project.ID == uuid.Nil {
+		project.ID = uuid.New()
+	}
+	r.projects[project.ID] = project
+}
+
+// live reports whether a task exists and is not in the trash
+func (r *MemoryTaskRepository) live(id uuid.UUID) (models.Task, bool) {
+	task, ok := r.tasks[id]
+	return task, ok && !task.DeletedAt.Valid
+}
+
+// isOpen reports whether a task is neither completed nor cancelled
+func isOpen(task models.Task) bool {
+	return !task.Completed && task.Status != "cancelled"
+}
+
+func (r *MemoryTaskRepository) blocked(id uuid.UUID) bool {
+	for _, dependency := range r.dependencies {
+		if dependency.TaskID != id {
+			continue
+		}
+		if blocker, ok := r.live(dependency.DependsOnID); ok && isOpen(blocker) {
+			return true
+		}
+	}
+	return false
+}
+
+// lessTask compares two tasks by a sort field; unset project IDs sort last
+func lessTask(field string, a, b models.Task) bool {
+	switch field {
+	case "dueDate":
+		return a.DueDate.Before(b.DueDate)
+	case "createdAt", "created":
+		return a.CreatedAt.Before(b.CreatedAt)
+	case "updatedAt":
+		return a.UpdatedAt.Before(b.UpdatedAt)
+	case "projectId":
+		if a.ProjectID == nil || b.ProjectID == nil {
+			return a.ProjectID != nil && b.ProjectID == nil
+		}
+		return a.ProjectID.String() < b.ProjectID.String()
+	case "title":
+		return a.Title < b.Title
+	case "priority":
+		return priorityRank[a.Priority] < priorityRank[b.Priority]
+	case "category":
+		return a.Category < b.Category
+	case "status":
+		return a.Status < b.Status
+	case "completed":
+		return !a.Completed && b.Completed
+	}
+	return false
+}
+
+func (r *MemoryTaskRepository) List(ctx context.Context, workspaceID uuid.UUID, filter TaskFilter) ([]models.Task, Page, error) {
+	_, order, err := filter.sortKeys(taskSortKeys, "createdAt")
+	if err != nil {
+		return nil, Page{}, err
+	}
+	var rank func(models.Task) float64
+	if filter.rankedSearch() {
+		rank = func(task models.Task) float64 { return filter.searchRank(taskSearchFields(task)...) }
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	conditions, err := taskFilterSpec(func(task models.Task) bool { return r.blocked(task.ID) }).compile(filter.Query, time.Now())
+	if err != nil {
+		return nil, Page{}, err
+	}
+
+	// Every task of the workspace in list order, so that cursors of tasks
+	// filtered out or deleted since can still be located
+	ordered := []models.Task{}
+	for _, task := range r.tasks {
+		if task.WorkspaceID == workspaceID {
+			ordered = append(ordered, task)
+		}
+	}
+	memorySort(ordered, filter.sortFields("createdAt"), rank, lessTask, func(task models.Task) uuid.UUID { return task.ID })
+
+	from, to, openOnly := dueWindow(filter.DateFilter, time.Now())
+	matches := []models.Task{}
+	for _, task := range ordered {
+		dueDate := task.DueDate.Format("2006-01-02")
+		switch {
+		case task.DeletedAt.Valid,
+			filter.Category != "" && task.Category != filter.Category,
+			filter.Priority != "" && task.Priority != filter.Priority,
+			filter.Status != "" && task.Status != filter.Status,
+			filter.Completed != nil && task.Completed != *filter.Completed,
+			filter.Blocked != nil && r.blocked(task.ID) != *filter.Blocked,
+			filter.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *filter.ProjectID),
+			from != "" && dueDate < from,
+			to != "" && dueDate > to,
+			openOnly && task.Completed,
+			filter.searchRank(taskSearchFields(task)...) == 0,
+			!filter.matchesTags(task.Tags),
+			!matchesConditions(task, conditions):
+			continue
+		}
+		matches = append(matches, task)
+	}
+
+	return memoryPage(ordered, matches, order, filter.ListOptions, func(task models.Task) uuid.UUID { return task.ID })
+}
+
+// taskSearchFields are the fields of a task searched, in the order of their
+// weight in the tasks search vector
+func taskSearchFields(task models.Task) []string {
+	description := ""
+	if task.Description != nil {
+		description = *task.Description
+	}
+	return []string{task.Title, strings.Join(task.Tags, " "), description}
+}
+
+func (r *MemoryTaskRepository) Highlights(ctx context.Context, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make(map[uuid.UUID]Highlight, len(ids))
+	terms := searchTerms(search)
+	if len(terms) == 0 {
+		return result, nil
+	}
+	for _, id := range ids {
+		task, ok := r.live(id)
+		if !ok || task.WorkspaceID != workspaceID {
+			continue
+		}
+		fields := taskSearchFields(task)
+		result[id] = Highlight{
+			Title:   markTerms(task.Title, terms),
+			Snippet: snippet(fields[2], terms),
+			Rank:    textMatch(terms, fields...),
+		}
+	}
+	return result, nil
+}
+
+func (r *MemoryTaskRepository) Counts(ctx context.Context, workspaceID uuid.UUID, filters []TaskFilter) ([]*int64, error) {
+	return memoryCounts(filters, func(filter TaskFilter) (Page, error) {
+		filter.Limit, filter.Offset, filter.After, filter.Before = 0, 0, "", ""
+		_, page, err := r.List(ctx, workspaceID, filter)
+		return page, err
+	})
+}
+
+func (r *MemoryTaskRepository) Get(ctx context.Context, workspaceID, id uuid.UUID) (*models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.live(id)
+	if !ok || task.WorkspaceID != workspaceID {
+		return nil, ErrNotFound
+	}
+	return &task, nil
+}
+
+func (r *MemoryTaskRepository) Subtasks(ctx context.Context, id uuid.UUID) ([]models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subtasks := []models.Task{}
+	for _, task := range r.tasks {
+		if task.ParentID != nil && *task.ParentID == id && !task.DeletedAt.Valid {
+			subtasks = append(subtasks, task)
+		}
+	}
+	sort.Slice(subtasks, func(i, j int) bool {
+		if !subtasks[i].DueDate.Equal(subtasks[j].DueDate) {
+			return subtasks[i].DueDate.Before(subtasks[j].DueDate)
+		}
+		return subtasks[i].CreatedAt.Before(subtasks[j].CreatedAt)
+	})
+	return subtasks, nil
+}
+
+func (r *MemoryTaskRepository) Checklist(ctx context.Context, id uuid.UUID) ([]models.ChecklistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	checklist := []models.ChecklistItem{}
+	for _, item := range r.checklist {
+		if item.TaskID == id {
+			checklist = append(checklist, item)
+		}
+	}
+	sort.Slice(checklist, func(i, j int) bool {
+		if checklist[i].Position != checklist[j].Position {
+			return checklist[i].Position < checklist[j].Position
+		}
+		return checklist[i].CreatedAt.Before(checklist[j].CreatedAt)
+	})
+	return checklist, nil
+}
+
+func (r *MemoryTaskRepository) Project(ctx context.Context, workspaceID, projectID uuid.UUID) (*models.Project, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	project, ok := r.projects[projectID]
+	if !ok || project.WorkspaceID != workspaceID {
+		return nil, ErrNotFound
+	}
+	return &project, nil
+}
+
+func (r *MemoryTaskRepository) OpenBlockers(ctx context.Context, id uuid.UUID) ([]models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blockers := []models.Task{}
+	for _, dependency := range r.dependencies {
+		if dependency.TaskID != id {
+			continue
+		}
+		if blocker, ok := r.live(dependency.DependsOnID); ok && isOpen(blocker) {
+			blockers = append(blockers, blocker)
+		}
+	}
+	return blockers, nil
+}
+
+func (r *MemoryTaskRepository) CountOpenDescendants(ctx context.Context, id uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, descendantID := range r.descendants(id) {
+		if isOpen(r.tasks[descendantID]) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryTaskRepository) ChecklistItem(ctx context.Context, taskID, id uuid.UUID) (*models.ChecklistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.checklist[id]
+	if !ok || item.TaskID != taskID {
+		return nil, ErrNotFound
+	}
+	return &item, nil
+}
+
+// sortByDueDate orders tasks by due date, then by when they were created
+func sortByDueDate(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if !tasks[i].DueDate.Equal(tasks[j].DueDate) {
+			return tasks[i].DueDate.Before(tasks[j].DueDate)
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
+	})
+}
+
+func (r *MemoryTaskRepository) Dependencies(ctx context.Context, id uuid.UUID) (blockedBy, blocking []models.Task, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	blockedBy, blocking = []models.Task{}, []models.Task{}
+	for _, dependency := range r.dependencies {
+		if task, ok := r.live(dependency.DependsOnID); ok && dependency.TaskID == id {
+			blockedBy = append(blockedBy, task)
+		}
+		if task, ok := r.live(dependency.TaskID); ok && dependency.DependsOnID == id {
+			blocking = append(blocking, task)
+		}
+	}
+	sortByDueDate(blockedBy)
+	sortByDueDate(blocking)
+	return blockedBy, blocking, nil
+}
+
+func (r *MemoryTaskRepository) ProjectGraph(ctx context.Context, workspaceID, projectID uuid.UUID) ([]models.Task, []models.TaskDependency, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	inProject := func(id uuid.UUID) bool {
+		task, ok := r.live(id)
+		return ok && task.WorkspaceID == workspaceID && task.ProjectID != nil && *task.ProjectID == projectID
+	}
+
+	tasks := []models.Task{}
+	for _, task := range r.tasks {
+		if inProject(task.ID) {
+			tasks = append(tasks, task)
+		}
+	}
+	sortByDueDate(tasks)
+
+	edges := []models.TaskDependency{}
+	for _, dependency := range r.dependencies {
+		if inProject(dependency.TaskID) && inProject(dependency.DependsOnID) {
+			edges = append(edges, dependency)
+		}
+	}
+	return tasks, edges, nil
+}
+
+func (r *MemoryTaskRepository) Versions(ctx context.Context, id uuid.UUID) ([]models.TaskVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]models.TaskVersion{}, r.versions[id]...), nil
+}
+
+func (r *MemoryTaskRepository) Version(ctx context.Context, id uuid.UUID, n int) (*models.TaskVersion, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions := r.versions[id]
+	if n < 1 || n > len(versions) {
+		return nil, ErrNotFound
+	}
+	version := versions[n-1]
+	return &version, nil
+}
+
+func (r *MemoryTaskRepository) History(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error) {
+	return []models.ActivityEvent{}, 0, nil
+}
+
+func (r *MemoryTaskRepository) Trash(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]models.Task, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	trash := []models.Task{}
+	for _, task := range r.tasks {
+		if task.WorkspaceID == workspaceID && task.DeletedAt.Valid {
+			trash = append(trash, task)
+		}
+	}
+	sort.Slice(trash, func(i, j int) bool {
+		if !trash[i].DeletedAt.Time.Equal(trash[j].DeletedAt.Time) {
+			return trash[i].DeletedAt.Time.After(trash[j].DeletedAt.Time)
+		}
+		return trash[i].CreatedAt.After(trash[j].CreatedAt)
+	})
+	return pageOf(trash, limit, offset), int64(len(trash)), nil
+}
+
+// saveVersion keeps before as the task's next version when after differs from it
+func (r *MemoryTaskRepository) saveVersion(actor Actor, before, after models.Task) error {
+	changes, err := history.Diff(before, after)
+	if err != nil || len(changes) == 0 {
+		return err
+	}
+	versions := r.versions[before.ID]
+	r.versions[before.ID] = append(versions, models.TaskVersion{
+		ID:        uuid.New(),
+		TaskID:    before.ID,
+		Version:   len(versions) + 1,
+		Snapshot:  models.TaskSnapshot(before),
+		CreatedBy: &actor.UserID,
+		CreatedAt: time.Now(),
+	})
+	return nil
+}
+
+// insert stores a new task, filling in what the database would
+func (r *MemoryTaskRepository) insert(task *models.Task) {
+	if task.ID == uuid.Nil {
+		task.ID = uuid.New()
+	}
+	now := time.Now()
+	task.CreatedAt, task.UpdatedAt = now, now
+	r.tasks[task.ID] = *task
+}
+
+func (r *MemoryTaskRepository) Create(ctx context.Context, actor Actor, task *models.Task) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.insert(task)
+	return nil
+}
+
+// descendants returns the IDs of every live subtask below a task, at any depth
+func (r *MemoryTaskRepository) descendants(id uuid.UUID) []uuid.UUID {
+	var ids []uuid.UUID
+	for _, task := range r.tasks {
+		if task.ParentID != nil && *task.ParentID == id && !task.DeletedAt.Valid {
+			ids = append(ids, task.ID)
+			ids = append(ids, r.descendants(task.ID)...)
+		}
+	}
+	return ids
+}
+
+func (r *MemoryTaskRepository) Update(ctx context.Context, actor Actor, id uuid.UUID, update TaskUpdate) (*models.Task, *models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.live(id)
+	if !ok || task.WorkspaceID != actor.WorkspaceID {
+		return nil, nil, ErrNotFound
+	}
+	before := task
+	if update.Title != nil {
+		task.Title = *update.Title
+	}
+	if update.Description != nil {
+		description := *update.Description
+		task.Description = &description
+	}
+	if update.Completed != nil {
+		task.Completed = *update.Completed
+	}
+	if update.DueDate != nil {
+		task.DueDate = *update.DueDate
+	}
+	if update.Priority != nil {
+		task.Priority = *update.Priority
+	}
+	if update.Category != nil {
+		task.Category = *update.Category
+	}
+	if update.Status != nil {
+		task.Status = *update.Status
+	}
+	if update.ProjectID != nil {
+		projectID := *update.ProjectID
+		task.ProjectID = &projectID
+	}
+	if update.Tags != nil {
+		task.Tags = pq.StringArray(*update.Tags)
+	}
+	if update.ClearRecurrence {
+		task.Recurrence = nil
+	} else if update.Recurrence != nil {
+		rule := *update.Recurrence
+		task.Recurrence, task.SeriesID, task.SeriesStart = &rule, update.SeriesID, update.SeriesStart
+	}
+	task.UpdatedAt = time.Now()
+
+	// Nothing is stored when the next occurrence cannot be built
+	var next *models.Task
+	if update.NextOccurrence != nil {
+		var err error
+		if next, err = update.NextOccurrence(task); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := r.saveVersion(actor, before, task); err != nil {
+		return nil, nil, err
+	}
+	r.tasks[id] = task
+
+	if update.CompleteDescendants {
+		tree := map[uuid.UUID]bool{id: true}
+		for _, descendantID := range r.descendants(id) {
+			tree[descendantID] = true
+			if descendant := r.tasks[descendantID]; isOpen(descendant) {
+				descendant.Completed, descendant.Status = true, "completed"
+				r.tasks[descendantID] = descendant
+			}
+		}
+		for itemID, item := range r.checklist {
+			if tree[item.TaskID] && !item.Done {
+				item.Done = true
+				r.checklist[itemID] = item
+			}
+		}
+	}
+	if next != nil && !r.createOccurrence(next) {
+		next = nil
+	}
+	return &task, next, nil
+}
+
+func (r *MemoryTaskRepository) Delete(ctx context.Context, actor Actor, id uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.live(id)
+	if !ok || task.WorkspaceID != actor.WorkspaceID {
+		return 0, ErrNotFound
+	}
+	deletedAt := gorm.DeletedAt{Time: time.Now(), Valid: true}
+	ids := append([]uuid.UUID{id}, r.descendants(id)...)
+	for _, taskID := range ids {
+		removed := r.tasks[taskID]
+		removed.DeletedAt = deletedAt
+		r.tasks[taskID] = removed
+	}
+	return int64(len(ids)), nil
+}
+
+func (r *MemoryTaskRepository) Revert(ctx context.Context, actor Actor, id uuid.UUID, snapshot models.TaskSnapshot) (*models.Task, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.live(id)
+	if !ok || task.WorkspaceID != actor.WorkspaceID {
+		return nil, ErrNotFound
+	}
+	before := task
+	task.Title, task.Description, task.Completed = snapshot.Title, snapshot.Description, snapshot.Completed
+	task.DueDate, task.Priority, task.Category = snapshot.DueDate, snapshot.Priority, snapshot.Category
+	task.Status, task.ProjectID, task.Tags = snapshot.Status, snapshot.ProjectID, snapshot.Tags
+	task.UpdatedAt = time.Now()
+	if err := r.saveVersion(actor, before, task); err != nil {
+		return nil, err
+	}
+	r.tasks[id] = task
+	return &task, nil
+}
+
+func (r *MemoryTaskRepository) Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.Task, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	task, ok := r.tasks[id]
+	if !ok || task.WorkspaceID != actor.WorkspaceID || !task.DeletedAt.Valid {
+		return nil, 0, ErrNotFound
+	}
+	if task.ParentID != nil {
+		if parent, ok := r.tasks[*task.ParentID]; ok && parent.DeletedAt.Valid {
+			return nil, 0, ErrParentInTrash
+		}
+	}
+
+	// The subtasks deleted along with the task share its deletion time
+	deletedAt := task.DeletedAt.Time
+	var restored int64
+	var restore func(id uuid.UUID)
+	restore = func(id uuid.UUID) {
+		task := r.tasks[id]
+		if task.DeletedAt.Valid && task.DeletedAt.Time.Equal(deletedAt) {
+			task.DeletedAt = gorm.DeletedAt{}
+			r.tasks[id] = task
+			restored++
+		}
+		for _, child := range r.tasks {
+			if child.ParentID != nil && *child.ParentID == id {
+				restore(child.ID)
+			}
+		}
+	}
+	restore(id)
+
+	task = r.tasks[id]
+	return &task, restored, nil
+}
+
+// remove permanently deletes a task with everything that cascades from it
+func (r *MemoryTaskRepository) remove(id uuid.UUID) {
+	delete(r.tasks, id)
+	delete(r.versions, id)
+	for itemID, item := range r.checklist {
+		if item.TaskID == id {
+			delete(r.checklist, itemID)
+		}
+	}
+	kept := r.dependencies[:0]
+	for _, dependency := range r.dependencies {
+		if dependency.TaskID != id && dependency.DependsOnID != id {
+			kept = append(kept, dependency)
+		}
+	}
+	r.dependencies = kept
+	for _, child := range r.tasks {
+		if child.ParentID != nil && *child.ParentID == id {
+			r.remove(child.ID)
+		}
+	}
+}
+
+func (r *MemoryTaskRepository) Purge(ctx context.Context, actor Actor, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	task, ok := r.tasks[id]
+	if !ok || task.WorkspaceID != actor.WorkspaceID || !task.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	r.remove(id)
+	return nil
+}
+
+func (r *MemoryTaskRepository) EmptyTrash(ctx context.Context, actor Actor) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var trash []uuid.UUID
+	for id, task := range r.tasks {
+		if task.WorkspaceID == actor.WorkspaceID && task.DeletedAt.Valid {
+			trash = append(trash, id)
+		}
+	}
+	for _, id := range trash {
+		r.remove(id)
+	}
+	return int64(len(trash)), nil
+}
+
+func (r *MemoryTaskRepository) CreateChecklistItem(ctx context.Context, actor Actor, item *models.ChecklistItem, atEnd bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tasks[item.TaskID]; !ok {
+		return ErrNotFound
+	}
+	if atEnd {
+		last := -1
+		for _, existing := range r.checklist {
+			if existing.TaskID == item.TaskID && existing.Position > last {
+				last = existing.Position
+			}
+		}
+		item.Position = last + 1
+	}
+	if item.ID == uuid.Nil {
+		item.ID = uuid.New()
+	}
+	now := time.Now()
+	item.CreatedAt, item.UpdatedAt = now, now
+	r.checklist[item.ID] = *item
+	return nil
+}
+
+func (r *MemoryTaskRepository) UpdateChecklistItem(ctx context.Context, actor Actor, id uuid.UUID, update ChecklistItemUpdate) (*models.ChecklistItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	item, ok := r.checklist[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if update.Title != nil {
+		item.Title = *update.Title
+	}
+	if update.Done != nil {
+		item.Done = *update.Done
+	}
+	if update.Position != nil {
+		item.Position = *update.Position
+	}
+	item.UpdatedAt = time.Now()
+	r.checklist[id] = item
+	return &item, nil
+}
+
+func (r *MemoryTaskRepository) DeleteChecklistItem(ctx context.Context, actor Actor, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.checklist[id]; !ok {
+		return ErrNotFound
+	}
+	delete(r.checklist, id)
+	return nil
+}
+
+// dependsOn reports whether a task transitively depends on another
+func (r *MemoryTaskRepository) dependsOn(id, other uuid.UUID, seen map[uuid.UUID]bool) bool {
+	if seen[id] {
+		return false
+	}
+	seen[id] = true
+	for _, dependency := range r.dependencies {
+		if dependency.TaskID == id && (dependency.DependsOnID == other || r.dependsOn(dependency.DependsOnID, other, seen)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryTaskRepository) CreateDependency(ctx context.Context, actor Actor, dependency *models.TaskDependency) error {
+	if dependency.TaskID == dependency.DependsOnID {
+		return ErrSelfDependency
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if blocker, ok := r.live(dependency.DependsOnID); !ok || blocker.WorkspaceID != actor.WorkspaceID {
+		return ErrNotFound
+	}
+	if r.dependsOn(dependency.DependsOnID, dependency.TaskID, map[uuid.UUID]bool{}) {
+		return ErrDependencyCycle
+	}
+	for _, existing := range r.dependencies {
+		if existing.TaskID == dependency.TaskID && existing.DependsOnID == dependency.DependsOnID {
+			return nil
+		}
+	}
+	dependency.CreatedAt = time.Now()
+	r.dependencies = append(r.dependencies, *dependency)
+	return nil
+}
+
+func (r *MemoryTaskRepository) DeleteDependency(ctx context.Context, actor Actor, dependency models.TaskDependency) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, existing := range r.dependencies {
+		if existing.TaskID == dependency.TaskID && existing.DependsOnID == dependency.DependsOnID {
+			r.dependencies = append(r.dependencies[:i], r.dependencies[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// createOccurrence stores the next occurrence of a recurring task unless the
+// series already has a task on its due date, and reports whether it did
+func (r *MemoryTaskRepository) createOccurrence(task *models.Task) bool {
+	// Like the series/due date unique index, trashed tasks count too
+	for _, existing := range r.tasks {
+		if existing.SeriesID != nil && task.SeriesID != nil && *existing.SeriesID == *task.SeriesID &&
+			existing.DueDate.Format("2006-01-02") == task.DueDate.Format("2006-01-02") {
+			return false
+		}
+	}
+	r.insert(task)
+	return true
+}
+
+func (r *MemoryTaskRepository) Stats(ctx context.Context, workspaceID uuid.UUID, topLevel bool) (*TaskStats, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	today := time.Now().Format("2006-01-02")
+	yesterday := time.Now().AddDate(0, 0, -1).Format("2006-01-02")
+
+	var stats TaskStats
+	for _, task := range r.tasks {
+		if task.WorkspaceID != workspaceID || task.DeletedAt.Valid || (topLevel && task.ParentID != nil) {
+			continue
+		}
+		dueDate := task.DueDate.Format("2006-01-02")
+		stats.Total++
+		if dueDate == today {
+			stats.Today++
+			if task.Completed {
+				stats.TodayCompleted++
+			}
+		}
+		if isOpen(task) {
+			stats.InProgress++
+		}
+		if task.Completed {
+			stats.Completed++
+		}
+		if strings.Compare(dueDate, yesterday) <= 0 && !task.Completed {
+			stats.Overdue++
+		}
+	}
+	return &stats, nil
+}
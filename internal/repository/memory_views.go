@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// MemoryViewRepository keeps saved views in memory. It does not keep an
+// audit trail.
+type MemoryViewRepository struct {
+	mu    sync.Mutex
+	views map[uuid.UUID]models.SavedView
+}
+
+// NewMemoryViewRepository returns an empty in-memory view repository
+func NewMemoryViewRepository() *MemoryViewRepository {
+	return &MemoryViewRepository{views: map[uuid.UUID]models.SavedView{}}
+}
+
+// visible reports whether a view exists and the actor sees it
+func (r *MemoryViewRepository) visible(actor Actor, id uuid.UUID) (models.SavedView, bool) {
+	view, ok := r.views[id]
+	return view, ok && view.WorkspaceID == actor.WorkspaceID && (view.UserID == actor.UserID || view.Shared)
+}
+
+func (r *MemoryViewRepository) List(ctx context.Context, actor Actor, entityType string) ([]models.SavedView, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	views := []models.SavedView{}
+	for id := range r.views {
+		if view, ok := r.visible(actor, id); ok && (entityType == "" || view.EntityType == entityType) {
+			views = append(views, view)
+		}
+	}
+	slices.SortFunc(views, func(a, b models.SavedView) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), a.CreatedAt.Compare(b.CreatedAt))
+	})
+	return views, nil
+}
+
+func (r *MemoryViewRepository) Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.SavedView, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	view, ok := r.visible(actor, id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &view, nil
+}
+
+func (r *MemoryViewRepository) Create(ctx context.Context, actor Actor, view *models.SavedView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if view.ID == uuid.Nil {
+		view.ID = uuid.New()
+	}
+	now := time.Now()
+	view.CreatedAt, view.UpdatedAt = now, now
+	r.views[view.ID] = *view
+	return nil
+}
+
+func (r *MemoryViewRepository) Update(ctx context.Context, actor Actor, view *models.SavedView) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.visible(actor, view.ID); !ok {
+		return ErrNotFound
+	}
+	view.UpdatedAt = time.Now()
+	r.views[view.ID] = *view
+	return nil
+}
+
+func (r *MemoryViewRepository) Delete(ctx context.Context, actor Actor, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.visible(actor, id); !ok {
+		return ErrNotFound
+	}
+	delete(r.views, id)
+	return nil
+}
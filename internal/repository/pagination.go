@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidCursor is returned for a cursor that cannot be decoded or was
+// made for a list sorted differently
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Page tells where a page is in its list. Cursors are empty at the ends.
+type Page struct {
+	Total      int64  // matches of the filter on every page
+	NextCursor string // continues after the last item of the page
+	PrevCursor string // continues before the first item of the page
+}
+
+// sortKey is the SQL expression a list is ordered by and the type its text
+// form is cast back to when a cursor is resumed
+type sortKey struct {
+	expr string
+	vars []interface{}
+	cast string
+}
+
+// orderKey is a sort key and its direction
+type orderKey struct {
+	sortKey
+	desc bool
+}
+
+// cursor is the position of an item in a sorted list: the values of its sort
+// keys and its ID, which breaks ties. Sort names the sort it was made for.
+type cursor struct {
+	Sort string    `json:"s"`
+	Keys []string  `json:"k,omitempty"`
+	ID   uuid.UUID `json:"id"`
+}
+
+// encode returns the cursor as an opaque URL-safe token
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// decodeCursor reads a token made by cursor.encode for a list sorted by sort
+func decodeCursor(token, sort string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.ID == uuid.Nil {
+		return cursor{}, ErrInvalidCursor
+	}
+	if c.Sort != sort {
+		return cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// direction returns the SQL direction of an order and the operator selecting
+// the rows past a cursor in it
+func direction(desc bool) (string, string) {
+	if desc {
+		return "DESC", "<"
+	}
+	return "ASC", ">"
+}
+
+// sortName identifies a sort in cursors so that a cursor is not resumed in a
+// list ordered another way
+func sortName(field string, desc bool) string {
+	if desc {
+		return field + ":desc"
+	}
+	return field + ":asc"
+}
+
+// pageBounds returns the cursor a page continues from and whether it runs
+// backwards from a Before cursor
+func (o ListOptions) pageBounds(sort string) (*cursor, bool, error) {
+	if o.After != "" && o.Before != "" {
+		return nil, false, ErrInvalidCursor
+	}
+	token, backward := o.After, false
+	if o.Before != "" {
+		token, backward = o.Before, true
+	}
+	if token == "" {
+		return nil, false, nil
+	}
+	c, err := decodeCursor(token, sort)
+	if err != nil {
+		return nil, false, err
+	}
+	return &c, backward, nil
+}
+
+// keyed is an item read together with the text of its sort keys
+type keyed[T any] struct {
+	Item       T `gorm:"embedded"`
+	CursorKeys pq.StringArray
+}
+
+// gormPage reads one page of query ordered by each key in turn and then by
+// ID, in the direction of the last key. With a cursor the page starts next to
+// it, otherwise at the offset. total is the number of matches without paging.
+func gormPage[T any](query *gorm.DB, table string, order []orderKey, sort string, o ListOptions, total int64, id func(T) uuid.UUID) ([]T, Page, error) {
+	at, backward, err := o.pageBounds(sort)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	if at != nil && len(at.Keys) != len(order) {
+		return nil, Page{}, ErrInvalidCursor
+	}
+
+	// Reading backwards flips the order; the page is put back in order below
+	idColumn := table + ".id"
+	var selects, orders []string
+	var selectVars, orderVars []interface{}
+	for _, key := range order {
+		dir, _ := direction(key.desc != backward)
+		selects = append(selects, "("+key.expr+")::text")
+		orders = append(orders, key.expr+" "+dir)
+		selectVars = append(selectVars, key.vars...)
+		orderVars = append(orderVars, key.vars...)
+	}
+	idDesc := order[len(order)-1].desc
+	idDir, idCompare := direction(idDesc != backward)
+	query = query.Select(table+".*, ARRAY["+strings.Join(selects, ", ")+"] AS cursor_keys", selectVars...).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                strings.Join(orders, ", ") + ", " + idColumn + " " + idDir,
+			Vars:               orderVars,
+			WithoutParentheses: true,
+		}})
+	if at != nil {
+		// Rows past the cursor on a key and level with it on the keys before
+		var past []string
+		var vars, levelVars []interface{}
+		level := ""
+		for i, key := range order {
+			_, compare := direction(key.desc != backward)
+			value := "CAST(? AS " + key.cast + ")"
+			past = append(past, "("+level+key.expr+" "+compare+" "+value+")")
+			vars = append(append(append(vars, levelVars...), key.vars...), at.Keys[i])
+			level += key.expr + " = " + value + " AND "
+			levelVars = append(append(levelVars, key.vars...), at.Keys[i])
+		}
+		past = append(past, "("+level+idColumn+" "+idCompare+" ?)")
+		vars = append(append(vars, levelVars...), at.ID)
+		query = query.Where(strings.Join(past, " OR "), vars...)
+	} else {
+		query = query.Offset(o.Offset)
+	}
+	// One more row than asked tells whether there is a further page
+	if o.Limit > 0 {
+		query = query.Limit(o.Limit + 1)
+	}
+
+	var rows []keyed[T]
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, Page{}, err
+	}
+	more := o.Limit > 0 && len(rows) > o.Limit
+	if more {
+		rows = rows[:o.Limit]
+	}
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	items := make([]T, len(rows))
+	keys := make([][]string, len(rows))
+	for i, row := range rows {
+		items[i], keys[i] = row.Item, row.CursorKeys
+	}
+	page := Page{Total: total}
+	if len(items) > 0 {
+		first, last := 0, len(items)-1
+		hasPrev := (backward && more) || (!backward && (at != nil || o.Offset > 0))
+		hasNext := (!backward && more) || backward
+		if hasPrev {
+			page.PrevCursor = cursor{Sort: sort, Keys: keys[first], ID: id(items[first])}.encode()
+		}
+		if hasNext {
+			page.NextCursor = cursor{Sort: sort, Keys: keys[last], ID: id(items[last])}.encode()
+		}
+	}
+	return items, page, nil
+}
+
+// memoryPage pages matches, a subset of ordered, the way gormPage does. Items
+// are found by the position of the cursor's item in ordered rather than by
+// its sort key, so ordered must keep items that no longer match or are in
+// the trash.
+func memoryPage[T any](ordered, matches []T, sort string, o ListOptions, id func(T) uuid.UUID) ([]T, Page, error) {
+	at, backward, err := o.pageBounds(sort)
+	if err != nil {
+		return nil, Page{}, err
+	}
+	page := Page{Total: int64(len(matches))}
+
+	start, end := min(o.Offset, len(matches)), len(matches)
+	if at != nil {
+		position := map[uuid.UUID]int{}
+		for i, item := range ordered {
+			position[id(item)] = i
+		}
+		atPosition, ok := position[at.ID]
+		if !ok {
+			return nil, Page{}, ErrInvalidCursor
+		}
+		// Matches before the cursor's item end where the ones after it start
+		split := len(matches)
+		for i, item := range matches {
+			if position[id(item)] >= atPosition {
+				split = i
+				break
+			}
+		}
+		if backward {
+			start, end = 0, split
+		} else {
+			start = split
+			if start < len(matches) && id(matches[start]) == at.ID {
+				start++
+			}
+		}
+	}
+	if o.Limit > 0 {
+		if backward {
+			start = max(end-o.Limit, 0)
+		} else {
+			end = min(start+o.Limit, end)
+		}
+	}
+
+	items := append([]T{}, matches[start:end]...)
+	if len(items) > 0 {
+		if start > 0 {
+			page.PrevCursor = cursor{Sort: sort, ID: id(items[0])}.encode()
+		}
+		if end < len(matches) {
+			page.NextCursor = cursor{Sort: sort, ID: id(items[len(items)-1])}.encode()
+		}
+	}
+	return items, page, nil
+}
+
+// memorySort orders items the way gormPage orders rows: best match first when
+// rank is set, or else by each field in turn, and then by ID in the direction
+// of the last field. less compares two items by one field, ascending.
+func memorySort[T any](items []T, fields []SortField, rank func(T) float64, less func(field string, a, b T) bool, id func(T) uuid.UUID) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		idDesc := true
+		if rank != nil {
+			if rankA, rankB := rank(a), rank(b); rankA != rankB {
+				return rankA > rankB
+			}
+		} else {
+			for _, field := range fields {
+				x, y := a, b
+				if field.Desc {
+					x, y = b, a
+				}
+				if less(field.Field, x, y) != less(field.Field, y, x) {
+					return less(field.Field, x, y)
+				}
+			}
+			idDesc = fields[len(fields)-1].Desc
+		}
+		if idDesc {
+			return id(a).String() > id(b).String()
+		}
+		return id(a).String() < id(b).String()
+	})
+}
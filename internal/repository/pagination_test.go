@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// pagingTasks stores tasks due on the given dates in a new in-memory
+// repository; tasks due on the same day are only told apart by their IDs
+func pagingTasks(t *testing.T, dueDates ...string) (*MemoryTaskRepository, uuid.UUID) {
+	t.Helper()
+	repo := NewMemoryTaskRepository()
+	workspaceID := uuid.New()
+	for i, dueDate := range dueDates {
+		due, err := time.Parse("2006-01-02", dueDate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		task := models.Task{WorkspaceID: workspaceID, Title: fmt.Sprintf("task %d", i), DueDate: due}
+		if err := repo.Create(context.Background(), Actor{WorkspaceID: workspaceID}, &task); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo, workspaceID
+}
+
+func taskIDs(tasks []models.Task) []uuid.UUID {
+	ids := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// walkPages lists every page of a task list from its first page forwards
+// along the next cursors, or from its last page backwards along the previous
+// ones, and returns the task IDs in list order
+func walkPages(t *testing.T, repo TaskRepository, workspaceID uuid.UUID, filter TaskFilter, backward bool) []uuid.UUID {
+	t.Helper()
+	if backward {
+		// Stepping back from past the last item starts at the end of the list
+		all, _, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: ListOptions{Sort: filter.Sort}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tasks, page, err := repo.List(context.Background(), workspaceID, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for page.NextCursor != "" {
+			filter.After = page.NextCursor
+			if tasks, page, err = repo.List(context.Background(), workspaceID, filter); err != nil {
+				t.Fatal(err)
+			}
+		}
+		ids := taskIDs(tasks)
+		for pages := 0; page.PrevCursor != ""; pages++ {
+			if pages > len(all) {
+				t.Fatal("paging backwards does not end")
+			}
+			filter.After, filter.Before = "", page.PrevCursor
+			if tasks, page, err = repo.List(context.Background(), workspaceID, filter); err != nil {
+				t.Fatal(err)
+			}
+			if len(tasks) == 0 || len(tasks) > filter.Limit {
+				t.Fatalf("page has %d tasks, want 1 to %d", len(tasks), filter.Limit)
+			}
+			ids = append(taskIDs(tasks), ids...)
+		}
+		return ids
+	}
+
+	var ids []uuid.UUID
+	for pages := 0; ; pages++ {
+		tasks, page, err := repo.List(context.Background(), workspaceID, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks) > filter.Limit {
+			t.Fatalf("page has %d tasks, want at most %d", len(tasks), filter.Limit)
+		}
+		if pages > 0 && page.PrevCursor == "" {
+			t.Error("a later page has no previous cursor")
+		}
+		ids = append(ids, taskIDs(tasks)...)
+		if page.NextCursor == "" {
+			return ids
+		}
+		if pages > len(ids) {
+			t.Fatal("paging forwards does not end")
+		}
+		filter.After = page.NextCursor
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	// Ties on the due date are broken by ID
+	repo, workspaceID := pagingTasks(t, "2026-03-02", "2026-03-01", "2026-03-02", "2026-03-02", "2026-03-03", "2026-03-01", "2026-03-02")
+
+	for _, sort := range []SortField{{Field: "dueDate"}, {Field: "dueDate", Desc: true}, {Field: "title", Desc: true}} {
+		for _, limit := range []int{1, 2, 3, 7, 10} {
+			filter := TaskFilter{ListOptions: ListOptions{Sort: []SortField{sort}, Limit: limit}}
+			all, _, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: ListOptions{Sort: []SortField{sort}}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := fmt.Sprint(taskIDs(all))
+			for _, backward := range []bool{false, true} {
+				if got := fmt.Sprint(walkPages(t, repo, workspaceID, filter, backward)); got != want {
+					t.Errorf("sort %+v, limit %d, backward %v: pages list\n%s\nwant\n%s", sort, limit, backward, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCursorOrderWithTies(t *testing.T) {
+	repo, workspaceID := pagingTasks(t, "2026-03-02", "2026-03-02", "2026-03-02", "2026-03-01")
+
+	for _, desc := range []bool{false, true} {
+		tasks, _, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: ListOptions{Sort: []SortField{{Field: "dueDate", Desc: desc}}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 1; i < len(tasks); i++ {
+			a, b := tasks[i-1], tasks[i]
+			if !a.DueDate.Equal(b.DueDate) {
+				if a.DueDate.After(b.DueDate) != desc {
+					t.Errorf("desc %v: %s listed before %s", desc, a.DueDate, b.DueDate)
+				}
+				continue
+			}
+			// The ID breaks ties in the direction of the sort
+			if (a.ID.String() > b.ID.String()) != desc {
+				t.Errorf("desc %v: tie broken as %s before %s", desc, a.ID, b.ID)
+			}
+		}
+	}
+}
+
+func TestCursorErrors(t *testing.T) {
+	repo, workspaceID := pagingTasks(t, "2026-03-01", "2026-03-02", "2026-03-03")
+	_, page, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: ListOptions{Sort: []SortField{{Field: "dueDate"}}, Limit: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		options ListOptions
+	}{
+		{"garbage", ListOptions{Sort: []SortField{{Field: "dueDate"}}, After: "not-a-cursor"}},
+		{"other sort", ListOptions{Sort: []SortField{{Field: "title"}}, After: page.NextCursor}},
+		{"other direction", ListOptions{Sort: []SortField{{Field: "dueDate", Desc: true}}, After: page.NextCursor}},
+		{"after and before", ListOptions{Sort: []SortField{{Field: "dueDate"}}, After: page.NextCursor, Before: page.NextCursor}},
+		{"unknown item", ListOptions{Sort: []SortField{{Field: "dueDate"}}, After: cursor{Sort: sortName("dueDate", false), ID: uuid.New()}.encode()}},
+	}
+	for _, tt := range tests {
+		if _, _, err := repo.List(context.Background(), workspaceID, TaskFilter{ListOptions: tt.options}); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: error = %v, want ErrInvalidCursor", tt.name, err)
+		}
+	}
+}
+
+// dryRunDB returns a Postgres database that records the SQL of its last
+// query or raw query instead of running it
+func dryRunDB(t *testing.T) (*gorm.DB, *string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sql string
+	capture := func(tx *gorm.DB) {
+		sql = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+	}
+	if err := db.Callback().Query().After("gorm:query").Register("test:capture", capture); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Row().After("gorm:row").Register("test:capture", capture); err != nil {
+		t.Fatal(err)
+	}
+	return db, &sql
+}
+
+func TestGormPageKeysetCondition(t *testing.T) {
+	db, sql := dryRunDB(t)
+	repo := NewGormTaskRepository(db)
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+	tests := []struct {
+		sort  SortField
+		after bool
+		want  []string
+	}{
+		{SortField{Field: "dueDate"}, true, []string{
+			"(due_date > CAST('2026-03-02' AS date)) OR (due_date = CAST('2026-03-02' AS date) AND tasks.id > '" + id.String() + "')",
+			"ORDER BY due_date ASC, tasks.id ASC LIMIT 3",
+		}},
+		{SortField{Field: "dueDate", Desc: true}, true, []string{
+			"(due_date < CAST('2026-03-02' AS date)) OR (due_date = CAST('2026-03-02' AS date) AND tasks.id < '" + id.String() + "')",
+			"ORDER BY due_date DESC, tasks.id DESC LIMIT 3",
+		}},
+		// Reading before a cursor flips the order and the comparisons
+		{SortField{Field: "dueDate"}, false, []string{
+			"(due_date < CAST('2026-03-02' AS date)) OR (due_date = CAST('2026-03-02' AS date) AND tasks.id < '" + id.String() + "')",
+			"ORDER BY due_date DESC, tasks.id DESC LIMIT 3",
+		}},
+	}
+	for _, tt := range tests {
+		token := cursor{Sort: sortName(tt.sort.Field, tt.sort.Desc), Keys: []string{"2026-03-02"}, ID: id}.encode()
+		options := ListOptions{Sort: []SortField{tt.sort}, Limit: 2, Before: token}
+		if tt.after {
+			options.After, options.Before = token, ""
+		}
+		if _, _, err := repo.List(context.Background(), uuid.New(), TaskFilter{ListOptions: options}); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(*sql, want) {
+				t.Errorf("sort %+v, after %v: SQL\n%s\ndoes not contain\n%s", tt.sort, tt.after, *sql, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,273 @@
+// Package repository holds the storage of tasks, learning resources and saved
+// views behind interfaces, with a GORM implementation for Postgres and an
+// in-memory one for running the handlers without a database. It also holds
+// the queries other handlers share with the repositories, such as goal
+// progress and technology lookups.
+package repository
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"diary-backend/internal/models"
+	"diary-backend/internal/querylang"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotFound is returned when a record does not exist in the workspace
+	ErrNotFound = errors.New("record not found")
+	// ErrInvalidSort is returned when a list is sorted by an unknown field
+	ErrInvalidSort = errors.New("invalid sort field")
+)
+
+// Actor is the user making a change and the workspace it is made in. Changes
+// are recorded in the audit trail in the actor's name.
+type Actor struct {
+	WorkspaceID uuid.UUID
+	UserID      uuid.UUID
+}
+
+// SortField is a field a list is sorted by, as it appears in the API
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ListOptions are the filters, sorting and paging shared by every list
+type ListOptions struct {
+	Search string      // full-text search; every word must match as a prefix
+	Query  string      // filter in the q language, see package querylang
+	Tags   []string    // every tag must be present
+	AnyTag bool        // one of Tags is enough
+	Sort   []SortField // in order of precedence; the ID breaks ties
+	Limit  int
+	Offset int    // ignored when paging by cursor
+	After  string // cursor of the item the page starts after
+	Before string // cursor of the item the page ends before
+}
+
+// searchRank scores a record's fields against the search, most important
+// field first. It is 0 when the record does not match and 1 without a search.
+func (o ListOptions) searchRank(fields ...string) float64 {
+	terms := searchTerms(o.Search)
+	if len(terms) == 0 {
+		return 1
+	}
+	return textMatch(terms, fields...)
+}
+
+// rankedSearch reports whether a list without sort fields is ordered by how
+// well its records match the search
+func (o ListOptions) rankedSearch() bool {
+	return len(o.Sort) == 0 && prefixQuery(o.Search) != ""
+}
+
+// sortFields returns the fields a list is sorted by: the sort fields, or
+// else the fallback field ascending
+func (o ListOptions) sortFields(fallback string) []SortField {
+	if len(o.Sort) == 0 {
+		return []SortField{{Field: fallback}}
+	}
+	return o.Sort
+}
+
+// sortKeys returns what a list is ordered by and the name of that order in
+// cursors: the sort fields, relevance for a search without any, best match
+// first, or else the fallback field
+func (o ListOptions) sortKeys(keys map[string]sortKey, fallback string) ([]orderKey, string, error) {
+	if o.rankedSearch() {
+		rank := sortKey{expr: rankExpr, vars: []interface{}{prefixQuery(o.Search)}, cast: "real"}
+		return []orderKey{{sortKey: rank, desc: true}}, "relevance", nil
+	}
+	fields := o.sortFields(fallback)
+	order := make([]orderKey, len(fields))
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		key, ok := keys[field.Field]
+		if !ok {
+			return nil, "", ErrInvalidSort
+		}
+		order[i] = orderKey{sortKey: key, desc: field.Desc}
+		names[i] = sortName(field.Field, field.Desc)
+	}
+	return order, strings.Join(names, ","), nil
+}
+
+// matchesTags reports whether tags include every tag of the filter, or one
+// of them with AnyTag
+func (o ListOptions) matchesTags(tags pq.StringArray) bool {
+	for _, want := range o.Tags {
+		found := slices.Contains(tags, want)
+		if found && o.AnyTag {
+			return true
+		}
+		if !found && !o.AnyTag {
+			return false
+		}
+	}
+	return len(o.Tags) == 0 || !o.AnyTag
+}
+
+// apply adds the search and tag filters to a query on a table with
+// search_vector and tags columns. The tag operators use the GIN index on tags.
+func (o ListOptions) apply(query *gorm.DB) *gorm.DB {
+	if tsquery := prefixQuery(o.Search); tsquery != "" {
+		query = query.Where("search_vector @@ to_tsquery('english', ?)", tsquery)
+	}
+	if len(o.Tags) > 0 {
+		operator := "@>"
+		if o.AnyTag {
+			operator = "&&"
+		}
+		query = query.Where("tags "+operator+" ?", pq.StringArray(o.Tags))
+	}
+	return query
+}
+
+// uncountable reports whether err is a problem with a list's filter or sort
+// that Counts leaves the list uncounted for
+func uncountable(err error) bool {
+	return errors.Is(err, ErrInvalidSort) || errors.As(err, new(*querylang.Error))
+}
+
+// gormCounts counts the matches of each filter in a single query, with one
+// count subquery per filter. Filters build cannot turn into a query are not
+// counted.
+func gormCounts[F any](db *gorm.DB, filters []F, build func(F) (*gorm.DB, error)) ([]*int64, error) {
+	counts := make([]*int64, len(filters))
+	var counted []int
+	var columns []string
+	var subqueries []interface{}
+	for i, filter := range filters {
+		query, err := build(filter)
+		if uncountable(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		counted = append(counted, i)
+		columns = append(columns, "(?)")
+		subqueries = append(subqueries, query.Select("count(*)"))
+	}
+	if len(counted) == 0 {
+		return counts, nil
+	}
+
+	var row struct{ Counts pq.Int64Array }
+	if err := db.Raw("SELECT ARRAY["+strings.Join(columns, ", ")+"] AS counts", subqueries...).Scan(&row).Error; err != nil {
+		return nil, err
+	}
+	if len(row.Counts) != len(counted) {
+		return nil, errors.New("count query returned the wrong number of counts")
+	}
+	for j, i := range counted {
+		counts[i] = &row.Counts[j]
+	}
+	return counts, nil
+}
+
+// memoryCounts counts the matches of each filter with list, which should
+// ignore their paging. Filters list rejects are not counted.
+func memoryCounts[F any](filters []F, list func(F) (Page, error)) ([]*int64, error) {
+	counts := make([]*int64, len(filters))
+	for i, filter := range filters {
+		page, err := list(filter)
+		if uncountable(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		counts[i] = &page.Total
+	}
+	return counts, nil
+}
+
+// ActivityEvents builds the query for a workspace's audit trail, with the
+// email and name of each event's actor
+func ActivityEvents(db *gorm.DB, workspaceID uuid.UUID) *gorm.DB {
+	return db.Model(&models.ActivityEvent{}).
+		Select("activity_events.*, users.email AS actor_email, users.name AS actor_name").
+		Joins("LEFT JOIN users ON users.id = activity_events.actor_id").
+		Where("activity_events.workspace_id = ?", workspaceID)
+}
+
+// gormHistory returns one page of the events of an entity, newest first, and
+// how many there are
+func gormHistory(db *gorm.DB, workspaceID uuid.UUID, entityType string, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error) {
+	query := ActivityEvents(db, workspaceID).
+		Where("activity_events.entity_type = ? AND activity_events.entity_id = ?", entityType, id).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var events []models.ActivityEvent
+	err := query.Order("activity_events.created_at DESC, activity_events.id DESC").
+		Offset(offset).Limit(limit).
+		Scan(&events).Error
+	return events, total, err
+}
+
+// gormTrash returns one page of the workspace's records of a soft-deleted
+// model in the trash, most recently deleted first, and how many there are
+func gormTrash[T any](db *gorm.DB, workspaceID uuid.UUID, limit, offset int) ([]T, int64, error) {
+	query := db.Unscoped().Model(new(T)).
+		Where("workspace_id = ? AND deleted_at IS NOT NULL", workspaceID).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var records []T
+	err := query.Order("deleted_at DESC, created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&records).Error
+	return records, total, err
+}
+
+// pageOf returns the items of a page of a list, every item from offset with
+// a limit of 0
+func pageOf[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+// Repositories are the stores the handlers are constructed with
+type Repositories struct {
+	Tasks     TaskRepository
+	Resources ResourceRepository
+	Views     ViewRepository
+}
+
+// NewGorm returns repositories backed by the database
+func NewGorm(db *gorm.DB) Repositories {
+	return Repositories{
+		Tasks:     NewGormTaskRepository(db),
+		Resources: NewGormResourceRepository(db),
+		Views:     NewGormViewRepository(db),
+	}
+}
+
+// NewMemory returns empty in-memory repositories
+func NewMemory() Repositories {
+	return Repositories{
+		Tasks:     NewMemoryTaskRepository(),
+		Resources: NewMemoryResourceRepository(),
+		Views:     NewMemoryViewRepository(),
+	}
+}
@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// resourceSortKeys maps the fields resources can be sorted by to their sort
+// keys. Priorities sort low to high; unrated and unestimated resources sort as
+// 0 and ones not completed after every completed one.
+var resourceSortKeys = map[string]sortKey{
+	"title":          {expr: "title", cast: "text"},
+	"technology":     {expr: "technology", cast: "text"},
+	"priority":       {expr: "CASE priority WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 ELSE 0 END", cast: "integer"},
+	"rating":         {expr: "coalesce(rating, 0)", cast: "integer"},
+	"progress":       {expr: "coalesce(progress, 0)", cast: "integer"},
+	"estimated_time": {expr: "coalesce(estimated_time, 0)", cast: "integer"},
+	"created_at":     {expr: "created_at", cast: "timestamptz"},
+	"updated_at":     {expr: "updated_at", cast: "timestamptz"},
+	"completed_at":   {expr: "coalesce(completed_at, 'infinity')", cast: "timestamptz"},
+}
+
+// ValidResourceSort reports whether resources can be sorted by field
+func ValidResourceSort(field string) bool {
+	_, ok := resourceSortKeys[field]
+	return ok
+}
+
+// Buckets of estimated time resources can be filtered by
+const (
+	EstimateShort  = "short"  // under an hour
+	EstimateMedium = "medium" // one to four hours
+	EstimateLong   = "long"   // four hours or more
+	EstimateNone   = "none"   // no estimate
+)
+
+// estimateBuckets are the ranges of estimated_time, in minutes, of each
+// bucket: from min up to but not including max, with 0 for no upper bound
+var estimateBuckets = map[string]struct{ min, max int }{
+	EstimateShort:  {0, 60},
+	EstimateMedium: {60, 240},
+	EstimateLong:   {240, 0},
+}
+
+// ValidEstimateBucket reports whether name is a bucket of estimated time
+func ValidEstimateBucket(name string) bool {
+	_, ok := estimateBuckets[name]
+	return ok || name == EstimateNone
+}
+
+// inEstimateBucket reports whether an estimated time falls in a bucket
+func inEstimateBucket(name string, minutes *int) bool {
+	if minutes == nil {
+		return name == EstimateNone
+	}
+	bucket, ok := estimateBuckets[name]
+	return ok && *minutes >= bucket.min && (bucket.max == 0 || *minutes < bucket.max)
+}
+
+// resourceFilterSpec is what the q parameter of the resource list filters on
+var resourceFilterSpec = filterSpec[models.Resource]{
+	fields: map[string]filterField[models.Resource]{
+		"type": {kind: kindEnum, column: "type", values: []string{"article", "video", "course", "documentation", "tutorial", "book", "podcast"},
+			get: func(resource models.Resource) interface{} { return resource.Type }},
+		"status": {kind: kindEnum, column: "status", values: []string{"to-read", "reading", "completed", "bookmarked"},
+			get: func(resource models.Resource) interface{} { return resource.Status }},
+		"priority": {kind: kindEnum, column: "priority", values: []string{"low", "medium", "high"}, ordered: true,
+			get: func(resource models.Resource) interface{} { return resource.Priority }},
+		"technology": {kind: kindText, column: "technology",
+			get: func(resource models.Resource) interface{} { return resource.Technology }},
+		"tag": {kind: kindTags, column: "tags",
+			get: func(resource models.Resource) interface{} { return []string(resource.Tags) }},
+		"rating": {kind: kindNumber, column: "rating",
+			get: func(resource models.Resource) interface{} { return resource.Rating }},
+		"progress": {kind: kindNumber, column: "progress",
+			get: func(resource models.Resource) interface{} { return resource.Progress }},
+		"created": {kind: kindDate, column: "created_at::date",
+			get: func(resource models.Resource) interface{} { return resource.CreatedAt }},
+		"updated": {kind: kindDate, column: "updated_at::date",
+			get: func(resource models.Resource) interface{} { return resource.UpdatedAt }},
+		"completed": {kind: kindDate, column: "completed_at::date",
+			get: func(resource models.Resource) interface{} { return resource.CompletedAt }},
+	},
+	text: resourceSearchFields,
+}
+
+// ResourceFilter selects the resources of a workspace to list. Ranges are
+// inclusive and dates are whole days.
+type ResourceFilter struct {
+	ListOptions
+	Technology    string
+	Type          string
+	Status        string
+	Priority      string
+	RatingMin     *int
+	RatingMax     *int
+	ProgressMin   *int
+	ProgressMax   *int
+	EstimatedTime []string // any of these buckets
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	CompletedFrom *time.Time
+	CompletedTo   *time.Time
+}
+
+// ResourceStatsFilter selects what GetResourceStats aggregates. Resources are
+// filtered by created_at within [From, To] and sessions by session_date
+// within [SessionFrom, SessionTo].
+type ResourceStatsFilter struct {
+	Technology  string
+	From        *time.Time
+	To          *time.Time
+	SessionFrom time.Time
+	SessionTo   time.Time
+}
+
+// ResourceStats aggregates the resources of a workspace and their study time
+type ResourceStats struct {
+	Total          int64
+	Completed      int64
+	Reading        int64
+	ToRead         int64
+	Bookmarked     int64
+	AvgRating      *float64 // nil when no resource is rated
+	Technologies   map[string]int64
+	Types          map[string]int64
+	SessionCount   int64
+	SessionMinutes int64
+}
+
+// SessionFilter selects the study sessions of a workspace to list. Dates are
+// inclusive and a zero Limit lists every session.
+type SessionFilter struct {
+	ResourceID *uuid.UUID
+	Technology string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// SessionTotals counts the sessions a filter matches and their minutes,
+// ignoring its paging
+type SessionTotals struct {
+	Count   int64
+	Minutes int64
+}
+
+// ResourceRepository stores learning resources with their study sessions.
+// Lookups are limited to one workspace and, apart from Trash, never return
+// resources in the trash. Changes are recorded in the audit trail, keep
+// earlier notes and refresh the goals the resource is linked to.
+type ResourceRepository interface {
+	// List returns one page of matching resources and where it is in the list
+	List(ctx context.Context, workspaceID uuid.UUID, filter ResourceFilter) ([]models.Resource, Page, error)
+	// Counts counts the resources each filter matches, ignoring their paging.
+	// Filters with a sort or q filter List would reject are not counted and
+	// get nil.
+	Counts(ctx context.Context, workspaceID uuid.UUID, filters []ResourceFilter) ([]*int64, error)
+	Get(ctx context.Context, workspaceID, id uuid.UUID) (*models.Resource, error)
+	// Highlights ranks the resources with the given IDs against a search and
+	// marks the matches in their title, description and notes, by resource ID
+	Highlights(ctx context.Context, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error)
+	// NormalizeTechnology maps a free-text technology to its canonical name in
+	// the workspace's registry; unregistered values are returned trimmed
+	NormalizeTechnology(ctx context.Context, workspaceID uuid.UUID, value string) (string, error)
+	// NoteVersions lists the earlier notes of a resource, newest first
+	NoteVersions(ctx context.Context, id uuid.UUID) ([]models.ResourceNoteVersion, error)
+	// NoteVersion returns notes version n of a resource
+	NoteVersion(ctx context.Context, id uuid.UUID, n int) (*models.ResourceNoteVersion, error)
+	// History returns one page of the audit trail of a resource, newest
+	// first, and its length. Resources in the trash keep their history.
+	History(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error)
+	// Trash returns one page of the workspace's resources in the trash, most
+	// recently deleted first, and how many there are
+	Trash(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]models.Resource, int64, error)
+	// Sessions returns the matching sessions, newest first, with their totals
+	Sessions(ctx context.Context, workspaceID uuid.UUID, filter SessionFilter) ([]models.Session, SessionTotals, error)
+	// Session returns a session of a resource
+	Session(ctx context.Context, workspaceID, resourceID, id uuid.UUID) (*models.Session, error)
+
+	Create(ctx context.Context, actor Actor, resource *models.Resource) error
+	// Update saves every field of a resource
+	Update(ctx context.Context, actor Actor, resource *models.Resource) error
+	// Delete moves a resource to the trash
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) error
+	// Restore brings a resource back from the trash
+	Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.Resource, error)
+	// Purge permanently deletes a resource in the trash with its sessions
+	Purge(ctx context.Context, actor Actor, id uuid.UUID) error
+	// EmptyTrash permanently deletes every resource in the trash, returning how many were deleted
+	EmptyTrash(ctx context.Context, actor Actor) (int64, error)
+
+	// CreateSession logs a session against a resource. A resource that has
+	// not been started yet is moved to "reading"; the resource is returned as
+	// it is after the session.
+	CreateSession(ctx context.Context, actor Actor, session *models.Session) (*models.Resource, error)
+	// UpdateSession saves every field of a session
+	UpdateSession(ctx context.Context, actor Actor, session *models.Session) error
+	DeleteSession(ctx context.Context, actor Actor, id uuid.UUID) error
+
+	Stats(ctx context.Context, workspaceID uuid.UUID, filter ResourceStatsFilter) (*ResourceStats, error)
+}
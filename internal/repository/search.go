@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// searchWord matches the words a search is made of; anything else, including
+// the tsquery operators, only separates words
+var searchWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// Highlight is the part of a record that matched a search. The text is
+// HTML-escaped and matched words are wrapped in <mark> tags, so that it can be
+// rendered as HTML.
+type Highlight struct {
+	Title   string  `json:"title"`
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+// ts_headline marks matches with these private-use characters rather than
+// <mark> tags, so that the headline can be HTML-escaped before they are
+// turned into tags. They are removed from the text beforehand.
+const (
+	startSel   = "\uE000"
+	stopSel    = "\uE001"
+	selections = startSel + stopSel
+)
+
+// headlineOptions configure ts_headline for snippets: up to two fragments of
+// the body around the matches
+const headlineOptions = "StartSel=\"" + startSel + "\", StopSel=\"" + stopSel + "\", MinWords=10, MaxWords=30, MaxFragments=2, FragmentDelimiter=\" … \""
+
+// titleHeadlineOptions highlight the whole title instead of cutting it short
+const titleHeadlineOptions = "StartSel=\"" + startSel + "\", StopSel=\"" + stopSel + "\", HighlightAll=true"
+
+// selectionMarks turns the selections of an escaped headline into <mark> tags
+var selectionMarks = strings.NewReplacer(startSel, "<mark>", stopSel, "</mark>")
+
+// markHeadline HTML-escapes a headline from ts_headline and marks its matches
+func markHeadline(headline string) string {
+	return selectionMarks.Replace(html.EscapeString(headline))
+}
+
+// searchTerms returns the lowercased words of a search
+func searchTerms(search string) []string {
+	return searchWord.FindAllString(strings.ToLower(search), -1)
+}
+
+// HasSearchWords reports whether a search has any words to match. Searches
+// without words, such as "!!!", would otherwise match every record.
+func HasSearchWords(search string) bool {
+	return searchWord.MatchString(search)
+}
+
+// prefixQuery turns a search into a tsquery matching records with a word
+// starting with each word of the search, so that "go rout" becomes
+// "go:* & rout:*" and matches while the user is still typing. It is empty
+// when the search has no words.
+func prefixQuery(search string) string {
+	terms := searchTerms(search)
+	for i, term := range terms {
+		terms[i] = term + ":*"
+	}
+	return strings.Join(terms, " & ")
+}
+
+// rankExpr scores how well the search vector matches a prefix query
+const rankExpr = "ts_rank_cd(search_vector, to_tsquery('english', ?))"
+
+// highlights ranks and highlights the records of a table with the given IDs.
+// body is the SQL expression snippets are cut from.
+func highlights(db *gorm.DB, table, body string, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error) {
+	result := make(map[uuid.UUID]Highlight, len(ids))
+	tsquery := prefixQuery(search)
+	if tsquery == "" || len(ids) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		ID uuid.UUID
+		Highlight
+	}
+	err := db.Table(table).
+		Select("id, "+
+			"ts_headline('english', translate(title, ?, ''), to_tsquery('english', ?), ?) AS title, "+
+			"ts_headline('english', translate("+body+", ?, ''), to_tsquery('english', ?), ?) AS snippet, "+
+			rankExpr+" AS rank",
+			selections, tsquery, titleHeadlineOptions, selections, tsquery, headlineOptions, tsquery).
+		Where("workspace_id = ? AND id IN ? AND deleted_at IS NULL", workspaceID, ids).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		row.Title = markHeadline(row.Title)
+		row.Snippet = markHeadline(row.Snippet)
+		result[row.ID] = row.Highlight
+	}
+	return result, nil
+}
+
+// textMatch scores how well fields match the words of a search the way the
+// search vectors do, for the in-memory repositories: every word must start a
+// word of some field, and matches in earlier fields count for more. Words are
+// not stemmed. It returns 0 when a word matches nothing.
+func textMatch(terms []string, fields ...string) float64 {
+	rank := 0.0
+	for _, term := range terms {
+		found := false
+		for i, field := range fields {
+			for _, word := range searchWord.FindAllString(strings.ToLower(field), -1) {
+				if strings.HasPrefix(word, term) {
+					found = true
+					rank += 1 / float64(i+1)
+				}
+			}
+		}
+		if !found {
+			return 0
+		}
+	}
+	return rank
+}
+
+// matchesTerm reports whether a word starts with a search word
+func matchesTerm(word string, terms []string) bool {
+	lower := strings.ToLower(word)
+	for _, term := range terms {
+		if strings.HasPrefix(lower, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// markTerms HTML-escapes text and wraps its words that start with a search
+// word in <mark> tags
+func markTerms(text string, terms []string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range searchWord.FindAllStringIndex(text, -1) {
+		word := text[loc[0]:loc[1]]
+		if !matchesTerm(word, terms) {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:loc[0]]))
+		b.WriteString("<mark>" + word + "</mark>")
+		last = loc[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
+
+// snippet returns up to 30 words of body starting shortly before the first
+// match, with the matches marked, like ts_headline does for Postgres
+func snippet(body string, terms []string) string {
+	words := strings.FieldsFunc(body, unicode.IsSpace)
+	start := 0
+	for i, word := range words {
+		if slices.ContainsFunc(searchWord.FindAllString(word, -1), func(w string) bool { return matchesTerm(w, terms) }) {
+			start = max(i-5, 0)
+			break
+		}
+	}
+	end := min(start+30, len(words))
+	return markTerms(strings.Join(words[start:end], " "), terms)
+}
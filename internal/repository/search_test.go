@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+func TestMarkTerms(t *testing.T) {
+	tests := []struct {
+		text  string
+		terms []string
+		want  string
+	}{
+		{"Go routines", []string{"rout"}, "Go <mark>routines</mark>"},
+		{"Go routines", []string{"go", "rout"}, "<mark>Go</mark> <mark>routines</mark>"},
+		{"nothing here", []string{"go"}, "nothing here"},
+		{`<script>alert("go")</script>`, []string{"go"}, `&lt;script&gt;alert(&#34;<mark>go</mark>&#34;)&lt;/script&gt;`},
+		{`<img src=x onerror=go()>`, []string{"x"}, `&lt;img src=<mark>x</mark> onerror=go()&gt;`},
+		{"Tom & Jerry's", []string{"amp"}, "Tom &amp; Jerry&#39;s"},
+	}
+	for _, tt := range tests {
+		if got := markTerms(tt.text, tt.terms); got != tt.want {
+			t.Errorf("markTerms(%q, %q) = %q, want %q", tt.text, tt.terms, got, tt.want)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	words := strings.Fields("one two three four five six seven eight nine ten")
+	body := strings.Join(words, " ") + " <b>goroutine</b> " + strings.Repeat("filler ", 40)
+
+	got := snippet(body, []string{"gorout"})
+	if !strings.HasPrefix(got, "six seven eight nine ten &lt;b&gt;<mark>goroutine</mark>&lt;/b&gt; filler") {
+		t.Errorf("snippet = %q, want it to start five words before the escaped match", got)
+	}
+	if n := len(strings.Fields(got)); n != 30 {
+		t.Errorf("snippet has %d words, want 30", n)
+	}
+	if got := snippet("<i>no match</i>", []string{"go"}); got != "&lt;i&gt;no match&lt;/i&gt;" {
+		t.Errorf("snippet without a match = %q, want the escaped start of the body", got)
+	}
+}
+
+func TestMarkHeadline(t *testing.T) {
+	headline := "<script>" + startSel + "go" + stopSel + "</script> & more"
+	want := "&lt;script&gt;<mark>go</mark>&lt;/script&gt; &amp; more"
+	if got := markHeadline(headline); got != want {
+		t.Errorf("markHeadline = %q, want %q", got, want)
+	}
+}
+
+func TestMemoryHighlightsAreEscaped(t *testing.T) {
+	repo := NewMemoryTaskRepository()
+	workspaceID := uuid.New()
+	description := `<img src=x onerror="alert(1)"> learn go`
+	task := models.Task{WorkspaceID: workspaceID, Title: "<b>Go</b> notes", Description: &description}
+	if err := repo.Create(context.Background(), Actor{WorkspaceID: workspaceID}, &task); err != nil {
+		t.Fatal(err)
+	}
+
+	highlights, err := repo.Highlights(context.Background(), workspaceID, "go", []uuid.UUID{task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := highlights[task.ID]
+	if got.Title != "&lt;b&gt;<mark>Go</mark>&lt;/b&gt; notes" {
+		t.Errorf("title = %q", got.Title)
+	}
+	if got.Snippet != "&lt;img src=x onerror=&#34;alert(1)&#34;&gt; learn <mark>go</mark>" {
+		t.Errorf("snippet = %q", got.Snippet)
+	}
+}
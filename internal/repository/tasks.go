@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// DescendantIDsSQL selects the IDs of every subtask below a task, at any depth
+const DescendantIDsSQL = `
+	WITH RECURSIVE descendants AS (
+		SELECT id FROM tasks WHERE parent_id = ?
+		UNION ALL
+		SELECT t.id FROM tasks t JOIN descendants d ON t.parent_id = d.id
+	)
+	SELECT id FROM descendants`
+
+// BlockedTaskSQL matches tasks that have at least one unfinished blocker.
+// Cancelled and deleted blockers no longer block.
+const BlockedTaskSQL = `EXISTS (
+	SELECT 1 FROM task_dependencies td
+	JOIN tasks blocker ON blocker.id = td.depends_on_id
+	WHERE td.task_id = tasks.id AND blocker.deleted_at IS NULL
+		AND blocker.completed = false AND blocker.status != 'cancelled')`
+
+// taskSortKeys maps the fields tasks can be sorted by to their sort keys.
+// Tasks without a project sort last, as NULLs do in ascending order.
+var taskSortKeys = map[string]sortKey{
+	"dueDate":   {expr: "due_date", cast: "date"},
+	"createdAt": {expr: "created_at", cast: "timestamptz"},
+	"created":   {expr: "created_at", cast: "timestamptz"},
+	"updatedAt": {expr: "updated_at", cast: "timestamptz"},
+	"projectId": {expr: "coalesce(project_id, 'ffffffff-ffff-ffff-ffff-ffffffffffff')", cast: "uuid"},
+	"title":     {expr: "title", cast: "text"},
+	"priority":  {expr: "CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'medium' THEN 2 WHEN 'low' THEN 1 END", cast: "integer"},
+	"category":  {expr: "category", cast: "text"},
+	"status":    {expr: "status", cast: "text"},
+	"completed": {expr: "completed", cast: "boolean"},
+}
+
+// taskFilterSpec is what the q parameter of the task list filters on.
+// blocked reports whether a task has unfinished blockers, for the in-memory
+// repository.
+func taskFilterSpec(blocked func(models.Task) bool) filterSpec[models.Task] {
+	return filterSpec[models.Task]{
+		fields: map[string]filterField[models.Task]{
+			"priority": {kind: kindEnum, column: "priority", values: []string{"low", "medium", "high", "urgent"}, ordered: true,
+				get: func(task models.Task) interface{} { return task.Priority }},
+			"status": {kind: kindEnum, column: "status", values: []string{"pending", "in-progress", "review", "completed", "cancelled"},
+				get: func(task models.Task) interface{} { return task.Status }},
+			"category": {kind: kindEnum, column: "category", values: []string{"personal", "office", "learning", "research"},
+				get: func(task models.Task) interface{} { return task.Category }},
+			"tag": {kind: kindTags, column: "tags",
+				get: func(task models.Task) interface{} { return []string(task.Tags) }},
+			"due": {kind: kindDate, column: "due_date",
+				get: func(task models.Task) interface{} { return task.DueDate }},
+			"created": {kind: kindDate, column: "created_at::date",
+				get: func(task models.Task) interface{} { return task.CreatedAt }},
+			"updated": {kind: kindDate, column: "updated_at::date",
+				get: func(task models.Task) interface{} { return task.UpdatedAt }},
+			"completed": {kind: kindBool, column: "completed",
+				get: func(task models.Task) interface{} { return task.Completed }},
+			"blocked": {kind: kindBool, column: BlockedTaskSQL,
+				get: func(task models.Task) interface{} { return blocked(task) }},
+			"project": {kind: kindUUID, column: "project_id",
+				get: func(task models.Task) interface{} { return task.ProjectID }},
+			"parent": {kind: kindUUID, column: "parent_id",
+				get: func(task models.Task) interface{} { return task.ParentID }},
+		},
+		text: taskSearchFields,
+	}
+}
+
+// priorityRank orders priorities from low to urgent
+var priorityRank = map[string]int{"low": 1, "medium": 2, "high": 3, "urgent": 4}
+
+// TaskFilter selects the tasks of a workspace to list
+type TaskFilter struct {
+	ListOptions
+	Category   string
+	Priority   string
+	Status     string
+	Completed  *bool
+	Blocked    *bool // has unfinished blockers
+	ProjectID  *uuid.UUID
+	DateFilter string // today, tomorrow, this-week, overdue
+}
+
+// dueWindow turns a date filter into an inclusive range of due dates,
+// formatted YYYY-MM-DD with "" for an open end. Overdue also limits the list
+// to open tasks.
+func dueWindow(dateFilter string, now time.Time) (from, to string, openOnly bool) {
+	switch dateFilter {
+	case "today":
+		today := now.Format("2006-01-02")
+		return today, today, false
+	case "tomorrow":
+		tomorrow := now.AddDate(0, 0, 1).Format("2006-01-02")
+		return tomorrow, tomorrow, false
+	case "this-week":
+		weekEnd := now.AddDate(0, 0, 7-int(now.Weekday()))
+		return now.Format("2006-01-02"), weekEnd.Format("2006-01-02"), false
+	case "overdue":
+		return "", now.AddDate(0, 0, -1).Format("2006-01-02"), true
+	}
+	return "", "", false
+}
+
+// TaskUpdate holds the fields of a task to change; nil fields are left alone
+type TaskUpdate struct {
+	Title       *string
+	Description *string
+	Completed   *bool
+	DueDate     *time.Time
+	Priority    *string
+	Category    *string
+	Status      *string
+	ProjectID   *uuid.UUID
+	Tags        *[]string
+
+	// ClearRecurrence removes the recurrence rule. Recurrence sets a new rule
+	// together with the series it starts.
+	ClearRecurrence bool
+	Recurrence      *string
+	SeriesID        *uuid.UUID
+	SeriesStart     *time.Time
+
+	// CompleteDescendants completes every open subtask below the task and
+	// ticks off the checklists of the whole tree
+	CompleteDescendants bool
+
+	// NextOccurrence builds the task following the updated task, or nil when
+	// its series has ended. It is set when the update completes a recurring
+	// task; the occurrence is stored in the same transaction unless the
+	// series already has a task on its due date.
+	NextOccurrence func(task models.Task) (*models.Task, error)
+}
+
+// ChecklistItemUpdate holds the fields of a checklist item to change; nil
+// fields are left alone
+type ChecklistItemUpdate struct {
+	Title    *string
+	Done     *bool
+	Position *int
+}
+
+var (
+	// ErrParentInTrash is returned when restoring a subtask whose parent is still in the trash
+	ErrParentInTrash = errors.New("parent task is in the trash")
+	// ErrSelfDependency is returned when a task would depend on itself
+	ErrSelfDependency = errors.New("a task cannot depend on itself")
+	// ErrDependencyCycle is returned when a dependency would close a cycle
+	ErrDependencyCycle = errors.New("dependency would create a cycle")
+)
+
+// TaskStats counts the tasks of a workspace
+type TaskStats struct {
+	Total          int64 `json:"total"`
+	Today          int64 `json:"today"`
+	TodayCompleted int64 `json:"todayCompleted"`
+	InProgress     int64 `json:"inProgress"`
+	Completed      int64 `json:"completed"`
+	Overdue        int64 `json:"overdue"`
+}
+
+// TaskRepository stores tasks with their checklists and dependencies. Lookups
+// are limited to one workspace and, apart from Trash, never return tasks in
+// the trash. Changes are recorded in the audit trail and updates keep the
+// task's earlier versions.
+type TaskRepository interface {
+	// List returns one page of matching tasks and where it is in the list
+	List(ctx context.Context, workspaceID uuid.UUID, filter TaskFilter) ([]models.Task, Page, error)
+	// Counts counts the tasks each filter matches, ignoring their paging.
+	// Filters with a sort or q filter List would reject are not counted and
+	// get nil.
+	Counts(ctx context.Context, workspaceID uuid.UUID, filters []TaskFilter) ([]*int64, error)
+	Get(ctx context.Context, workspaceID, id uuid.UUID) (*models.Task, error)
+	// Highlights ranks the tasks with the given IDs against a search and marks
+	// the matches in their title and description, by task ID
+	Highlights(ctx context.Context, workspaceID uuid.UUID, search string, ids []uuid.UUID) (map[uuid.UUID]Highlight, error)
+	// Subtasks lists the direct subtasks of a task by due date
+	Subtasks(ctx context.Context, id uuid.UUID) ([]models.Task, error)
+	// Checklist lists the checklist items of a task by position
+	Checklist(ctx context.Context, id uuid.UUID) ([]models.ChecklistItem, error)
+	// Project returns a project tasks of the workspace can be assigned to
+	Project(ctx context.Context, workspaceID, projectID uuid.UUID) (*models.Project, error)
+	// OpenBlockers lists the unfinished tasks blocking a task
+	OpenBlockers(ctx context.Context, id uuid.UUID) ([]models.Task, error)
+	// CountOpenDescendants counts the subtasks below a task, at any depth, that
+	// are neither completed nor cancelled: the ones CompleteDescendants completes
+	CountOpenDescendants(ctx context.Context, id uuid.UUID) (int64, error)
+	// ChecklistItem returns an item of a task's checklist
+	ChecklistItem(ctx context.Context, taskID, id uuid.UUID) (*models.ChecklistItem, error)
+	// Dependencies lists the tasks blocking a task and the tasks it blocks, by due date
+	Dependencies(ctx context.Context, id uuid.UUID) (blockedBy, blocking []models.Task, err error)
+	// ProjectGraph lists the tasks of a project by due date and the
+	// dependencies between them
+	ProjectGraph(ctx context.Context, workspaceID, projectID uuid.UUID) ([]models.Task, []models.TaskDependency, error)
+	// Versions lists the earlier versions of a task, oldest first
+	Versions(ctx context.Context, id uuid.UUID) ([]models.TaskVersion, error)
+	// Version returns version n of a task
+	Version(ctx context.Context, id uuid.UUID, n int) (*models.TaskVersion, error)
+	// History returns one page of the audit trail of a task, newest first,
+	// and its length. Tasks in the trash keep their history.
+	History(ctx context.Context, workspaceID, id uuid.UUID, limit, offset int) ([]models.ActivityEvent, int64, error)
+	// Trash returns one page of the workspace's tasks in the trash, most
+	// recently deleted first, and how many there are
+	Trash(ctx context.Context, workspaceID uuid.UUID, limit, offset int) ([]models.Task, int64, error)
+
+	Create(ctx context.Context, actor Actor, task *models.Task) error
+	// Update applies update to a task and returns the task as updated, and
+	// the next occurrence it created if any
+	Update(ctx context.Context, actor Actor, id uuid.UUID, update TaskUpdate) (*models.Task, *models.Task, error)
+	// Revert puts the fields of a task back to how they were in the snapshot
+	// of one of its versions, keeping the state it replaces as a new version.
+	// Parent, recurrence and series are left alone since they tie the task to
+	// other tasks.
+	Revert(ctx context.Context, actor Actor, id uuid.UUID, snapshot models.TaskSnapshot) (*models.Task, error)
+	// Delete moves a task and its subtasks to the trash, returning how many were moved
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) (int64, error)
+	// Restore brings a task back from the trash together with the subtasks
+	// deleted along with it, returning the task and how many were restored
+	Restore(ctx context.Context, actor Actor, id uuid.UUID) (*models.Task, int64, error)
+	// Purge permanently deletes a task in the trash
+	Purge(ctx context.Context, actor Actor, id uuid.UUID) error
+	// EmptyTrash permanently deletes every task in the trash, returning how many were deleted
+	EmptyTrash(ctx context.Context, actor Actor) (int64, error)
+
+	// CreateChecklistItem adds an item to a task's checklist. With atEnd it
+	// goes after the last item whatever its Position.
+	CreateChecklistItem(ctx context.Context, actor Actor, item *models.ChecklistItem, atEnd bool) error
+	UpdateChecklistItem(ctx context.Context, actor Actor, id uuid.UUID, update ChecklistItemUpdate) (*models.ChecklistItem, error)
+	DeleteChecklistItem(ctx context.Context, actor Actor, id uuid.UUID) error
+	// CreateDependency makes a task blocked by another task of the workspace,
+	// returning ErrNotFound when there is no such task. Existing dependencies
+	// are left as they are.
+	CreateDependency(ctx context.Context, actor Actor, dependency *models.TaskDependency) error
+	DeleteDependency(ctx context.Context, actor Actor, dependency models.TaskDependency) error
+
+	// Stats counts the workspace's tasks; topLevel leaves subtasks out
+	Stats(ctx context.Context, workspaceID uuid.UUID, topLevel bool) (*TaskStats, error)
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TechnologyKey is the case- and whitespace-insensitive form names and aliases are matched on
+func TechnologyKey(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
+}
+
+// FindTechnology looks a value up by name, slug or alias in a workspace's registry
+func FindTechnology(db *gorm.DB, workspaceID uuid.UUID, value string) (*models.Technology, error) {
+	key := TechnologyKey(value)
+	var technology models.Technology
+	err := db.Where("workspace_id = ?", workspaceID).
+		Where("LOWER(name) = ? OR slug = ? OR ? = ANY(aliases)", key, key, key).
+		First(&technology).Error
+	if err != nil {
+		return nil, err
+	}
+	return &technology, nil
+}
+
+// NormalizeTechnology maps a free-text technology to its canonical name.
+// Values that are not registered are returned trimmed.
+func NormalizeTechnology(db *gorm.DB, workspaceID uuid.UUID, value string) (string, error) {
+	trimmed := strings.Join(strings.Fields(value), " ")
+	technology, err := FindTechnology(db, workspaceID, trimmed)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return trimmed, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return technology.Name, nil
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+
+	"diary-backend/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ViewRepository stores saved views. An actor sees the views of their
+// workspace they created and those shared with it.
+type ViewRepository interface {
+	// List returns the views the actor sees by name, only those of
+	// entityType when it is set
+	List(ctx context.Context, actor Actor, entityType string) ([]models.SavedView, error)
+	// Get returns a view the actor sees
+	Get(ctx context.Context, actor Actor, id uuid.UUID) (*models.SavedView, error)
+
+	Create(ctx context.Context, actor Actor, view *models.SavedView) error
+	// Update saves every field of a view
+	Update(ctx context.Context, actor Actor, view *models.SavedView) error
+	Delete(ctx context.Context, actor Actor, id uuid.UUID) error
+}
@@ -1,51 +1,224 @@
 package routes
 
 import (
+	"diary-backend/internal/auth"
 	"diary-backend/internal/handlers"
 	"diary-backend/internal/middleware"
+	"diary-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine, corsOrigins string) {
+func SetupRoutes(router *gin.Engine, corsOrigins string, issuer *auth.TokenIssuer, repos repository.Repositories, health *handlers.HealthHandler) {
 	// Add CORS middleware
 	router.Use(middleware.CORS(corsOrigins))
 
+	handlers.SetTokenIssuer(issuer)
+	requireAuth := middleware.RequireAuth(issuer)
+
+	taskHandler := handlers.NewTaskHandler(repos.Tasks)
+	resourceHandler := handlers.NewResourceHandler(repos.Resources)
+	searchHandler := handlers.NewSearchHandler(repos.Tasks, repos.Resources)
+	viewHandler := handlers.NewViewHandler(repos.Views, repos.Tasks, repos.Resources)
+
 	// API version 1
 	v1 := router.Group("/api/v1")
 	{
+		// Account routes
+		accounts := v1.Group("/auth")
+		{
+			accounts.POST("/register", handlers.Register)             // POST /api/v1/auth/register
+			accounts.POST("/login", handlers.Login)                   // POST /api/v1/auth/login
+			accounts.POST("/refresh", handlers.RefreshToken)          // POST /api/v1/auth/refresh
+			accounts.POST("/logout", handlers.Logout)                 // POST /api/v1/auth/logout
+			accounts.GET("/me", requireAuth, handlers.GetCurrentUser) // GET /api/v1/auth/me
+		}
+
+		// Every route registered below requires an access token or a
+		// personal access token granted the scope the route declares
+		v1.Use(requireAuth)
+
+		readTasks, writeTasks := middleware.RequireScope(auth.ScopeTasksRead), middleware.RequireScope(auth.ScopeTasksWrite)
+		readProjects, writeProjects := middleware.RequireScope(auth.ScopeProjectsRead), middleware.RequireScope(auth.ScopeProjectsWrite)
+		readResources, writeResources := middleware.RequireScope(auth.ScopeResourcesRead), middleware.RequireScope(auth.ScopeResourcesWrite)
+		readTechnologies, writeTechnologies := middleware.RequireScope(auth.ScopeTechnologiesRead), middleware.RequireScope(auth.ScopeTechnologiesWrite)
+		readGoals, writeGoals := middleware.RequireScope(auth.ScopeGoalsRead), middleware.RequireScope(auth.ScopeGoalsWrite)
+		readActivity := middleware.RequireScope(auth.ScopeActivityRead)
+		readViews, writeViews := middleware.RequireScope(auth.ScopeViewsRead), middleware.RequireScope(auth.ScopeViewsWrite)
+
+		// Personal access tokens are managed from a login session only
+		tokens := v1.Group("/tokens", middleware.RequireLogin())
+		{
+			tokens.GET("", handlers.GetPersonalAccessTokens)          // GET /api/v1/tokens
+			tokens.POST("", handlers.CreatePersonalAccessToken)       // POST /api/v1/tokens
+			tokens.DELETE("/:id", handlers.RevokePersonalAccessToken) // DELETE /api/v1/tokens/:id
+		}
+
+		// Workspaces, their members and invitations
+		workspaces := v1.Group("/workspaces", middleware.RequireLogin())
+		{
+			workspaces.GET("", handlers.GetWorkspaces)                                              // GET /api/v1/workspaces
+			workspaces.GET("/:id", handlers.GetWorkspace)                                           // GET /api/v1/workspaces/:id
+			workspaces.POST("", handlers.CreateWorkspace)                                           // POST /api/v1/workspaces
+			workspaces.PUT("/:id", handlers.UpdateWorkspace)                                        // PUT /api/v1/workspaces/:id
+			workspaces.DELETE("/:id", handlers.DeleteWorkspace)                                     // DELETE /api/v1/workspaces/:id
+			workspaces.GET("/:id/members", handlers.GetWorkspaceMembers)                            // GET /api/v1/workspaces/:id/members
+			workspaces.PUT("/:id/members/:userId", handlers.UpdateWorkspaceMember)                  // PUT /api/v1/workspaces/:id/members/:userId
+			workspaces.DELETE("/:id/members/:userId", handlers.RemoveWorkspaceMember)               // DELETE /api/v1/workspaces/:id/members/:userId
+			workspaces.GET("/:id/invitations", handlers.GetWorkspaceInvitations)                    // GET /api/v1/workspaces/:id/invitations
+			workspaces.POST("/:id/invitations", handlers.InviteWorkspaceMember)                     // POST /api/v1/workspaces/:id/invitations
+			workspaces.DELETE("/:id/invitations/:invitationId", handlers.DeleteWorkspaceInvitation) // DELETE /api/v1/workspaces/:id/invitations/:invitationId
+		}
+
+		// Invitations addressed to the current user
+		invitations := v1.Group("/invitations", middleware.RequireLogin())
+		{
+			invitations.GET("", handlers.GetMyInvitations)             // GET /api/v1/invitations
+			invitations.POST("/:id/accept", handlers.AcceptInvitation) // POST /api/v1/invitations/:id/accept
+			invitations.DELETE("/:id", handlers.DeclineInvitation)     // DELETE /api/v1/invitations/:id
+		}
+
+		// Everything below belongs to the workspace selected by the
+		// X-Workspace-ID header, the user's personal workspace by default.
+		// Viewers are limited to read requests.
+		scoped := v1.Group("", middleware.RequireWorkspace())
+
 		// Task routes
-		tasks := v1.Group("/tasks")
+		tasks := scoped.Group("/tasks")
 		{
-			tasks.GET("", handlers.GetTasks)           // GET /api/v1/tasks
-			tasks.GET("/:id", handlers.GetTaskByID)    // GET /api/v1/tasks/:id
-			tasks.POST("", handlers.CreateTask)        // POST /api/v1/tasks
-			tasks.PUT("/:id", handlers.UpdateTask)     // PUT /api/v1/tasks/:id
-			tasks.DELETE("/:id", handlers.DeleteTask)  // DELETE /api/v1/tasks/:id
-			tasks.GET("/stats", handlers.GetTaskStats) // GET /api/v1/tasks/stats
+			tasks.GET("", readTasks, taskHandler.GetTasks)                   // GET /api/v1/tasks
+			tasks.GET("/:id", readTasks, taskHandler.GetTaskByID)            // GET /api/v1/tasks/:id
+			tasks.POST("", writeTasks, taskHandler.CreateTask)               // POST /api/v1/tasks
+			tasks.PUT("/:id", writeTasks, taskHandler.UpdateTask)            // PUT /api/v1/tasks/:id
+			tasks.DELETE("/:id", writeTasks, taskHandler.DeleteTask)         // DELETE /api/v1/tasks/:id
+			tasks.GET("/stats", readTasks, taskHandler.GetTaskStats)         // GET /api/v1/tasks/stats
+			tasks.GET("/:id/history", readTasks, taskHandler.GetTaskHistory) // GET /api/v1/tasks/:id/history
+
+			// Versions: earlier states of a task, which it can be reverted to
+			tasks.GET("/:id/versions", readTasks, taskHandler.GetTaskVersions)               // GET /api/v1/tasks/:id/versions
+			tasks.GET("/:id/versions/:n", readTasks, taskHandler.GetTaskVersion)             // GET /api/v1/tasks/:id/versions/:n
+			tasks.POST("/:id/versions/:n/revert", writeTasks, taskHandler.RevertTaskVersion) // POST /api/v1/tasks/:id/versions/:n/revert
+
+			// Trash: deleted tasks can be restored or purged for good
+			tasks.GET("/trash", readTasks, taskHandler.GetTaskTrash)        // GET /api/v1/tasks/trash
+			tasks.DELETE("/trash", writeTasks, taskHandler.EmptyTaskTrash)  // DELETE /api/v1/tasks/trash
+			tasks.DELETE("/trash/:id", writeTasks, taskHandler.PurgeTask)   // DELETE /api/v1/tasks/trash/:id
+			tasks.POST("/:id/restore", writeTasks, taskHandler.RestoreTask) // POST /api/v1/tasks/:id/restore
+
+			// Subtasks and checklist of a task
+			tasks.GET("/:id/subtasks", readTasks, taskHandler.GetSubtasks)                      // GET /api/v1/tasks/:id/subtasks
+			tasks.POST("/:id/subtasks", writeTasks, taskHandler.CreateSubtask)                  // POST /api/v1/tasks/:id/subtasks
+			tasks.GET("/:id/checklist", readTasks, taskHandler.GetChecklist)                    // GET /api/v1/tasks/:id/checklist
+			tasks.POST("/:id/checklist", writeTasks, taskHandler.CreateChecklistItem)           // POST /api/v1/tasks/:id/checklist
+			tasks.PUT("/:id/checklist/:itemId", writeTasks, taskHandler.UpdateChecklistItem)    // PUT /api/v1/tasks/:id/checklist/:itemId
+			tasks.DELETE("/:id/checklist/:itemId", writeTasks, taskHandler.DeleteChecklistItem) // DELETE /api/v1/tasks/:id/checklist/:itemId
+
+			// Dependencies between tasks
+			tasks.GET("/:id/dependencies", readTasks, taskHandler.GetTaskDependencies)                   // GET /api/v1/tasks/:id/dependencies
+			tasks.POST("/:id/dependencies", writeTasks, taskHandler.CreateTaskDependency)                // POST /api/v1/tasks/:id/dependencies
+			tasks.DELETE("/:id/dependencies/:dependsOnId", writeTasks, taskHandler.DeleteTaskDependency) // DELETE /api/v1/tasks/:id/dependencies/:dependsOnId
 		}
+
+		// Project routes
+		projects := scoped.Group("/projects")
+		{
+			projects.GET("", readProjects, handlers.GetProjects)                      // GET /api/v1/projects
+			projects.GET("/:id", readProjects, handlers.GetProjectByID)               // GET /api/v1/projects/:id
+			projects.POST("", writeProjects, handlers.CreateProject)                  // POST /api/v1/projects
+			projects.PUT("/:id", writeProjects, handlers.UpdateProject)               // PUT /api/v1/projects/:id
+			projects.DELETE("/:id", writeProjects, handlers.DeleteProject)            // DELETE /api/v1/projects/:id
+			projects.POST("/:id/archive", writeProjects, handlers.ArchiveProject)     // POST /api/v1/projects/:id/archive
+			projects.POST("/:id/unarchive", writeProjects, handlers.UnarchiveProject) // POST /api/v1/projects/:id/unarchive
+			projects.GET("/:id/graph", readProjects, taskHandler.GetProjectGraph)     // GET /api/v1/projects/:id/graph
+			projects.GET("/:id/history", readProjects, handlers.GetProjectHistory)    // GET /api/v1/projects/:id/history
+		}
+
 		// Learning Resources routes
-		resources := v1.Group("/resources")
+		resources := scoped.Group("/resources")
 		{
-			resources.GET("", handlers.GetResources)                      // GET /api/v1/resources
-			resources.GET("/:id", handlers.GetResourceByID)               // GET /api/v1/resources/:id
-			resources.POST("", handlers.CreateResource)                   // POST /api/v1/resources
-			resources.PUT("/:id", handlers.UpdateResource)                // PUT /api/v1/resources/:id
-			resources.DELETE("/:id", handlers.DeleteResource)             // DELETE /api/v1/resources/:id
-			resources.PATCH("/:id/status", handlers.UpdateResourceStatus) // PATCH /api/v1/resources/:id/status
-			resources.PATCH("/:id/rating", handlers.UpdateResourceRating) // PATCH /api/v1/resources/:id/rating
-			resources.GET("/stats", handlers.GetResourceStats)            // GET /api/v1/resources/stats
-			resources.GET("/technologies", handlers.GetTechnologies)      // GET /api/v1/resources/technologies
-			resources.POST("/import-url", handlers.ImportFromURL)         // POST /api/v1/resources/import-url
+			resources.GET("", readResources, resourceHandler.GetResources)                       // GET /api/v1/resources
+			resources.GET("/:id", readResources, resourceHandler.GetResourceByID)                // GET /api/v1/resources/:id
+			resources.POST("", writeResources, resourceHandler.CreateResource)                   // POST /api/v1/resources
+			resources.PUT("/:id", writeResources, resourceHandler.UpdateResource)                // PUT /api/v1/resources/:id
+			resources.DELETE("/:id", writeResources, resourceHandler.DeleteResource)             // DELETE /api/v1/resources/:id
+			resources.PATCH("/:id/status", writeResources, resourceHandler.UpdateResourceStatus) // PATCH /api/v1/resources/:id/status
+			resources.PATCH("/:id/rating", writeResources, resourceHandler.UpdateResourceRating) // PATCH /api/v1/resources/:id/rating
+			resources.GET("/stats", readResources, resourceHandler.GetResourceStats)             // GET /api/v1/resources/stats
+			resources.GET("/technologies", readResources, handlers.GetTechnologies)              // GET /api/v1/resources/technologies
+			resources.POST("/import-url", writeResources, resourceHandler.ImportFromURL)         // POST /api/v1/resources/import-url
+			resources.GET("/:id/history", readResources, resourceHandler.GetResourceHistory)     // GET /api/v1/resources/:id/history
+
+			// Note versions: earlier notes of a resource, which can be restored
+			resources.GET("/:id/notes/versions", readResources, resourceHandler.GetResourceNoteVersions)               // GET /api/v1/resources/:id/notes/versions
+			resources.GET("/:id/notes/versions/:n", readResources, resourceHandler.GetResourceNoteVersion)             // GET /api/v1/resources/:id/notes/versions/:n
+			resources.POST("/:id/notes/versions/:n/revert", writeResources, resourceHandler.RevertResourceNoteVersion) // POST /api/v1/resources/:id/notes/versions/:n/revert
+
+			// Trash: deleted resources can be restored or purged for good
+			resources.GET("/trash", readResources, resourceHandler.GetResourceTrash)        // GET /api/v1/resources/trash
+			resources.DELETE("/trash", writeResources, resourceHandler.EmptyResourceTrash)  // DELETE /api/v1/resources/trash
+			resources.DELETE("/trash/:id", writeResources, resourceHandler.PurgeResource)   // DELETE /api/v1/resources/trash/:id
+			resources.POST("/:id/restore", writeResources, resourceHandler.RestoreResource) // POST /api/v1/resources/:id/restore
+
+			// Learning sessions of a resource
+			resources.GET("/:id/sessions", readResources, resourceHandler.GetResourceSessions)          // GET /api/v1/resources/:id/sessions
+			resources.POST("/:id/sessions", writeResources, resourceHandler.CreateSession)              // POST /api/v1/resources/:id/sessions
+			resources.GET("/:id/sessions/:sessionId", readResources, resourceHandler.GetSessionByID)    // GET /api/v1/resources/:id/sessions/:sessionId
+			resources.PUT("/:id/sessions/:sessionId", writeResources, resourceHandler.UpdateSession)    // PUT /api/v1/resources/:id/sessions/:sessionId
+			resources.DELETE("/:id/sessions/:sessionId", writeResources, resourceHandler.DeleteSession) // DELETE /api/v1/resources/:id/sessions/:sessionId
 		}
 
+		// Technology registry routes
+		technologies := scoped.Group("/technologies")
+		{
+			technologies.GET("", readTechnologies, handlers.GetTechnologyRegistry)            // GET /api/v1/technologies
+			technologies.POST("", writeTechnologies, handlers.CreateTechnology)               // POST /api/v1/technologies
+			technologies.PUT("/:id", writeTechnologies, handlers.UpdateTechnology)            // PUT /api/v1/technologies/:id
+			technologies.DELETE("/:id", writeTechnologies, handlers.DeleteTechnology)         // DELETE /api/v1/technologies/:id
+			technologies.POST("/merge", writeTechnologies, handlers.MergeTechnologies)        // POST /api/v1/technologies/merge
+			technologies.GET("/:id/history", readTechnologies, handlers.GetTechnologyHistory) // GET /api/v1/technologies/:id/history
+		}
+
+		// Ranked full-text search across tasks and resources; results are
+		// limited to the types the token can read
+		scoped.GET("/search", searchHandler.Search) // GET /api/v1/search
+
+		// Saved views: named task and resource lists, private to their
+		// creator unless shared with the workspace. Running a view also
+		// needs the read scope of the list it shows.
+		views := scoped.Group("/views")
+		{
+			views.GET("", readViews, viewHandler.GetViews)               // GET /api/v1/views
+			views.GET("/:id", readViews, viewHandler.GetViewByID)        // GET /api/v1/views/:id
+			views.POST("", writeViews, viewHandler.CreateView)           // POST /api/v1/views
+			views.PUT("/:id", writeViews, viewHandler.UpdateView)        // PUT /api/v1/views/:id
+			views.DELETE("/:id", writeViews, viewHandler.DeleteView)     // DELETE /api/v1/views/:id
+			views.GET("/:id/items", readViews, viewHandler.GetViewItems) // GET /api/v1/views/:id/items
+		}
+
+		// Learning sessions across all resources
+		scoped.GET("/sessions", readResources, resourceHandler.GetSessions) // GET /api/v1/sessions
+
+		// Audit trail of every change made in the workspace
+		scoped.GET("/activity", readActivity, handlers.GetActivity) // GET /api/v1/activity
+
+		// Learning goal routes
+		goals := scoped.Group("/goals")
+		{
+			goals.GET("", readGoals, handlers.GetGoals)                                         // GET /api/v1/goals
+			goals.GET("/:id", readGoals, handlers.GetGoalByID)                                  // GET /api/v1/goals/:id
+			goals.POST("", writeGoals, handlers.CreateGoal)                                     // POST /api/v1/goals
+			goals.PUT("/:id", writeGoals, handlers.UpdateGoal)                                  // PUT /api/v1/goals/:id
+			goals.DELETE("/:id", writeGoals, handlers.DeleteGoal)                               // DELETE /api/v1/goals/:id
+			goals.POST("/:id/resources", writeGoals, handlers.LinkGoalResources)                // POST /api/v1/goals/:id/resources
+			goals.DELETE("/:id/resources/:resourceId", writeGoals, handlers.UnlinkGoalResource) // DELETE /api/v1/goals/:id/resources/:resourceId
+			goals.GET("/:id/history", readGoals, handlers.GetGoalHistory)                       // GET /api/v1/goals/:id/history
+		}
 	}
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"service": "diary-backend",
-		})
-	})
+	// Health probes: live only says the process is up, ready checks the
+	// database and schema. /health predates the split and answers like ready.
+	router.GET("/health/live", health.Live)     // GET /health/live
+	router.GET("/health/ready", health.Ready)   // GET /health/ready
+	router.GET("/health", health.Ready)         // GET /health
+	router.GET("/version", handlers.GetVersion) // GET /version
 }
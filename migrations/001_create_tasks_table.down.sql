@@ -0,0 +1,3 @@
+-- Drop tasks table and the shared updated_at function
+DROP TABLE IF EXISTS tasks;
+DROP FUNCTION IF EXISTS update_updated_at_column();
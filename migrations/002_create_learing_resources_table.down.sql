@@ -0,0 +1,5 @@
+-- Drop learning tables
+DROP TABLE IF EXISTS learning_goals;
+DROP TABLE IF EXISTS learning_sessions;
+DROP TABLE IF EXISTS learning_resources;
+DROP FUNCTION IF EXISTS set_completed_at();
@@ -42,20 +42,20 @@ CREATE TABLE IF NOT EXISTS learning_goals (
 );
 
 -- Create indexes for better query performance
-CREATE INDEX idx_learning_resources_technology ON learning_resources(technology);
-CREATE INDEX idx_learning_resources_type ON learning_resources(type);
-CREATE INDEX idx_learning_resources_status ON learning_resources(status);
-CREATE INDEX idx_learning_resources_priority ON learning_resources(priority);
-CREATE INDEX idx_learning_resources_rating ON learning_resources(rating);
-CREATE INDEX idx_learning_resources_created_at ON learning_resources(created_at);
-CREATE INDEX idx_learning_resources_tags ON learning_resources USING GIN(tags);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_technology ON learning_resources(technology);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_type ON learning_resources(type);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_status ON learning_resources(status);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_priority ON learning_resources(priority);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_rating ON learning_resources(rating);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_created_at ON learning_resources(created_at);
+CREATE INDEX IF NOT EXISTS idx_learning_resources_tags ON learning_resources USING GIN(tags);
 
-CREATE INDEX idx_learning_sessions_resource_id ON learning_sessions(resource_id);
-CREATE INDEX idx_learning_sessions_date ON learning_sessions(session_date);
+CREATE INDEX IF NOT EXISTS idx_learning_sessions_resource_id ON learning_sessions(resource_id);
+CREATE INDEX IF NOT EXISTS idx_learning_sessions_date ON learning_sessions(session_date);
 
-CREATE INDEX idx_learning_goals_technology ON learning_goals(technology);
-CREATE INDEX idx_learning_goals_status ON learning_goals(status);
-CREATE INDEX idx_learning_goals_target_date ON learning_goals(target_date);
+CREATE INDEX IF NOT EXISTS idx_learning_goals_technology ON learning_goals(technology);
+CREATE INDEX IF NOT EXISTS idx_learning_goals_status ON learning_goals(status);
+CREATE INDEX IF NOT EXISTS idx_learning_goals_target_date ON learning_goals(target_date);
 
 -- Create function to update updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -67,14 +67,16 @@ END;
 $$ language 'plpgsql';
 
 -- Create triggers to automatically update updated_at
-CREATE TRIGGER update_learning_resources_updated_at 
-    BEFORE UPDATE ON learning_resources 
-    FOR EACH ROW 
+DROP TRIGGER IF EXISTS update_learning_resources_updated_at ON learning_resources;
+CREATE TRIGGER update_learning_resources_updated_at
+    BEFORE UPDATE ON learning_resources
+    FOR EACH ROW
     EXECUTE FUNCTION update_updated_at_column();
 
-CREATE TRIGGER update_learning_goals_updated_at 
-    BEFORE UPDATE ON learning_goals 
-    FOR EACH ROW 
+DROP TRIGGER IF EXISTS update_learning_goals_updated_at ON learning_goals;
+CREATE TRIGGER update_learning_goals_updated_at
+    BEFORE UPDATE ON learning_goals
+    FOR EACH ROW
     EXECUTE FUNCTION update_updated_at_column();
 
 -- Function to automatically set completed_at when status changes to 'completed'
@@ -91,6 +93,7 @@ BEGIN
 END;
 $$ language 'plpgsql';
 
+DROP TRIGGER IF EXISTS set_learning_resource_completed_at ON learning_resources;
 CREATE TRIGGER set_learning_resource_completed_at
     BEFORE UPDATE ON learning_resources
     FOR EACH ROW
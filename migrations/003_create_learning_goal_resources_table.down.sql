@@ -0,0 +1,2 @@
+-- Drop learning_goal_resources join table
+DROP TABLE IF EXISTS learning_goal_resources;
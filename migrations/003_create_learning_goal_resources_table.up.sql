@@ -0,0 +1,10 @@
+-- Create learning_goal_resources join table linking goals to the resources that make them up
+CREATE TABLE IF NOT EXISTS learning_goal_resources (
+    goal_id UUID NOT NULL REFERENCES learning_goals(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL REFERENCES learning_resources(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (goal_id, resource_id)
+);
+
+-- Create index for looking up the goals of a resource
+CREATE INDEX IF NOT EXISTS idx_learning_goal_resources_resource_id ON learning_goal_resources(resource_id);
@@ -0,0 +1,4 @@
+-- Unlink tasks from projects and drop projects table; tasks keep their project_id values
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_tasks_project;
+DROP INDEX IF EXISTS idx_tasks_project_id;
+DROP TABLE IF EXISTS projects;
@@ -0,0 +1,32 @@
+-- Create projects table
+CREATE TABLE IF NOT EXISTS projects (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    color VARCHAR(20),
+    archived_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for better query performance
+CREATE INDEX IF NOT EXISTS idx_projects_archived_at ON projects(archived_at);
+CREATE INDEX IF NOT EXISTS idx_tasks_project_id ON tasks(project_id);
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_projects_updated_at ON projects;
+CREATE TRIGGER update_projects_updated_at
+    BEFORE UPDATE ON projects
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Detach tasks that reference projects which never existed
+UPDATE tasks SET project_id = NULL
+WHERE project_id IS NOT NULL
+  AND project_id NOT IN (SELECT id FROM projects);
+
+-- Link tasks to their project; deleting a project keeps its tasks
+ALTER TABLE tasks DROP CONSTRAINT IF EXISTS fk_tasks_project;
+ALTER TABLE tasks
+    ADD CONSTRAINT fk_tasks_project
+    FOREIGN KEY (project_id) REFERENCES projects(id) ON DELETE SET NULL;
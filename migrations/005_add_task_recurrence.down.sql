@@ -0,0 +1,6 @@
+-- Drop recurrence columns from tasks; occurrences already generated stay as plain tasks
+DROP INDEX IF EXISTS idx_tasks_series_due_date;
+DROP INDEX IF EXISTS idx_tasks_series_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS series_start;
+ALTER TABLE tasks DROP COLUMN IF EXISTS series_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS recurrence_rule;
@@ -0,0 +1,12 @@
+-- Add recurrence columns to tasks. Every occurrence of a recurring task
+-- shares the series_id of the first one and the series_start (DTSTART) the
+-- rule is evaluated from.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_rule TEXT;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id UUID;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_start DATE;
+
+-- Create index for looking up the occurrences of a series
+CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks(series_id);
+
+-- Each series has at most one task per due date, so the next occurrence is never generated twice
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_series_due_date ON tasks(series_id, due_date) WHERE series_id IS NOT NULL;
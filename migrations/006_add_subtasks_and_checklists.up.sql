@@ -0,0 +1,24 @@
+-- Add parent/child relationship between tasks; deleting a parent deletes its subtasks
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id) ON DELETE CASCADE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_parent_id ON tasks(parent_id);
+
+-- Create task_checklist_items table for lightweight checklists inside a task
+CREATE TABLE IF NOT EXISTS task_checklist_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    title VARCHAR(255) NOT NULL,
+    done BOOLEAN DEFAULT FALSE,
+    position INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_checklist_items_task_id ON task_checklist_items(task_id, position);
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_task_checklist_items_updated_at ON task_checklist_items;
+CREATE TRIGGER update_task_checklist_items_updated_at
+    BEFORE UPDATE ON task_checklist_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Drop task_dependencies table
+DROP TABLE IF EXISTS task_dependencies;
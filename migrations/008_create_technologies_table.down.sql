@@ -0,0 +1,2 @@
+-- Drop technologies table; resources and goals keep their technology names
+DROP TABLE IF EXISTS technologies;
@@ -0,0 +1,43 @@
+-- Create technologies table: the canonical names resources and goals are normalized to
+CREATE TABLE IF NOT EXISTS technologies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(100) NOT NULL UNIQUE,
+    aliases TEXT[] NOT NULL DEFAULT '{}', -- lower-cased alternative spellings
+    parent_id UUID REFERENCES technologies(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for name and alias lookups
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_name_lower ON technologies(LOWER(name));
+CREATE INDEX IF NOT EXISTS idx_technologies_aliases ON technologies USING GIN(aliases);
+CREATE INDEX IF NOT EXISTS idx_technologies_parent_id ON technologies(parent_id);
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_technologies_updated_at ON technologies;
+CREATE TRIGGER update_technologies_updated_at
+    BEFORE UPDATE ON technologies
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Seed common technologies
+INSERT INTO technologies (name, slug, aliases) VALUES
+    ('Go', 'go', '{golang,go-lang}'),
+    ('JavaScript', 'javascript', '{js,ecmascript,es6}'),
+    ('TypeScript', 'typescript', '{ts}'),
+    ('Python', 'python', '{py,python3}'),
+    ('Rust', 'rust', '{rust-lang,rustlang}'),
+    ('Java', 'java', '{}'),
+    ('Kotlin', 'kotlin', '{kt}'),
+    ('PostgreSQL', 'postgresql', '{postgres,psql,pg}'),
+    ('Docker', 'docker', '{}'),
+    ('Kubernetes', 'kubernetes', '{k8s,kube}')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO technologies (name, slug, aliases, parent_id) VALUES
+    ('React', 'react', '{reactjs,react.js}', (SELECT id FROM technologies WHERE slug = 'javascript')),
+    ('Vue', 'vue', '{vuejs,vue.js}', (SELECT id FROM technologies WHERE slug = 'javascript')),
+    ('Node.js', 'nodejs', '{node,node.js}', (SELECT id FROM technologies WHERE slug = 'javascript')),
+    ('Django', 'django', '{}', (SELECT id FROM technologies WHERE slug = 'python'))
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- Purge the trash, since without deleted_at its rows would show up again,
+-- and drop the soft delete columns
+DELETE FROM tasks WHERE deleted_at IS NOT NULL;
+DELETE FROM learning_resources WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_tasks_deleted_at;
+DROP INDEX IF EXISTS idx_learning_resources_deleted_at;
+ALTER TABLE tasks DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE learning_resources DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+-- Soft delete for tasks and learning resources: deleted rows stay in the trash until purged
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE learning_resources ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Create partial indexes for listing the trash and finding expired rows to purge
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_learning_resources_deleted_at ON learning_resources(deleted_at) WHERE deleted_at IS NOT NULL;
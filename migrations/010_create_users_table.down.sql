@@ -0,0 +1,28 @@
+-- Technology names and slugs become unique across the registry again. Where
+-- users had the same technology only the oldest is kept.
+DELETE FROM technologies WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY slug ORDER BY created_at, id) AS n FROM technologies
+    ) ranked WHERE n > 1
+);
+DELETE FROM technologies WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (PARTITION BY LOWER(name) ORDER BY created_at, id) AS n FROM technologies
+    ) ranked WHERE n > 1
+);
+DROP INDEX IF EXISTS idx_technologies_user_slug;
+DROP INDEX IF EXISTS idx_technologies_user_name_lower;
+ALTER TABLE technologies DROP COLUMN IF EXISTS user_id;
+ALTER TABLE technologies DROP CONSTRAINT IF EXISTS technologies_slug_key;
+ALTER TABLE technologies ADD CONSTRAINT technologies_slug_key UNIQUE (slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_name_lower ON technologies(LOWER(name));
+
+-- Drop the owner of every owned table
+ALTER TABLE projects DROP COLUMN IF EXISTS user_id;
+ALTER TABLE learning_goals DROP COLUMN IF EXISTS user_id;
+ALTER TABLE learning_resources DROP COLUMN IF EXISTS user_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS user_id;
+
+-- Drop users and their refresh tokens
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS users;
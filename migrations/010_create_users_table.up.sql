@@ -0,0 +1,67 @@
+-- Create users table
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    password_hash TEXT NOT NULL,
+    name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_users_updated_at ON users;
+CREATE TRIGGER update_users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create refresh_tokens table: refresh tokens are rotated on use and revoked on logout
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens(user_id);
+
+-- Bootstrap user owning everything created before accounts existed. It has no
+-- password and cannot log in until claimed with BOOTSTRAP_USER_EMAIL and
+-- BOOTSTRAP_USER_PASSWORD.
+INSERT INTO users (id, email, password_hash, name)
+VALUES ('00000000-0000-0000-0000-000000000001', 'bootstrap@localhost', '', 'Bootstrap user')
+ON CONFLICT DO NOTHING;
+
+-- Give every owned table a user_id and assign existing rows to the bootstrap user
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE tasks SET user_id = '00000000-0000-0000-0000-000000000001' WHERE user_id IS NULL;
+ALTER TABLE tasks ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id);
+
+ALTER TABLE learning_resources ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE learning_resources SET user_id = '00000000-0000-0000-0000-000000000001' WHERE user_id IS NULL;
+ALTER TABLE learning_resources ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_learning_resources_user_id ON learning_resources(user_id);
+
+ALTER TABLE learning_goals ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE learning_goals SET user_id = '00000000-0000-0000-0000-000000000001' WHERE user_id IS NULL;
+ALTER TABLE learning_goals ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_learning_goals_user_id ON learning_goals(user_id);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE projects SET user_id = '00000000-0000-0000-0000-000000000001' WHERE user_id IS NULL;
+ALTER TABLE projects ALTER COLUMN user_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id);
+
+ALTER TABLE technologies ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE technologies SET user_id = '00000000-0000-0000-0000-000000000001' WHERE user_id IS NULL;
+ALTER TABLE technologies ALTER COLUMN user_id SET NOT NULL;
+
+-- Technology names and slugs are unique per user
+ALTER TABLE technologies DROP CONSTRAINT IF EXISTS technologies_slug_key;
+DROP INDEX IF EXISTS idx_technologies_name_lower;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_user_slug ON technologies(user_id, slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_user_name_lower ON technologies(user_id, LOWER(name));
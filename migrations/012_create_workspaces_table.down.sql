@@ -0,0 +1,41 @@
+-- Technologies go back to the user who created their workspace. Where that
+-- leaves a user with the same technology twice, the one from their personal
+-- workspace, or else the oldest, is kept.
+ALTER TABLE technologies ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+UPDATE technologies t SET user_id = w.created_by FROM workspaces w
+WHERE t.workspace_id = w.id AND t.user_id IS NULL;
+
+DELETE FROM technologies WHERE id IN (
+    SELECT id FROM (
+        SELECT t.id, ROW_NUMBER() OVER (
+            PARTITION BY t.user_id, t.slug ORDER BY w.personal DESC, t.created_at, t.id
+        ) AS n
+        FROM technologies t JOIN workspaces w ON w.id = t.workspace_id
+    ) ranked WHERE n > 1
+);
+DELETE FROM technologies WHERE id IN (
+    SELECT id FROM (
+        SELECT t.id, ROW_NUMBER() OVER (
+            PARTITION BY t.user_id, LOWER(t.name) ORDER BY w.personal DESC, t.created_at, t.id
+        ) AS n
+        FROM technologies t JOIN workspaces w ON w.id = t.workspace_id
+    ) ranked WHERE n > 1
+);
+
+ALTER TABLE technologies ALTER COLUMN user_id SET NOT NULL;
+DROP INDEX IF EXISTS idx_technologies_workspace_slug;
+DROP INDEX IF EXISTS idx_technologies_workspace_name_lower;
+ALTER TABLE technologies DROP COLUMN IF EXISTS workspace_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_user_slug ON technologies(user_id, slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_user_name_lower ON technologies(user_id, LOWER(name));
+
+-- Everything else stays with the user who created it
+ALTER TABLE projects DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE learning_goals DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE learning_resources DROP COLUMN IF EXISTS workspace_id;
+ALTER TABLE tasks DROP COLUMN IF EXISTS workspace_id;
+
+-- Drop workspace tables
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
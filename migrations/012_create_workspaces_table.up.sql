@@ -0,0 +1,102 @@
+-- Create workspaces table: a workspace owns tasks, projects, learning resources,
+-- goals and technologies and is shared by its members
+CREATE TABLE IF NOT EXISTS workspaces (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    personal BOOLEAN NOT NULL DEFAULT FALSE, -- the workspace used when a request selects none
+    created_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Every user has exactly one personal workspace
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspaces_personal ON workspaces(created_by) WHERE personal;
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_workspaces_updated_at ON workspaces;
+CREATE TRIGGER update_workspaces_updated_at
+    BEFORE UPDATE ON workspaces
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create workspace_members table
+CREATE TABLE IF NOT EXISTS workspace_members (
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user_id ON workspace_members(user_id);
+
+-- Create workspace_invitations table: an invitation is addressed to an email
+-- and turns into a membership when that user accepts it
+CREATE TABLE IF NOT EXISTS workspace_invitations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'editor', 'viewer')),
+    invited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_invitations_email ON workspace_invitations(workspace_id, LOWER(email));
+
+-- Give every existing user a personal workspace they own. The bootstrap user's
+-- workspace has a fixed ID like the user itself.
+INSERT INTO workspaces (id, name, personal, created_by)
+VALUES ('00000000-0000-0000-0000-000000000001', 'Personal', TRUE, '00000000-0000-0000-0000-000000000001')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO workspaces (name, personal, created_by)
+SELECT 'Personal', TRUE, u.id FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM workspaces w WHERE w.personal AND w.created_by = u.id);
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT w.id, w.created_by, 'owner' FROM workspaces w WHERE w.personal
+ON CONFLICT DO NOTHING;
+
+-- Move every owned row into its owner's personal workspace. user_id stays on
+-- tasks, projects, resources and goals to record who created them.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE tasks t SET workspace_id = w.id FROM workspaces w
+WHERE t.workspace_id IS NULL AND w.personal AND w.created_by = t.user_id;
+ALTER TABLE tasks ALTER COLUMN workspace_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_workspace_id ON tasks(workspace_id);
+
+ALTER TABLE learning_resources ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE learning_resources r SET workspace_id = w.id FROM workspaces w
+WHERE r.workspace_id IS NULL AND w.personal AND w.created_by = r.user_id;
+ALTER TABLE learning_resources ALTER COLUMN workspace_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_learning_resources_workspace_id ON learning_resources(workspace_id);
+
+ALTER TABLE learning_goals ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE learning_goals g SET workspace_id = w.id FROM workspaces w
+WHERE g.workspace_id IS NULL AND w.personal AND w.created_by = g.user_id;
+ALTER TABLE learning_goals ALTER COLUMN workspace_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_learning_goals_workspace_id ON learning_goals(workspace_id);
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+UPDATE projects p SET workspace_id = w.id FROM workspaces w
+WHERE p.workspace_id IS NULL AND w.personal AND w.created_by = p.user_id;
+ALTER TABLE projects ALTER COLUMN workspace_id SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_projects_workspace_id ON projects(workspace_id);
+
+-- The technology registry belongs to the workspace alone, so its user_id goes
+ALTER TABLE technologies ADD COLUMN IF NOT EXISTS workspace_id UUID REFERENCES workspaces(id) ON DELETE CASCADE;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'technologies' AND column_name = 'user_id') THEN
+        UPDATE technologies t SET workspace_id = w.id FROM workspaces w
+        WHERE t.workspace_id IS NULL AND w.personal AND w.created_by = t.user_id;
+    END IF;
+END $$;
+ALTER TABLE technologies ALTER COLUMN workspace_id SET NOT NULL;
+
+-- Technology names and slugs are unique per workspace
+DROP INDEX IF EXISTS idx_technologies_user_slug;
+DROP INDEX IF EXISTS idx_technologies_user_name_lower;
+ALTER TABLE technologies DROP COLUMN IF EXISTS user_id;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_workspace_slug ON technologies(workspace_id, slug);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_technologies_workspace_name_lower ON technologies(workspace_id, LOWER(name));
@@ -0,0 +1,3 @@
+-- Drop activity_events table and its append-only guard
+DROP TABLE IF EXISTS activity_events;
+DROP FUNCTION IF EXISTS prevent_activity_event_update();
@@ -0,0 +1,37 @@
+-- Create activity_events table: an append-only audit trail of every change
+-- made to a workspace's data
+CREATE TABLE IF NOT EXISTS activity_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}', -- field name -> {"from": ..., "to": ...}
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for the activity feed and per-entity history
+CREATE INDEX IF NOT EXISTS idx_activity_events_workspace_created ON activity_events(workspace_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_events_entity ON activity_events(entity_type, entity_id, created_at DESC);
+
+-- Events are never edited. Rows only go away with their workspace, or the
+-- actor reference with its user.
+CREATE OR REPLACE FUNCTION prevent_activity_event_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Clearing the actor when their user is deleted is the only change allowed
+    IF NEW.actor_id IS NULL
+        AND (NEW.id, NEW.workspace_id, NEW.entity_type, NEW.entity_id, NEW.action, NEW.changes, NEW.created_at)
+            IS NOT DISTINCT FROM (OLD.id, OLD.workspace_id, OLD.entity_type, OLD.entity_id, OLD.action, OLD.changes, OLD.created_at) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'activity events are append-only';
+END;
+$$ language 'plpgsql';
+
+DROP TRIGGER IF EXISTS prevent_activity_events_update ON activity_events;
+CREATE TRIGGER prevent_activity_events_update
+    BEFORE UPDATE ON activity_events
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_activity_event_update();
@@ -0,0 +1,3 @@
+-- Drop version tables
+DROP TABLE IF EXISTS resource_note_versions;
+DROP TABLE IF EXISTS task_versions;
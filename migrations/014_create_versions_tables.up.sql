@@ -0,0 +1,22 @@
+-- Create task_versions table: the state of a task before each change, so
+-- earlier versions can be read and reverted to
+CREATE TABLE IF NOT EXISTS task_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    snapshot JSONB NOT NULL,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (task_id, version)
+);
+
+-- Create resource_note_versions table: earlier notes of a resource
+CREATE TABLE IF NOT EXISTS resource_note_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_id UUID NOT NULL REFERENCES learning_resources(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (resource_id, version)
+);
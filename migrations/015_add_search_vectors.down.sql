@@ -0,0 +1,6 @@
+-- Drop search vectors
+DROP INDEX IF EXISTS idx_learning_resources_search_vector;
+ALTER TABLE learning_resources DROP COLUMN IF EXISTS search_vector;
+DROP INDEX IF EXISTS idx_tasks_search_vector;
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS search_tags(TEXT[]);
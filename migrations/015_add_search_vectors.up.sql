@@ -0,0 +1,25 @@
+-- Tags joined into one string for the search vectors. array_to_string is only
+-- STABLE, which generated columns do not accept; for text[] it cannot change.
+CREATE OR REPLACE FUNCTION search_tags(tags TEXT[])
+RETURNS TEXT AS $$
+    SELECT coalesce(array_to_string(tags, ' '), '')
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE;
+
+-- Full-text search over tasks: title ranks above tags above description
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', search_tags(tags)), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+-- Full-text search over learning resources, notes ranking lowest
+ALTER TABLE learning_resources ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', search_tags(tags)), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C') ||
+    setweight(to_tsvector('english', coalesce(notes, '')), 'D')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_learning_resources_search_vector ON learning_resources USING GIN (search_vector);
@@ -0,0 +1,25 @@
+-- Create saved_views table: a named task or resource list with its filters,
+-- sort and page size, private to its creator unless shared with the workspace
+CREATE TABLE IF NOT EXISTS saved_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('task', 'resource')),
+    filters JSONB NOT NULL DEFAULT '{}',
+    sort_by VARCHAR(50) NOT NULL DEFAULT '', -- empty for the list's default sort
+    sort_order VARCHAR(4) NOT NULL DEFAULT 'asc' CHECK (sort_order IN ('asc', 'desc')),
+    page_size INTEGER NOT NULL CHECK (page_size BETWEEN 1 AND 100),
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_workspace_user ON saved_views(workspace_id, user_id);
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_saved_views_updated_at ON saved_views;
+CREATE TRIGGER update_saved_views_updated_at
+    BEFORE UPDATE ON saved_views
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL migrations so the binaries can apply them.
+// Each version has a NNN_name.up.sql file and a NNN_name.down.sql file that
+// undoes it.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS